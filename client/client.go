@@ -36,7 +36,9 @@ type Client struct {
 func (c *Client) CommonHeaders(ctx context.Context) http.Header {
 	var headers = make(http.Header)
 	// TODO overwrote by context values
-	if len(c.Cfg.Token) > 0 {
+	if len(c.Cfg.APIKey) > 0 {
+		headers.Set("Authorization", "Bearer "+c.Cfg.APIKey)
+	} else if len(c.Cfg.Token) > 0 {
 		headers.Set("X-Auth-Token", c.Cfg.Token)
 	}
 	return headers
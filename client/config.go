@@ -32,6 +32,9 @@ type Config struct {
 	// TODO Expandable header not only token header
 	Token          string
 	CertKeyPWDPath string
+	// APIKey is the long-lived api key of a service account,
+	// it is sent as the bearer token and takes precedence over Token
+	APIKey string
 }
 
 func (cfg *Config) Merge() rest.URLClientOption {
@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+var (
+	ErrAPIKeyNotExist = errors.New("api key does not exist")
+)
+
+// APIKeyManager contains the api key CRUD
+type APIKeyManager interface {
+	CreateAPIKey(ctx context.Context, key *rbacframe.APIKey) error
+	GetAPIKey(ctx context.Context, id string) (*rbacframe.APIKey, error)
+	// ListAPIKey returns the keys of account, or all if account is empty
+	ListAPIKey(ctx context.Context, account string) ([]*rbacframe.APIKey, error)
+	DeleteAPIKey(ctx context.Context, account, id string) error
+	UpdateAPIKeyLastUsed(ctx context.Context, id, lastUsedTime string) error
+}
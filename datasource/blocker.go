@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+var (
+	ErrLoginAttemptNotExist = errors.New("login attempt not exist")
+	ErrLoginAttemptConflict = errors.New("login attempt is modified concurrently")
+)
+
+// LoginAttemptManager persists the login failures shared by the replicas
+type LoginAttemptManager interface {
+	// PutLoginAttempt creates or overwrites the attempt, it is removed after ttl
+	PutLoginAttempt(ctx context.Context, a *rbacframe.LoginAttempt, ttl time.Duration) error
+	// IncLoginAttempt increases the failures of the attempt atomically, the attempt
+	// is created with the ttl if not exist, and the ttl is not extended by increments
+	IncLoginAttempt(ctx context.Context, typ, value string, ttl time.Duration) (*rbacframe.LoginAttempt, error)
+	GetLoginAttempt(ctx context.Context, id string) (*rbacframe.LoginAttempt, error)
+	ListLoginAttempt(ctx context.Context) ([]*rbacframe.LoginAttempt, error)
+	DeleteLoginAttempt(ctx context.Context, id string) error
+}
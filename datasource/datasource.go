@@ -25,4 +25,12 @@ type DataSource interface {
 	DependencyManager
 	MetadataManager
 	SCManager
+	WebhookManager
+	APIKeyManager
+	RevocationManager
+	LoginAttemptManager
+	PasswordStateManager
+	AuditManager
+	QuotaManager
+	GovManager
 }
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func (ds *DataSource) CreateAPIKey(ctx context.Context, key *rbacframe.APIKey) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		log.Error("api key info is invalid", err)
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateRBACAPIKeyKey(key.ID), value)
+	if err != nil {
+		log.Error("can not save api key info", err)
+		return err
+	}
+	log.Infof("create new api key [%s] of account [%s]", key.ID, key.Account)
+	return nil
+}
+
+func (ds *DataSource) GetAPIKey(ctx context.Context, id string) (*rbacframe.APIKey, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateRBACAPIKeyKey(id)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrAPIKeyNotExist
+	}
+	key := &rbacframe.APIKey{}
+	err = json.Unmarshal(resp.Kvs[0].Value, key)
+	if err != nil {
+		log.Error("api key info format invalid", err)
+		return nil, err
+	}
+	return key, nil
+}
+
+func (ds *DataSource) ListAPIKey(ctx context.Context, account string) ([]*rbacframe.APIKey, error) {
+	kvs, _, err := client.List(ctx, path.GenerateRBACAPIKeyKey(""))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*rbacframe.APIKey, 0, len(kvs))
+	for _, kv := range kvs {
+		key := &rbacframe.APIKey{}
+		err = json.Unmarshal(kv.Value, key)
+		if err != nil {
+			log.Error("api key info format invalid", err)
+			continue //do not fail if some key is invalid
+		}
+		if len(account) > 0 && key.Account != account {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (ds *DataSource) DeleteAPIKey(ctx context.Context, account, id string) error {
+	key, err := ds.GetAPIKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.Account != account {
+		return datasource.ErrAPIKeyNotExist
+	}
+	_, err = client.Delete(ctx, path.GenerateRBACAPIKeyKey(id))
+	return err
+}
+
+func (ds *DataSource) UpdateAPIKeyLastUsed(ctx context.Context, id, lastUsedTime string) error {
+	key, err := ds.GetAPIKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	key.LastUsedTime = lastUsedTime
+	value, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return client.PutBytes(ctx, path.GenerateRBACAPIKeyKey(id), value)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+)
+
+// auditBucket is the time range of the audit records sharing a lease,
+// and the key range loaded at a time when listing
+const auditBucket = int64(time.Hour / time.Second)
+
+func (ds *DataSource) PutAuditRecords(ctx context.Context, rs []*audit.Record, ttl time.Duration) error {
+	ops := make([]client.PluginOp, 0, len(rs))
+	for _, r := range rs {
+		value, err := json.Marshal(r)
+		if err != nil {
+			log.Error("audit record is invalid", err)
+			continue
+		}
+		leaseID, err := ds.auditLease(ctx, r.Timestamp/auditBucket, ttl)
+		if err != nil {
+			log.Error("grant lease of audit records failed", err)
+			return err
+		}
+		ops = append(ops, client.OpPut(client.WithStrKey(path.GenerateAuditRecordKey(r.Timestamp, r.ID)),
+			client.WithValue(value), client.WithLease(leaseID)))
+	}
+	for start := 0; start < len(ops); start += client.MaxTxnNumberOneTime {
+		end := start + client.MaxTxnNumberOneTime
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if _, err := client.Instance().Txn(ctx, ops[start:end]); err != nil {
+			// the lease may be lost, grant new ones next time
+			ds.auditMux.Lock()
+			ds.auditLeases = make(map[int64]int64)
+			ds.auditMux.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+//auditLease returns the lease shared by the records in the bucket,
+//it lives ttl after the bucket ends, so every record lives at least ttl
+func (ds *DataSource) auditLease(ctx context.Context, bucket int64, ttl time.Duration) (int64, error) {
+	ds.auditMux.Lock()
+	defer ds.auditMux.Unlock()
+	if leaseID, ok := ds.auditLeases[bucket]; ok {
+		return leaseID, nil
+	}
+	leaseID, err := client.Instance().LeaseGrant(ctx, ttlSeconds(ttl)+auditBucket)
+	if err != nil {
+		return 0, err
+	}
+	for b := range ds.auditLeases {
+		if b < bucket {
+			delete(ds.auditLeases, b)
+		}
+	}
+	ds.auditLeases[bucket] = leaseID
+	return leaseID, nil
+}
+
+//ListAuditRecord loads the records bucket by bucket backwards from the end of query,
+//until the limit is reached or no earlier records
+func (ds *DataSource) ListAuditRecord(ctx context.Context, q *audit.Query) ([]*audit.Record, error) {
+	if q == nil {
+		q = &audit.Query{}
+	}
+	end := q.End + 1
+	if q.End <= 0 {
+		end = time.Now().Unix() + 1
+	}
+	var records []*audit.Record
+	for end > q.Start {
+		begin := end - auditBucket
+		if begin < q.Start {
+			begin = q.Start
+		}
+		kvs, err := listAuditRange(ctx, begin, end)
+		if err != nil {
+			return nil, err
+		}
+		// the keys are in time asc order
+		for i := len(kvs) - 1; i >= 0; i-- {
+			r := &audit.Record{}
+			err = json.Unmarshal(kvs[i].Value, r)
+			if err != nil {
+				log.Error("audit record format invalid", err)
+				continue
+			}
+			if !q.Match(r) {
+				continue
+			}
+			records = append(records, r)
+			if q.Limit > 0 && len(records) >= q.Limit {
+				return records, nil
+			}
+		}
+		end = begin
+		more, err := hasAuditRecordBefore(ctx, end)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+	}
+	return records, nil
+}
+
+//listAuditRange returns the records of time in [begin, end)
+func listAuditRange(ctx context.Context, begin, end int64) ([]*mvccpb.KeyValue, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateAuditRecordKey(begin, "")),
+		client.WithStrEndKey(path.GenerateAuditRecordKey(end, "")))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Kvs, nil
+}
+
+func hasAuditRecordBefore(ctx context.Context, end int64) (bool, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GetAuditRootKey()),
+		client.WithStrEndKey(path.GenerateAuditRecordKey(end, "")),
+		client.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count > 0, nil
+}
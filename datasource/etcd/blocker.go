@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+// incRetries is the max times of increasing the failures concurrently
+const incRetries = 5
+
+func (ds *DataSource) PutLoginAttempt(ctx context.Context, a *rbacframe.LoginAttempt, ttl time.Duration) error {
+	value, err := json.Marshal(a)
+	if err != nil {
+		log.Error("login attempt info is invalid", err)
+		return err
+	}
+	leaseID, err := client.Instance().LeaseGrant(ctx, ttlSeconds(ttl))
+	if err != nil {
+		log.Error("grant lease of login attempt failed", err)
+		return err
+	}
+	_, err = client.Instance().Do(ctx, client.PUT, client.WithStrKey(path.GenerateRBACLoginAttemptKey(a.ID)),
+		client.WithValue(value), client.WithLease(leaseID))
+	return err
+}
+
+// IncLoginAttempt compares the mod revision to increase the failures,
+// and retries if the attempt is modified by the other replicas meanwhile
+func (ds *DataSource) IncLoginAttempt(ctx context.Context, typ, value string,
+	ttl time.Duration) (*rbacframe.LoginAttempt, error) {
+	key := path.GenerateRBACLoginAttemptKey(rbacframe.LoginAttemptID(typ, value))
+	for i := 0; i < incRetries; i++ {
+		resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+		if err != nil {
+			return nil, err
+		}
+		var (
+			a       *rbacframe.LoginAttempt
+			cmp     client.CompareOp
+			leaseID int64
+			granted bool
+		)
+		if resp.Count == 0 {
+			a = rbacframe.NewLoginAttempt(typ, value)
+			a.FirstFailTime = time.Now().Unix()
+			leaseID, err = client.Instance().LeaseGrant(ctx, ttlSeconds(ttl))
+			if err != nil {
+				log.Error("grant lease of login attempt failed", err)
+				return nil, err
+			}
+			granted = true
+			cmp = client.OpCmp(client.CmpStrVer(key), client.CmpEqual, 0)
+		} else {
+			kv := resp.Kvs[0]
+			a = &rbacframe.LoginAttempt{}
+			if err := json.Unmarshal(kv.Value, a); err != nil {
+				log.Error("login attempt info format invalid", err)
+				return nil, err
+			}
+			leaseID = kv.Lease
+			cmp = client.OpCmp(client.CmpStrModRev(key), client.CmpEqual, kv.ModRevision)
+		}
+		a.Failures++
+		data, err := json.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		txn, err := client.Instance().TxnWithCmp(ctx, []client.PluginOp{
+			client.OpPut(client.WithStrKey(key), client.WithValue(data), client.WithLease(leaseID)),
+		}, []client.CompareOp{cmp}, nil)
+		if err == nil && txn.Succeeded {
+			return a, nil
+		}
+		if granted {
+			if rerr := client.Instance().LeaseRevoke(ctx, leaseID); rerr != nil {
+				log.Error("revoke lease of login attempt failed", rerr)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, datasource.ErrLoginAttemptConflict
+}
+
+func ttlSeconds(ttl time.Duration) int64 {
+	seconds := int64(ttl.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+func (ds *DataSource) GetLoginAttempt(ctx context.Context, id string) (*rbacframe.LoginAttempt, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateRBACLoginAttemptKey(id)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrLoginAttemptNotExist
+	}
+	a := &rbacframe.LoginAttempt{}
+	err = json.Unmarshal(resp.Kvs[0].Value, a)
+	if err != nil {
+		log.Error("login attempt info format invalid", err)
+		return nil, err
+	}
+	return a, nil
+}
+
+func (ds *DataSource) ListLoginAttempt(ctx context.Context) ([]*rbacframe.LoginAttempt, error) {
+	kvs, _, err := client.List(ctx, path.GenerateRBACLoginAttemptKey(""))
+	if err != nil {
+		return nil, err
+	}
+	attempts := make([]*rbacframe.LoginAttempt, 0, len(kvs))
+	for _, kv := range kvs {
+		a := &rbacframe.LoginAttempt{}
+		err = json.Unmarshal(kv.Value, a)
+		if err != nil {
+			log.Error("login attempt info format invalid", err)
+			continue
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, nil
+}
+
+func (ds *DataSource) DeleteLoginAttempt(ctx context.Context, id string) error {
+	_, err := client.Delete(ctx, path.GenerateRBACLoginAttemptKey(id))
+	return err
+}
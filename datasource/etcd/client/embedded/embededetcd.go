@@ -564,7 +564,7 @@ func getEmbedInstance(opts datasource.Options) client.Registry {
 	serverCfg.QuotaBackendBytes = etcdserver.MaxQuotaBytes
 	// TODO 不支持使用TLS通信
 	// 存储目录，相对于工作目录
-	serverCfg.Dir = "data"
+	serverCfg.Dir = etcd.EmbeddedDataDir
 	// 集群支持
 	serverCfg.Name = hostName
 	serverCfg.InitialCluster = etcd.Configuration().ClusterAddresses
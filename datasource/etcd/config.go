@@ -26,6 +26,9 @@ import (
 	"github.com/apache/servicecomb-service-center/server/config"
 )
 
+// EmbeddedDataDir is the data dir of embedded etcd, relative to the work dir
+const EmbeddedDataDir = "data"
+
 var (
 	defaultRegistryConfig client.Config
 	configOnce            sync.Once
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+var candidate = fmt.Sprintf("%s-%d", util.HostName(), os.Getpid())
+
+// Campaign puts the leader key with a lease if it does not exist, the leader
+// keeps the leadership by renewing the lease, and the key is removed with the
+// lease once the leader stops renewing, so it never removes others' key
+func (ds *DataSource) Campaign(ctx context.Context, request *datasource.CampaignRequest) error {
+	ds.leaderMux.Lock()
+	defer ds.leaderMux.Unlock()
+
+	if leaseID, ok := ds.leases[request.ID]; ok {
+		_, err := client.Instance().LeaseRenew(ctx, leaseID)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(errorsEx.InternalError); ok {
+			// keep the lease, it may be still alive
+			return err
+		}
+		log.Warnf("leadership of %s is lost, lease %d not found", request.ID, leaseID)
+		delete(ds.leases, request.ID)
+	}
+
+	leaseID, err := client.Instance().LeaseGrant(ctx, request.TTL)
+	if err != nil {
+		return err
+	}
+	key := path.GenerateLeaderKey(request.ID)
+	resp, err := client.Instance().TxnWithCmp(ctx,
+		[]client.PluginOp{client.OpPut(client.WithStrKey(key), client.WithStrValue(candidate), client.WithLease(leaseID))},
+		[]client.CompareOp{client.OpCmp(client.CmpStrVer(key), client.CmpEqual, 0)},
+		nil)
+	if err == nil && resp.Succeeded {
+		ds.leases[request.ID] = leaseID
+		log.Infof("hold the leadership of %s, lease %d", request.ID, leaseID)
+		return nil
+	}
+	if rerr := client.Instance().LeaseRevoke(ctx, leaseID); rerr != nil {
+		log.Error(fmt.Sprintf("revoke lease %d failed", leaseID), rerr)
+	}
+	if err != nil {
+		return err
+	}
+	return datasource.ErrNotLeader
+}
+
+// Resign revokes the lease held by self, the leader key is removed with it
+func (ds *DataSource) Resign(ctx context.Context, request *datasource.ResignRequest) error {
+	ds.leaderMux.Lock()
+	defer ds.leaderMux.Unlock()
+
+	leaseID, ok := ds.leases[request.ID]
+	if !ok {
+		return nil
+	}
+	delete(ds.leases, request.ID)
+	return client.Instance().LeaseRevoke(ctx, leaseID)
+}
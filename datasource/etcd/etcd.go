@@ -50,6 +50,14 @@ type DataSource struct {
 
 	lockMux sync.Mutex
 	locks   map[string]*etcdsync.DLock
+
+	leaderMux sync.Mutex
+	// leases are the leases of the elections held by self
+	leases map[string]int64
+
+	auditMux sync.Mutex
+	// auditLeases are the leases shared by the audit records in the same hour
+	auditLeases map[int64]int64
 }
 
 func NewDataSource(opts datasource.Options) (datasource.DataSource, error) {
@@ -60,6 +68,8 @@ func NewDataSource(opts datasource.Options) (datasource.DataSource, error) {
 		SchemaEditable: opts.SchemaEditable,
 		InstanceTTL:    opts.InstanceTTL,
 		locks:          make(map[string]*etcdsync.DLock),
+		leases:         make(map[string]int64),
+		auditLeases:    make(map[int64]int64),
 	}
 
 	registryAddresses := strings.Join(Configuration().RegistryAddresses(), ",")
@@ -83,6 +93,7 @@ func (ds *DataSource) initialize(opts datasource.Options) error {
 	ds.autoCompact()
 	// Jobs
 	job.ClearNoInstanceServices()
+	registerHealthChecks(opts)
 	return nil
 }
 
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/kv"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/sd"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// GovRevisionEventHandler propagates the governance changes made by other replicas,
+// the watchers of the project are woken up by them
+type GovRevisionEventHandler struct {
+}
+
+func (h *GovRevisionEventHandler) Type() sd.Type {
+	return kv.GovRevision
+}
+
+func (h *GovRevisionEventHandler) OnEvent(evt sd.KvEvent) {
+	if evt.Type == pb.EVT_INIT || evt.Type == pb.EVT_DELETE {
+		return
+	}
+	project := strings.TrimPrefix(string(evt.KV.Key), path.GenerateGovRevisionKey(""))
+	notify.PublishGovRevisionEvent(notify.NewGovRevisionEvent(project, evt.KV.ModRevision))
+}
+
+func NewGovRevisionEventHandler() *GovRevisionEventHandler {
+	return &GovRevisionEventHandler{}
+}
@@ -116,6 +116,9 @@ func (h *InstanceEventHandler) OnEvent(evt sd.KvEvent) {
 		action, providerID, ms.Environment, ms.AppId, ms.ServiceName, ms.Version,
 		providerInstanceID, instance.Endpoints)
 
+	notify.PublishResourceEvent(notify.NewResourceEvent(notify.ResourceInstance, string(action),
+		domainProject, providerInstanceID, ms, instance))
+
 	// 查询所有consumer
 	consumerIDs, _, err := serviceUtil.GetAllConsumerIds(ctx, domainProject, ms)
 	if err != nil {
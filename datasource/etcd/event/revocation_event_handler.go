@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"github.com/apache/servicecomb-service-center/datasource/etcd/kv"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/sd"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// RevocationEventHandler propagates the token revocations made by other replicas
+type RevocationEventHandler struct {
+}
+
+func (h *RevocationEventHandler) Type() sd.Type {
+	return kv.REVOCATION
+}
+
+func (h *RevocationEventHandler) OnEvent(evt sd.KvEvent) {
+	if evt.Type == pb.EVT_INIT {
+		return
+	}
+	r, ok := evt.KV.Value.(*rbacframe.Revocation)
+	if !ok {
+		return
+	}
+	notify.PublishRevocationEvent(notify.NewRevocationEvent(string(evt.Type), r))
+}
+
+func NewRevocationEventHandler() *RevocationEventHandler {
+	return &RevocationEventHandler{}
+}
@@ -106,6 +106,9 @@ func (h *RuleEventHandler) OnEvent(evt sd.KvEvent) {
 	}
 	log.Infof("caught [%s] service rule[%s/%s] event", action, providerID, ruleID)
 
+	notify.PublishResourceEvent(notify.NewResourceEvent(notify.ResourceRule, string(action),
+		domainProject, ruleID, serviceUtil.GetServiceFromCache(domainProject, providerID), evt.KV.Value))
+
 	err := task.GetService().Add(context.Background(),
 		NewRulesChangedAsyncTask(domainProject, providerID, evt))
 	if err != nil {
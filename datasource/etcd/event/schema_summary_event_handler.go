@@ -23,7 +23,9 @@ import (
 	"github.com/apache/servicecomb-service-center/datasource/etcd/kv"
 	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
 	"github.com/apache/servicecomb-service-center/datasource/etcd/sd"
+	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
 	"github.com/apache/servicecomb-service-center/server/metrics"
+	"github.com/apache/servicecomb-service-center/server/notify"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -49,6 +51,15 @@ func (h *SchemaSummaryEventHandler) OnEvent(evt sd.KvEvent) {
 		}
 	default:
 	}
+
+	if action == pb.EVT_INIT {
+		return
+	}
+	domainProject, serviceID, schemaID := path.GetInfoFromSchemaSummaryKV(evt.KV.Key)
+	summary, _ := evt.KV.Value.(string)
+	notify.PublishResourceEvent(notify.NewResourceEvent(notify.ResourceSchema, string(action),
+		domainProject, schemaID, serviceUtil.GetServiceFromCache(domainProject, serviceID),
+		&pb.Schema{SchemaId: schemaID, Summary: summary}))
 }
 
 func NewSchemaSummaryEventHandler() *SchemaSummaryEventHandler {
@@ -28,6 +28,7 @@ import (
 	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/metrics"
+	"github.com/apache/servicecomb-service-center/server/notify"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -73,6 +74,9 @@ func (h *ServiceEventHandler) OnEvent(evt sd.KvEvent) {
 	// cache
 	providerKey := pb.MicroServiceToKey(domainProject, ms)
 	cache.FindInstances.Remove(providerKey)
+
+	notify.PublishResourceEvent(notify.NewResourceEvent(notify.ResourceService, string(evt.Type),
+		domainProject, ms.ServiceId, ms, ms))
 }
 
 func getFramework(ms *pb.MicroService) (string, string) {
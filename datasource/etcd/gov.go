@@ -0,0 +1,259 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+)
+
+func (ds *DataSource) PutGovPolicy(ctx context.Context, project string, p *gov.Policy) (int64, error) {
+	value, err := json.Marshal(p)
+	if err != nil {
+		log.Error("governance policy is invalid", err)
+		return 0, err
+	}
+	resp, err := client.BatchCommitWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(path.GenerateGovPolicyKey(project, p.ID)), client.WithValue(value)),
+		client.OpPut(client.WithStrKey(path.GenerateGovRevisionKey(project)), client.WithStrValue(p.ID)),
+	}, nil, nil)
+	if err != nil {
+		log.Error("can not save governance policy", err)
+		return 0, err
+	}
+	return resp.Revision, nil
+}
+
+func (ds *DataSource) GetGovPolicy(ctx context.Context, project, id string) (*gov.Policy, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateGovPolicyKey(project, id)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrGovPolicyNotExist
+	}
+	return decodeGovPolicy(resp.Kvs[0])
+}
+
+func (ds *DataSource) ListGovPolicy(ctx context.Context, project, kind string) ([]*gov.Policy, error) {
+	kvs, _, err := client.List(ctx, path.GetGovPolicyRootKey(project))
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*gov.Policy, 0, len(kvs))
+	for _, kv := range kvs {
+		p, err := decodeGovPolicy(kv)
+		if err != nil {
+			continue
+		}
+		if len(kind) > 0 && p.Kind != kind {
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (ds *DataSource) DeleteGovPolicy(ctx context.Context, project, id string) (int64, error) {
+	key := path.GenerateGovPolicyKey(project, id)
+	resp, err := client.BatchCommitWithCmp(ctx, []client.PluginOp{
+		client.OpDel(client.WithStrKey(key)),
+		client.OpPut(client.WithStrKey(path.GenerateGovRevisionKey(project)), client.WithStrValue(id)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrVer(key), client.CmpNotEqual, 0),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Succeeded {
+		return 0, datasource.ErrGovPolicyNotExist
+	}
+	return resp.Revision, nil
+}
+
+func (ds *DataSource) GetGovRevision(ctx context.Context, project string) (int64, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateGovRevisionKey(project)))
+	if err != nil {
+		return 0, err
+	}
+	return resp.MaxModRevision(), nil
+}
+
+func (ds *DataSource) AddGovHistory(ctx context.Context, project string, h *gov.History) error {
+	value, err := json.Marshal(h)
+	if err != nil {
+		log.Error("governance policy history is invalid", err)
+		return err
+	}
+	key := path.GenerateGovHistoryKey(project, h.ID, h.Revision)
+	resp, err := client.BatchCommitWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(key), client.WithValue(value)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrVer(key), client.CmpEqual, 0),
+	}, nil)
+	if err != nil {
+		log.Error("can not save governance policy history", err)
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrGovHistoryConflict
+	}
+	return nil
+}
+
+func (ds *DataSource) ListGovHistory(ctx context.Context, project, id string) ([]*gov.History, error) {
+	kvs, _, err := client.List(ctx, path.GetGovHistoryRootKey(project, id))
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]*gov.History, 0, len(kvs))
+	for _, kv := range kvs {
+		h := &gov.History{}
+		err := json.Unmarshal(kv.Value, h)
+		if err != nil {
+			log.Error("governance policy history format invalid", err)
+			continue
+		}
+		histories = append(histories, h)
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].Revision < histories[j].Revision
+	})
+	return histories, nil
+}
+
+func (ds *DataSource) PutGovRollout(ctx context.Context, project string, r *gov.Rollout) error {
+	key := path.GenerateGovRolloutKey(project, r.ID)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return err
+	}
+	cmp := client.OpCmp(client.CmpStrVer(key), client.CmpEqual, 0)
+	var rev int64
+	if resp.Count > 0 {
+		old := &gov.Rollout{}
+		err = json.Unmarshal(resp.Kvs[0].Value, old)
+		if err != nil {
+			log.Error("governance policy rollout format invalid", err)
+			return err
+		}
+		rev = old.Revision
+		cmp = client.OpCmp(client.CmpStrModRev(key), client.CmpEqual, resp.Kvs[0].ModRevision)
+	}
+	if rev != r.Revision {
+		return datasource.ErrGovRolloutConflict
+	}
+	next := *r
+	next.Revision++
+	value, err := json.Marshal(&next)
+	if err != nil {
+		log.Error("governance policy rollout is invalid", err)
+		return err
+	}
+	txn, err := client.BatchCommitWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(key), client.WithValue(value)),
+		client.OpPut(client.WithStrKey(path.GenerateGovRevisionKey(project)), client.WithStrValue(r.ID)),
+	}, []client.CompareOp{cmp}, nil)
+	if err != nil {
+		log.Error("can not save governance policy rollout", err)
+		return err
+	}
+	if !txn.Succeeded {
+		return datasource.ErrGovRolloutConflict
+	}
+	r.Revision = next.Revision
+	return nil
+}
+
+func (ds *DataSource) GetGovRollout(ctx context.Context, project, id string) (*gov.Rollout, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateGovRolloutKey(project, id)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrGovRolloutNotExist
+	}
+	r := &gov.Rollout{}
+	err = json.Unmarshal(resp.Kvs[0].Value, r)
+	if err != nil {
+		log.Error("governance policy rollout format invalid", err)
+		return nil, err
+	}
+	return r, nil
+}
+
+func (ds *DataSource) ListGovRollout(ctx context.Context, project string) ([]*gov.Rollout, error) {
+	kvs, _, err := client.List(ctx, path.GetGovRolloutRootKey(project))
+	if err != nil {
+		return nil, err
+	}
+	rollouts := make([]*gov.Rollout, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &gov.Rollout{}
+		err := json.Unmarshal(kv.Value, r)
+		if err != nil {
+			log.Error("governance policy rollout format invalid", err)
+			continue
+		}
+		rollouts = append(rollouts, r)
+	}
+	return rollouts, nil
+}
+
+func (ds *DataSource) DeleteGovRollout(ctx context.Context, project, id string) error {
+	key := path.GenerateGovRolloutKey(project, id)
+	resp, err := client.BatchCommitWithCmp(ctx, []client.PluginOp{
+		client.OpDel(client.WithStrKey(key)),
+		client.OpPut(client.WithStrKey(path.GenerateGovRevisionKey(project)), client.WithStrValue(id)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrVer(key), client.CmpNotEqual, 0),
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrGovRolloutNotExist
+	}
+	return nil
+}
+
+func decodeGovPolicy(kv *mvccpb.KeyValue) (*gov.Policy, error) {
+	p := &gov.Policy{}
+	err := json.Unmarshal(kv.Value, p)
+	if err != nil {
+		log.Error("governance policy format invalid", err)
+		return nil, err
+	}
+	if p.GovernancePolicy == nil {
+		p.GovernancePolicy = &gov.GovernancePolicy{}
+	}
+	// the policy is put with the revision key in a txn, so the mod revision is the project revision of the change
+	p.Revision = kv.ModRevision
+	return p, nil
+}
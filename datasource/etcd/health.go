@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/kv"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/sd"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/health"
+)
+
+func registerHealthChecks(opts datasource.Options) {
+	health.Register("backend_latency", health.Readiness, health.NewLatencyChecker(func(ctx context.Context) error {
+		_, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(path.GetRootKey()), client.WithCountOnly())
+		return err
+	}))
+	health.Register("cache_sync", health.Readiness, health.CheckFunc(checkCacheSync))
+	if opts.Kind == "embeded_etcd" {
+		health.Register("disk", health.Readiness, health.NewDiskChecker(EmbeddedDataDir))
+	}
+}
+
+// checkCacheSync fails if any adaptor is not ready, or its cache has been
+// out of sync with etcd longer than 'health.maxCacheLag'
+func checkCacheSync() error {
+	max := config.GetDuration("health.maxCacheLag", time.Minute)
+	var lagging []string
+	for _, t := range sd.Types {
+		adaptor := kv.Store().Adaptors(t)
+		select {
+		case <-adaptor.Ready():
+		default:
+			lagging = append(lagging, t.String()+" not ready")
+			continue
+		}
+		if l, ok := adaptor.(sd.Lagger); ok {
+			if lag := l.Lag(); lag > max {
+				lagging = append(lagging, fmt.Sprintf("%s lag %s", t, lag))
+			}
+		}
+	}
+	if len(lagging) > 0 {
+		return fmt.Errorf("cache out of sync: %s", strings.Join(lagging, ", "))
+	}
+	return nil
+}
@@ -180,6 +180,7 @@ func (s *TypeStore) Service() sd.Adaptor            { return s.Adaptors(SERVICE)
 func (s *TypeStore) SchemaSummary() sd.Adaptor      { return s.Adaptors(SchemaSummary) }
 func (s *TypeStore) Instance() sd.Adaptor           { return s.Adaptors(INSTANCE) }
 func (s *TypeStore) Lease() sd.Adaptor              { return s.Adaptors(LEASE) }
+func (s *TypeStore) InstanceOwner() sd.Adaptor      { return s.Adaptors(InstanceOwner) }
 func (s *TypeStore) ServiceIndex() sd.Adaptor       { return s.Adaptors(ServiceIndex) }
 func (s *TypeStore) ServiceAlias() sd.Adaptor       { return s.Adaptors(ServiceAlias) }
 func (s *TypeStore) ServiceTag() sd.Adaptor         { return s.Adaptors(ServiceTag) }
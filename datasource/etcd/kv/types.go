@@ -48,6 +48,9 @@ var (
 	SchemaSummary   sd.Type
 	INSTANCE        sd.Type
 	LEASE           sd.Type
+	InstanceOwner   sd.Type
+	REVOCATION      sd.Type
+	GovRevision     sd.Type
 )
 
 func registerInnerTypes() {
@@ -73,6 +76,9 @@ func registerInnerTypes() {
 	LEASE = Store().MustInstall(NewAddOn("LEASE",
 		sd.Configure().WithPrefix(path.GetInstanceLeaseRootKey("")).
 			WithInitSize(1000).WithParser(value.StringParser)))
+	InstanceOwner = Store().MustInstall(NewAddOn("INSTANCE_OWNER",
+		sd.Configure().WithPrefix(path.GetInstanceOwnerRootKey("")).
+			WithInitSize(1000).WithParser(value.StringParser)))
 	ServiceIndex = Store().MustInstall(NewAddOn("SERVICE_INDEX",
 		sd.Configure().WithPrefix(path.GetServiceIndexRootKey("")).
 			WithInitSize(500).WithParser(value.StringParser)))
@@ -94,4 +100,10 @@ func registerInnerTypes() {
 	PROJECT = Store().MustInstall(NewAddOn("PROJECT",
 		sd.Configure().WithPrefix(path.GetProjectRootKey("")).
 			WithInitSize(100).WithParser(value.StringParser)))
+	REVOCATION = Store().MustInstall(NewAddOn("REVOCATION",
+		sd.Configure().WithPrefix(path.GenerateRBACRevocationKey("")).
+			WithInitSize(100).WithParser(value.RevocationParser)))
+	GovRevision = Store().MustInstall(NewAddOn("GOV_REVISION",
+		sd.Configure().WithPrefix(path.GenerateGovRevisionKey("")).
+			WithInitSize(100).WithParser(value.StringParser)))
 }
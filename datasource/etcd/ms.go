@@ -38,6 +38,7 @@ import (
 	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
@@ -148,8 +149,6 @@ func (ds *DataSource) RegisterService(ctx context.Context, request *pb.CreateSer
 		}, nil
 	}
 
-	//TODO increase usage in quota system
-
 	log.Info(fmt.Sprintf("create micro-service[%s][%s] successfully, operator: %s",
 		service.ServiceId, serviceFlag, remoteIP))
 	return &pb.CreateServiceResponse{
@@ -621,6 +620,10 @@ func (ds *DataSource) RegisterInstance(ctx context.Context, request *pb.Register
 		client.OpPut(client.WithStrKey(hbKey), client.WithStrValue(fmt.Sprintf("%d", leaseID)),
 			client.WithLease(leaseID)),
 	}
+	if owner := rbacframe.OwnerFromContext(ctx); len(owner) > 0 {
+		opts = append(opts, client.OpPut(client.WithStrKey(path.GenerateInstanceOwnerKey(domainProject, instance.ServiceId, instanceID)),
+			client.WithStrValue(owner), client.WithLease(leaseID)))
+	}
 
 	resp, err := client.Instance().TxnWithCmp(ctx, opts,
 		[]client.CompareOp{client.OpCmp(
@@ -642,8 +645,6 @@ func (ds *DataSource) RegisterInstance(ctx context.Context, request *pb.Register
 		}, nil
 	}
 
-	//TODO increase usage in quota system
-
 	log.Info(fmt.Sprintf("register instance %s, instanceID %s, operator %s",
 		instanceFlag, instanceID, remoteIP))
 	return &pb.RegisterInstanceResponse{
@@ -1049,6 +1050,13 @@ func (ds *DataSource) UpdateInstanceStatus(ctx context.Context, request *pb.Upda
 		}, nil
 	}
 
+	if err := checkInstanceOwner(ctx, domainProject, request.ServiceId, request.InstanceId); err != nil {
+		log.Errorf(err, "update instance[%s] status failed", updateStatusFlag)
+		return &pb.UpdateInstanceStatusResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
 	copyInstanceRef := *instance
 	copyInstanceRef.Status = request.Status
 
@@ -1088,6 +1096,13 @@ func (ds *DataSource) UpdateInstanceProperties(ctx context.Context, request *pb.
 		}, nil
 	}
 
+	if err := checkInstanceOwner(ctx, domainProject, request.ServiceId, request.InstanceId); err != nil {
+		log.Errorf(err, "update instance[%s] properties failed", instanceFlag)
+		return &pb.UpdateInstancePropsResponse{
+			Response: pb.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
 	copyInstanceRef := *instance
 	copyInstanceRef.Properties = request.Properties
 
@@ -1258,7 +1273,10 @@ func (ds *DataSource) UnregisterInstance(ctx context.Context, request *pb.Unregi
 
 	instanceFlag := util.StringJoin([]string{serviceID, instanceID}, "/")
 
-	err := revokeInstance(ctx, domainProject, serviceID, instanceID)
+	err := checkInstanceOwner(ctx, domainProject, serviceID, instanceID)
+	if err == nil {
+		err = revokeInstance(ctx, domainProject, serviceID, instanceID)
+	}
 	if err != nil {
 		log.Errorf(err, "unregister instance failed, instance[%s], operator %s: revoke instance failed",
 			instanceFlag, remoteIP)
@@ -1282,7 +1300,11 @@ func (ds *DataSource) Heartbeat(ctx context.Context, request *pb.HeartbeatReques
 	domainProject := util.ParseDomainProject(ctx)
 	instanceFlag := util.StringJoin([]string{request.ServiceId, request.InstanceId}, "/")
 
-	_, ttl, err := serviceUtil.HeartbeatUtil(ctx, domainProject, request.ServiceId, request.InstanceId)
+	var ttl int64
+	err := checkInstanceOwner(ctx, domainProject, request.ServiceId, request.InstanceId)
+	if err == nil {
+		_, ttl, err = serviceUtil.HeartbeatUtil(ctx, domainProject, request.ServiceId, request.InstanceId)
+	}
 	if err != nil {
 		log.Errorf(err, "heartbeat failed, instance[%s]. operator %s",
 			instanceFlag, remoteIP)
@@ -1818,7 +1840,8 @@ func (ds *DataSource) AddRule(ctx context.Context, request *pb.AddServiceRulesRe
 		}, nil
 	}
 	res := quota.NewApplyQuotaResource(quota.TypeRule, domainProject, request.ServiceId, int64(len(request.Rules)))
-	errQuota := quota.Apply(ctx, res)
+	release, errQuota := quota.Acquire(ctx, res)
+	defer release()
 	if errQuota != nil {
 		log.Errorf(errQuota, "add service[%s] rule failed, operator: %s", request.ServiceId, remoteIP)
 		response := &pb.AddServiceRulesResponse{
@@ -2148,7 +2171,8 @@ func (ds *DataSource) modifySchemas(ctx context.Context, domainProject string, s
 	if !ds.isSchemaEditable(service) {
 		if len(service.Schemas) == 0 {
 			res := quota.NewApplyQuotaResource(quota.TypeSchema, domainProject, serviceID, int64(len(nonExistSchemaIds)))
-			errQuota := quota.Apply(ctx, res)
+			release, errQuota := quota.Acquire(ctx, res)
+			defer release()
 			if errQuota != nil {
 				log.Errorf(errQuota, "modify service[%s] schemas failed, operator: %s", serviceID, remoteIP)
 				return errQuota
@@ -2192,7 +2216,8 @@ func (ds *DataSource) modifySchemas(ctx context.Context, domainProject string, s
 		quotaSize := len(needAddSchemas) - len(needDeleteSchemas)
 		if quotaSize > 0 {
 			res := quota.NewApplyQuotaResource(quota.TypeSchema, domainProject, serviceID, int64(quotaSize))
-			err := quota.Apply(ctx, res)
+			release, err := quota.Acquire(ctx, res)
+			defer release()
 			if err != nil {
 				log.Errorf(err, "modify service[%s] schemas failed, operator: %s", serviceID, remoteIP)
 				return err
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func (ds *DataSource) PutPasswordState(ctx context.Context, s *rbacframe.PasswordState) error {
+	value, err := json.Marshal(s)
+	if err != nil {
+		log.Error("password state is invalid", err)
+		return err
+	}
+	return client.PutBytes(ctx, path.GenerateRBACPasswordStateKey(s.Account), value)
+}
+
+func (ds *DataSource) GetPasswordState(ctx context.Context, account string) (*rbacframe.PasswordState, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateRBACPasswordStateKey(account)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrPasswordStateNotExist
+	}
+	s := &rbacframe.PasswordState{}
+	err = json.Unmarshal(resp.Kvs[0].Value, s)
+	if err != nil {
+		log.Error("password state format invalid", err)
+		return nil, err
+	}
+	return s, nil
+}
+
+func (ds *DataSource) DeletePasswordState(ctx context.Context, account string) error {
+	_, err := client.Delete(ctx, path.GenerateRBACPasswordStateKey(account))
+	return err
+}
@@ -18,6 +18,8 @@
 package path
 
 import (
+	"fmt"
+
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/go-chassis/cari/discovery"
 )
@@ -39,10 +41,13 @@ const (
 	RegistrySchemaKey        = "schemas"
 	RegistrySchemaSummaryKey = "schema-sum"
 	RegistryLeaseKey         = "leases"
+	RegistryOwnerKey         = "owners"
 	RegistryDependencyKey    = "deps"
 	RegistryDepsRuleKey      = "dep-rules"
 	RegistryDepsQueueKey     = "dep-queue"
 	RegistryMetricsKey       = "metrics"
+	RegistryWebhookKey       = "webhooks"
+	RegistryDeadLetterKey    = "dead-letters"
 	DepsQueueUUID            = "0"
 	DepsConsumer             = "c"
 	DepsProvider             = "p"
@@ -60,6 +65,14 @@ func GenerateRBACAccountKey(name string) string {
 	}, SPLIT)
 }
 
+func GenerateLeaderKey(id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"leaders",
+		id,
+	}, SPLIT)
+}
+
 func GetProjectRootKey(domain string) string {
 	return util.StringJoin([]string{
 		GetRootKey(),
@@ -68,6 +81,174 @@ func GetProjectRootKey(domain string) string {
 	}, SPLIT)
 }
 
+func GenerateRBACAPIKeyKey(id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"api-keys",
+		id,
+	}, SPLIT)
+}
+
+func GenerateRBACRevocationKey(id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"revocations",
+		id,
+	}, SPLIT)
+}
+
+func GenerateRBACLoginAttemptKey(id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"login-attempts",
+		id,
+	}, SPLIT)
+}
+
+func GetQuotaLimitsRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"quotas",
+		"",
+	}, SPLIT)
+}
+
+func GenerateQuotaLimitsKey(domain, project string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"quotas",
+		domain,
+		project,
+	}, SPLIT)
+}
+
+//GenerateQuotaRevisionKey is increased on each reservation of the quota key
+func GenerateQuotaRevisionKey(key string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"quota-reservations",
+		key,
+	}, SPLIT)
+}
+
+func GenerateQuotaReservationKey(key, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"quota-reservations",
+		key,
+		id,
+	}, SPLIT)
+}
+
+func GetGovPolicyRootKey(project string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"policies",
+		project,
+		"",
+	}, SPLIT)
+}
+
+func GenerateGovPolicyKey(project, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"policies",
+		project,
+		id,
+	}, SPLIT)
+}
+
+func GetGovHistoryRootKey(project, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"histories",
+		project,
+		id,
+		"",
+	}, SPLIT)
+}
+
+//GenerateGovHistoryKey pads the revision to list the histories in the order of revision
+func GenerateGovHistoryKey(project, id string, revision int64) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"histories",
+		project,
+		id,
+		fmt.Sprintf("%020d", revision),
+	}, SPLIT)
+}
+
+//GetGovRolloutRootKey returns the root key of the rollouts of all the projects if project is empty
+func GetGovRolloutRootKey(project string) string {
+	if len(project) == 0 {
+		return util.StringJoin([]string{
+			GetRootKey(),
+			"gov",
+			"rollouts",
+			"",
+		}, SPLIT)
+	}
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"rollouts",
+		project,
+		"",
+	}, SPLIT)
+}
+
+func GenerateGovRolloutKey(project, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"rollouts",
+		project,
+		id,
+	}, SPLIT)
+}
+
+//GenerateGovRevisionKey is rewritten with every change of the project policies,
+//the mod revision of it is the revision of the project
+func GenerateGovRevisionKey(project string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov",
+		"revisions",
+		project,
+	}, SPLIT)
+}
+
+func GetAuditRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"audit",
+		"",
+	}, SPLIT)
+}
+
+//GenerateAuditRecordKey orders the records by time
+func GenerateAuditRecordKey(timestamp int64, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"audit",
+		fmt.Sprintf("%020d", timestamp),
+		id,
+	}, SPLIT)
+}
+
+func GenerateRBACPasswordStateKey(name string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"password-states",
+		name,
+	}, SPLIT)
+}
+
 func GenerateRBACRoleKey(name string) string {
 	return util.StringJoin([]string{
 		GetRootKey(),
@@ -271,6 +452,23 @@ func GenerateInstanceLeaseKey(domainProject string, serviceID string, instanceID
 	}, SPLIT)
 }
 
+func GetInstanceOwnerRootKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		RegistryInstanceKey,
+		RegistryOwnerKey,
+		domainProject,
+	}, SPLIT)
+}
+
+func GenerateInstanceOwnerKey(domainProject string, serviceID string, instanceID string) string {
+	return util.StringJoin([]string{
+		GetInstanceOwnerRootKey(domainProject),
+		serviceID,
+		instanceID,
+	}, SPLIT)
+}
+
 func GenerateServiceDependencyRuleKey(serviceType string, domainProject string, in *discovery.MicroServiceKey) string {
 	if in == nil {
 		return util.StringJoin([]string{
@@ -394,3 +592,36 @@ func GenerateProjectKey(domain, project string) string {
 		project,
 	}, SPLIT)
 }
+
+func GetWebhookRootKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		RegistryWebhookKey,
+		RegistryFile,
+		domainProject,
+	}, SPLIT)
+}
+
+func GenerateWebhookKey(domainProject, id string) string {
+	return util.StringJoin([]string{
+		GetWebhookRootKey(domainProject),
+		id,
+	}, SPLIT)
+}
+
+func GetDeadLetterRootKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		RegistryWebhookKey,
+		RegistryDeadLetterKey,
+		domainProject,
+	}, SPLIT)
+}
+
+func GenerateDeadLetterKey(domainProject, webhookID, id string) string {
+	return util.StringJoin([]string{
+		GetDeadLetterRootKey(domainProject),
+		webhookID,
+		id,
+	}, SPLIT)
+}
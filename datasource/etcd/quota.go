@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+func (ds *DataSource) PutQuotaLimits(ctx context.Context, l *quota.Limits) error {
+	value, err := json.Marshal(l)
+	if err != nil {
+		log.Error("quota limits is invalid", err)
+		return err
+	}
+	return client.PutBytes(ctx, path.GenerateQuotaLimitsKey(l.Domain, l.Project), value)
+}
+
+func (ds *DataSource) GetQuotaLimits(ctx context.Context, domain, project string) (*quota.Limits, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateQuotaLimitsKey(domain, project)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrQuotaLimitsNotExist
+	}
+	l := &quota.Limits{}
+	err = json.Unmarshal(resp.Kvs[0].Value, l)
+	if err != nil {
+		log.Error("quota limits format invalid", err)
+		return nil, err
+	}
+	return l, nil
+}
+
+func (ds *DataSource) ListQuotaLimits(ctx context.Context) ([]*quota.Limits, error) {
+	kvs, _, err := client.List(ctx, path.GetQuotaLimitsRootKey())
+	if err != nil {
+		return nil, err
+	}
+	limits := make([]*quota.Limits, 0, len(kvs))
+	for _, kv := range kvs {
+		l := &quota.Limits{}
+		err = json.Unmarshal(kv.Value, l)
+		if err != nil {
+			log.Error("quota limits format invalid", err)
+			continue
+		}
+		limits = append(limits, l)
+	}
+	return limits, nil
+}
+
+func (ds *DataSource) DeleteQuotaLimits(ctx context.Context, domain, project string) error {
+	_, err := client.Delete(ctx, path.GenerateQuotaLimitsKey(domain, project))
+	return err
+}
+
+func (ds *DataSource) CountQuotaUsage(ctx context.Context, resource, serviceID string) (int64, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	switch resource {
+	case quota.ResourceService:
+		return countPrefix(ctx, path.GetServiceRootKey(domainProject)+path.SPLIT)
+	case quota.ResourceInstance:
+		return countPrefix(ctx, path.GetInstanceRootKey(domainProject)+path.SPLIT)
+	case quota.ResourceInstancePerService:
+		return countPrefix(ctx, path.GenerateInstanceKey(domainProject, serviceID, ""))
+	case quota.ResourceRule:
+		return countPrefix(ctx, path.GenerateServiceRuleKey(domainProject, serviceID, ""))
+	case quota.ResourceAccount:
+		return countPrefix(ctx, path.GenerateRBACAccountKey(""))
+	case quota.ResourceRole:
+		return countPrefix(ctx, path.GenerateRBACRoleKey(""))
+	case quota.ResourceSchema:
+		service, err := serviceUtil.GetService(util.WithNoCache(ctx), domainProject, serviceID)
+		if err != nil {
+			return 0, ignoreNoData(err)
+		}
+		return int64(len(service.Schemas)), nil
+	case quota.ResourceTag:
+		tags, err := serviceUtil.GetTagsUtils(util.WithNoCache(ctx), domainProject, serviceID)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(tags)), nil
+	case quota.ResourceDependency:
+		service, err := serviceUtil.GetService(util.WithNoCache(ctx), domainProject, serviceID)
+		if err != nil {
+			return 0, ignoreNoData(err)
+		}
+		key := path.GenerateConsumerDependencyRuleKey(domainProject, pb.MicroServiceToKey(domainProject, service))
+		dep, err := serviceUtil.TransferToMicroServiceDependency(util.WithNoCache(ctx), key)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(dep.Dependency)), nil
+	default:
+		return 0, datasource.ErrUnknownQuotaResource
+	}
+}
+
+func countPrefix(ctx context.Context, prefix string) (int64, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(prefix), client.WithPrefix(), client.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// the resources of a service not exist are counted as 0
+func ignoreNoData(err error) error {
+	if errors.Is(err, datasource.ErrNoData) {
+		return nil
+	}
+	return err
+}
+
+func (ds *DataSource) ListQuotaReservation(ctx context.Context, key string) ([]*quota.Reservation, int64, error) {
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(path.GenerateQuotaRevisionKey(key)))
+	if err != nil {
+		return nil, 0, err
+	}
+	var rev int64
+	if len(resp.Kvs) > 0 {
+		rev = resp.Kvs[0].ModRevision
+	}
+	kvs, _, err := client.List(ctx, path.GenerateQuotaReservationKey(key, ""))
+	if err != nil {
+		return nil, 0, err
+	}
+	now := time.Now().Unix()
+	rs := make([]*quota.Reservation, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &quota.Reservation{}
+		if err := json.Unmarshal(kv.Value, r); err != nil {
+			log.Error("quota reservation format invalid", err)
+			continue
+		}
+		if r.ExpireTime > now {
+			rs = append(rs, r)
+			continue
+		}
+		// leaked by the crashed replicas
+		if _, err := client.Delete(ctx, string(kv.Key)); err != nil {
+			log.Errorf(err, "delete expired quota reservation %s failed", kv.Key)
+		}
+	}
+	return rs, rev, nil
+}
+
+func (ds *DataSource) PutQuotaReservation(ctx context.Context, key string, r *quota.Reservation, rev int64) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	revKey := path.GenerateQuotaRevisionKey(key)
+	resp, err := client.Instance().TxnWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(revKey), client.WithStrValue(r.ID)),
+		client.OpPut(client.WithStrKey(path.GenerateQuotaReservationKey(key, r.ID)), client.WithValue(value)),
+	},
+		[]client.CompareOp{client.OpCmp(client.CmpStrModRev(revKey), client.CmpEqual, rev)}, nil)
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrQuotaReservationConflict
+	}
+	return nil
+}
+
+func (ds *DataSource) DeleteQuotaReservation(ctx context.Context, key, id string) error {
+	_, err := client.Delete(ctx, path.GenerateQuotaReservationKey(key, id))
+	return err
+}
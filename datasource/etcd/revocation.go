@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func (ds *DataSource) PutRevocation(ctx context.Context, r *rbacframe.Revocation) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		log.Error("revocation info is invalid", err)
+		return err
+	}
+	return client.PutBytes(ctx, path.GenerateRBACRevocationKey(r.ID), value)
+}
+
+func (ds *DataSource) ListRevocation(ctx context.Context) ([]*rbacframe.Revocation, error) {
+	kvs, _, err := client.List(ctx, path.GenerateRBACRevocationKey(""))
+	if err != nil {
+		return nil, err
+	}
+	revocations := make([]*rbacframe.Revocation, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &rbacframe.Revocation{}
+		err = json.Unmarshal(kv.Value, r)
+		if err != nil {
+			log.Error("revocation info format invalid", err)
+			continue
+		}
+		revocations = append(revocations, r)
+	}
+	return revocations, nil
+}
+
+func (ds *DataSource) DeleteRevocation(ctx context.Context, id string) error {
+	_, err := client.Delete(ctx, path.GenerateRBACRevocationKey(id))
+	return err
+}
@@ -17,6 +17,8 @@
 
 package sd
 
+import "time"
+
 // Adaptor is used to do service discovery.
 // To improve the performance, Adaptor may use cache firstly in
 // service pkg.
@@ -28,6 +30,11 @@ type Adaptor interface {
 	Cacher
 }
 
+// Lagger reports how long the cache has been out of sync with the backend
+type Lagger interface {
+	Lag() time.Duration
+}
+
 // AdaptorRepository creates Adaptors
 type AdaptorRepository interface {
 	// New news an instance of specify Type adaptor
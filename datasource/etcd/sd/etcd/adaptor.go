@@ -18,6 +18,8 @@
 package etcd
 
 import (
+	"time"
+
 	"github.com/apache/servicecomb-service-center/datasource/etcd/sd"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/config"
@@ -49,6 +51,14 @@ func (se *Adaptor) Ready() <-chan struct{} {
 	return closedCh
 }
 
+// Lag returns how long the cache has been out of sync with etcd
+func (se *Adaptor) Lag() time.Duration {
+	if l, ok := se.Cacher.(sd.Lagger); ok {
+		return l.Lag()
+	}
+	return 0
+}
+
 func NewEtcdAdaptor(name string, cfg *sd.Config) *Adaptor {
 	var adaptor Adaptor
 	enableCache := config.GetRegistry().EnableCache
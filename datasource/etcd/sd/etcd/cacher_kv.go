@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
@@ -46,6 +47,8 @@ type KvCacher struct {
 	Cfg *sd.Config
 
 	reListCount int
+	// unsynced is the unix nano time since the cache is out of sync, 0 means synced
+	unsynced int64
 
 	ready     chan struct{}
 	lw        sdcommon.ListWatch
@@ -140,9 +143,13 @@ func (c *KvCacher) ListAndWatch(ctx context.Context) error {
 	// 2. Runtime: error occurs in previous watch operation, the lister's revision is set to 0.
 	// 3. Runtime: watch operation timed out over DEFAULT_FORCE_LIST_INTERVAL times.
 	if c.needList() {
-		if err := c.doList(cfg); err != nil && (!c.IsReady() || c.getRevision() == 0) {
+		err := c.doList(cfg)
+		if err != nil && (!c.IsReady() || c.getRevision() == 0) {
 			return err // do retry to list etcd
 		}
+		if err == nil {
+			atomic.StoreInt64(&c.unsynced, 0)
+		}
 		// keep going to next step:
 		// 1. doList return OK.
 		// 2. some traps in etcd client, like the limitation of max response body(4MB),
@@ -213,6 +220,7 @@ func (c *KvCacher) refresh(ctx context.Context) {
 	for {
 		nextPeriod := sdcommon.MinWaitInterval
 		if err := c.ListAndWatch(ctx); err != nil {
+			atomic.CompareAndSwapInt64(&c.unsynced, 0, time.Now().UnixNano())
 			retries++
 			nextPeriod = backoff.GetBackoff().Delay(retries)
 		} else {
@@ -495,6 +503,15 @@ func (c *KvCacher) IsReady() bool {
 	}
 }
 
+// Lag returns how long the cache has been out of sync with etcd
+func (c *KvCacher) Lag() time.Duration {
+	since := atomic.LoadInt64(&c.unsynced)
+	if since == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, since))
+}
+
 func (c *KvCacher) reportMetrics(ctx context.Context) {
 	if !config.GetServer().EnablePProf {
 		return
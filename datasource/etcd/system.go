@@ -62,8 +62,7 @@ func (ds *DataSource) DLock(ctx context.Context, request *datasource.DLockReques
 		lock *etcdsync.DLock
 		err  error
 	)
-	ds.lockMux.Lock()
-
+	// do not hold lockMux while waiting, the other IDs can be locked meanwhile
 	id := mux.Type(request.ID)
 	if request.Wait {
 		lock, err = mux.Lock(id)
@@ -71,11 +70,11 @@ func (ds *DataSource) DLock(ctx context.Context, request *datasource.DLockReques
 		lock, err = mux.Try(id)
 	}
 	if err != nil {
-		ds.lockMux.Unlock()
 		return err
 	}
-	ds.locks[request.ID] = lock
 
+	ds.lockMux.Lock()
+	ds.locks[request.ID] = lock
 	ds.lockMux.Unlock()
 	return nil
 }
@@ -33,6 +33,7 @@ import (
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/plugin/uuid"
@@ -205,7 +206,10 @@ func getHeartbeatFunc(ctx context.Context, domainProject string, instancesHbRst
 			InstanceId: element.InstanceId,
 			ErrMessage: "",
 		}
-		_, _, err := serviceUtil.HeartbeatUtil(ctx, domainProject, element.ServiceId, element.InstanceId)
+		err := checkInstanceOwner(ctx, domainProject, element.ServiceId, element.InstanceId)
+		if err == nil {
+			_, _, err = serviceUtil.HeartbeatUtil(ctx, domainProject, element.ServiceId, element.InstanceId)
+		}
 		if err != nil {
 			hbRst.ErrMessage = err.Error()
 			log.Errorf(err, "heartbeat set failed, %s/%s", element.ServiceId, element.InstanceId)
@@ -233,6 +237,28 @@ func revokeInstance(ctx context.Context, domainProject string, serviceID string,
 	return nil
 }
 
+//checkInstanceOwner returns ErrForbidden if the account of the request
+//is neither the owner of the instance nor an admin,
+//the owner is read from the cache, so the heartbeats do not add the reads of etcd
+func checkInstanceOwner(ctx context.Context, domainProject string, serviceID string, instanceID string) *pb.Error {
+	if rbacframe.OwnerFromContext(ctx) == "" {
+		return nil
+	}
+	opts := append(serviceUtil.FromContext(ctx),
+		client.WithStrKey(path.GenerateInstanceOwnerKey(domainProject, serviceID, instanceID)))
+	resp, err := kv.Store().InstanceOwner().Search(ctx, opts...)
+	if err != nil {
+		return pb.NewError(pb.ErrUnavailableBackend, err.Error())
+	}
+	if len(resp.Kvs) == 0 {
+		return nil
+	}
+	if !rbacframe.IsOwner(ctx, resp.Kvs[0].Value.(string)) {
+		return pb.NewError(pb.ErrForbidden, rbacframe.ErrNotOwner.Error())
+	}
+	return nil
+}
+
 // governServiceCtrl util
 func getServiceAllVersions(ctx context.Context, serviceKey *pb.MicroServiceKey) ([]string, error) {
 	var versions []string
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/go-chassis/cari/discovery"
 )
@@ -43,6 +44,7 @@ var (
 	newRule            CreateValueFunc = func() interface{} { return new(discovery.ServiceRule) }
 	newDependencyRule  CreateValueFunc = func() interface{} { return new(discovery.MicroServiceDependency) }
 	newDependencyQueue CreateValueFunc = func() interface{} { return new(discovery.ConsumerDependency) }
+	newRevocation      CreateValueFunc = func() interface{} { return new(rbacframe.Revocation) }
 )
 
 // parse
@@ -110,6 +112,7 @@ var (
 	RuleParser            = &CommonParser{newRule, JSONUnmarshal}
 	DependencyRuleParser  = &CommonParser{newDependencyRule, JSONUnmarshal}
 	DependencyQueueParser = &CommonParser{newDependencyQueue, JSONUnmarshal}
+	RevocationParser      = &CommonParser{newRevocation, JSONUnmarshal}
 )
 
 func check(src []byte, dist interface{}) error {
@@ -0,0 +1,131 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+)
+
+func (ds *DataSource) CreateWebhook(ctx context.Context, hook *webhook.Webhook) error {
+	value, err := json.Marshal(hook)
+	if err != nil {
+		log.Error("webhook info is invalid", err)
+		return err
+	}
+	key := path.GenerateWebhookKey(hook.Domain+path.SPLIT+hook.Project, hook.ID)
+	err = client.PutBytes(ctx, key, value)
+	if err != nil {
+		log.Error("can not save webhook info", err)
+		return err
+	}
+	log.Info("create new webhook: " + hook.ID)
+	return nil
+}
+
+func (ds *DataSource) GetWebhook(ctx context.Context, domainProject, id string) (*webhook.Webhook, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateWebhookKey(domainProject, id)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrWebhookNotExist
+	}
+	hook := &webhook.Webhook{}
+	err = json.Unmarshal(resp.Kvs[0].Value, hook)
+	if err != nil {
+		log.Error("webhook info format invalid", err)
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (ds *DataSource) ListWebhook(ctx context.Context, domainProject string) ([]*webhook.Webhook, error) {
+	key := path.GetWebhookRootKey(domainProject) + path.SPLIT
+	if len(domainProject) == 0 {
+		key = path.GetWebhookRootKey("")
+	}
+	kvs, _, err := client.List(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	hooks := make([]*webhook.Webhook, 0, len(kvs))
+	for _, kv := range kvs {
+		hook := &webhook.Webhook{}
+		err = json.Unmarshal(kv.Value, hook)
+		if err != nil {
+			log.Error("webhook info format invalid", err)
+			continue //do not fail if some webhook is invalid
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+func (ds *DataSource) DeleteWebhook(ctx context.Context, domainProject, id string) error {
+	ok, err := client.Delete(ctx, path.GenerateWebhookKey(domainProject, id))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return datasource.ErrWebhookNotExist
+	}
+	_, err = client.Instance().Do(ctx, client.DEL,
+		client.WithStrKey(path.GenerateDeadLetterKey(domainProject, id, "")), client.WithPrefix())
+	return err
+}
+
+func (ds *DataSource) PutDeadLetter(ctx context.Context, letter *webhook.DeadLetter) error {
+	value, err := json.Marshal(letter)
+	if err != nil {
+		log.Error("dead letter is invalid", err)
+		return err
+	}
+	key := path.GenerateDeadLetterKey(letter.Domain+path.SPLIT+letter.Project, letter.WebhookID, letter.ID)
+	return client.PutBytes(ctx, key, value)
+}
+
+func (ds *DataSource) ListDeadLetter(ctx context.Context, domainProject, webhookID string) ([]*webhook.DeadLetter, error) {
+	kvs, _, err := client.List(ctx, path.GenerateDeadLetterKey(domainProject, webhookID, ""))
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]*webhook.DeadLetter, 0, len(kvs))
+	for _, kv := range kvs {
+		letter := &webhook.DeadLetter{}
+		err = json.Unmarshal(kv.Value, letter)
+		if err != nil {
+			log.Error("dead letter format invalid", err)
+			continue
+		}
+		letters = append(letters, letter)
+	}
+	return letters, nil
+}
+
+func (ds *DataSource) DeleteDeadLetter(ctx context.Context, domainProject, webhookID, id string) error {
+	_, err := client.Delete(ctx, path.GenerateDeadLetterKey(domainProject, webhookID, id))
+	return err
+}
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+var (
+	ErrGovPolicyNotExist  = errors.New("governance policy does not exist")
+	ErrGovHistoryConflict = errors.New("governance policy history revision already exists")
+	ErrGovRolloutNotExist = errors.New("governance policy is not in rollout")
+	ErrGovRolloutConflict = errors.New("governance policy rollout is changed concurrently")
+)
+
+// GovManager contains the governance policies persistence,
+// every change of the policies in a project bumps the revision of the project
+type GovManager interface {
+	// PutGovPolicy creates or overwrites the policy, returns the new revision of the project
+	PutGovPolicy(ctx context.Context, project string, p *gov.Policy) (int64, error)
+	GetGovPolicy(ctx context.Context, project, id string) (*gov.Policy, error)
+	// ListGovPolicy returns the policies of the project, or the policies of kind if kind is not empty
+	ListGovPolicy(ctx context.Context, project, kind string) ([]*gov.Policy, error)
+	// DeleteGovPolicy deletes the policy, returns the new revision of the project
+	DeleteGovPolicy(ctx context.Context, project, id string) (int64, error)
+	// GetGovRevision returns the latest revision of the project, 0 if never changed
+	GetGovRevision(ctx context.Context, project string) (int64, error)
+	// AddGovHistory appends the history of the policy, the histories are never changed once added,
+	// returns ErrGovHistoryConflict if the revision of the history exists
+	AddGovHistory(ctx context.Context, project string, h *gov.History) error
+	// ListGovHistory returns the histories of the policy in the order of revision
+	ListGovHistory(ctx context.Context, project, id string) ([]*gov.History, error)
+	// PutGovRollout creates or overwrites the rollout of the policy if the stored one is of r.Revision,
+	// 0 means not exist, then it increases r.Revision and bumps the revision of the project,
+	// returns ErrGovRolloutConflict if the rollout is changed after read
+	PutGovRollout(ctx context.Context, project string, r *gov.Rollout) error
+	GetGovRollout(ctx context.Context, project, id string) (*gov.Rollout, error)
+	// ListGovRollout returns the rollouts of the project, or the rollouts of all the projects if project is empty
+	ListGovRollout(ctx context.Context, project string) ([]*gov.Rollout, error)
+	// DeleteGovRollout deletes the rollout of the policy, it bumps the revision of the project
+	DeleteGovRollout(ctx context.Context, project, id string) error
+}
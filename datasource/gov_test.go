@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGovPolicy(t *testing.T) {
+	const project = "gov_policy_test"
+	var rev int64
+
+	t.Run("put policies should bump the revision", func(t *testing.T) {
+		for _, p := range []*gov.Policy{
+			{GovernancePolicy: &gov.GovernancePolicy{ID: "p1", Name: "scene-a"}, Kind: "match-group"},
+			{GovernancePolicy: &gov.GovernancePolicy{ID: "p2", Name: "scene-a"}, Kind: "retry"},
+		} {
+			r, err := datasource.Instance().PutGovPolicy(getContext(), project, p)
+			assert.NoError(t, err)
+			assert.True(t, r > rev)
+			rev = r
+		}
+		r, err := datasource.Instance().GetGovRevision(getContext(), project)
+		assert.NoError(t, err)
+		assert.Equal(t, rev, r)
+	})
+
+	t.Run("get and list policies", func(t *testing.T) {
+		p, err := datasource.Instance().GetGovPolicy(getContext(), project, "p2")
+		assert.NoError(t, err)
+		assert.Equal(t, "retry", p.Kind)
+		assert.Equal(t, rev, p.Revision)
+
+		policies, err := datasource.Instance().ListGovPolicy(getContext(), project, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(policies))
+
+		policies, err = datasource.Instance().ListGovPolicy(getContext(), project, "match-group")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(policies))
+		assert.Equal(t, "p1", policies[0].ID)
+
+		_, err = datasource.Instance().GetGovPolicy(getContext(), project, "not-exist")
+		assert.Equal(t, datasource.ErrGovPolicyNotExist, err)
+	})
+
+	t.Run("delete policies should bump the revision", func(t *testing.T) {
+		for _, id := range []string{"p1", "p2"} {
+			r, err := datasource.Instance().DeleteGovPolicy(getContext(), project, id)
+			assert.NoError(t, err)
+			assert.True(t, r > rev)
+			rev = r
+		}
+		_, err := datasource.Instance().DeleteGovPolicy(getContext(), project, "p1")
+		assert.Equal(t, datasource.ErrGovPolicyNotExist, err)
+
+		r, err := datasource.Instance().GetGovRevision(getContext(), project)
+		assert.NoError(t, err)
+		assert.Equal(t, rev, r)
+	})
+}
+
+func TestGovHistory(t *testing.T) {
+	const project = "gov_history_test"
+
+	t.Run("add histories should keep the order of revision", func(t *testing.T) {
+		for _, rev := range []int64{2, 1, 10} {
+			err := datasource.Instance().AddGovHistory(getContext(), project, &gov.History{
+				ID:       "p1",
+				Revision: rev,
+				Action:   "update",
+				Policy:   &gov.Policy{GovernancePolicy: &gov.GovernancePolicy{ID: "p1"}, Kind: "retry"},
+			})
+			assert.NoError(t, err)
+		}
+		histories, err := datasource.Instance().ListGovHistory(getContext(), project, "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(histories))
+		assert.Equal(t, int64(1), histories[0].Revision)
+		assert.Equal(t, int64(2), histories[1].Revision)
+		assert.Equal(t, int64(10), histories[2].Revision)
+		assert.Equal(t, "retry", histories[2].Policy.Kind)
+
+		histories, err = datasource.Instance().ListGovHistory(getContext(), project, "p2")
+		assert.NoError(t, err)
+		assert.Empty(t, histories)
+	})
+
+	t.Run("add an existing revision should fail", func(t *testing.T) {
+		err := datasource.Instance().AddGovHistory(getContext(), project, &gov.History{ID: "p1", Revision: 1, Action: "delete"})
+		assert.Equal(t, datasource.ErrGovHistoryConflict, err)
+		histories, err := datasource.Instance().ListGovHistory(getContext(), project, "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, "update", histories[0].Action)
+	})
+}
+
+func TestGovRollout(t *testing.T) {
+	const project = "gov_rollout_test"
+
+	t.Run("put rollouts should bump the revision", func(t *testing.T) {
+		rev, err := datasource.Instance().GetGovRevision(getContext(), project)
+		assert.NoError(t, err)
+		for _, id := range []string{"p1", "p2"} {
+			err = datasource.Instance().PutGovRollout(getContext(), project, &gov.Rollout{
+				ID:     id,
+				Stages: []*gov.RolloutStage{{Percentage: 10}},
+				Status: gov.RolloutProgressing,
+			})
+			assert.NoError(t, err)
+		}
+		r, err := datasource.Instance().GetGovRevision(getContext(), project)
+		assert.NoError(t, err)
+		assert.True(t, r > rev)
+	})
+
+	t.Run("get and list rollouts", func(t *testing.T) {
+		r, err := datasource.Instance().GetGovRollout(getContext(), project, "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, gov.RolloutProgressing, r.Status)
+		assert.Equal(t, 10, r.Current().Percentage)
+
+		rollouts, err := datasource.Instance().ListGovRollout(getContext(), project)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(rollouts))
+
+		rollouts, err = datasource.Instance().ListGovRollout(getContext(), "")
+		assert.NoError(t, err)
+		assert.True(t, len(rollouts) >= 2)
+
+		_, err = datasource.Instance().GetGovRollout(getContext(), project, "not-exist")
+		assert.Equal(t, datasource.ErrGovRolloutNotExist, err)
+	})
+
+	t.Run("put a stale rollout should conflict", func(t *testing.T) {
+		r, err := datasource.Instance().GetGovRollout(getContext(), project, "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), r.Revision)
+		stale := *r
+		r.Stage = 1
+		assert.NoError(t, datasource.Instance().PutGovRollout(getContext(), project, r))
+		assert.Equal(t, int64(2), r.Revision)
+		assert.Equal(t, datasource.ErrGovRolloutConflict, datasource.Instance().PutGovRollout(getContext(), project, &stale))
+		assert.Equal(t, datasource.ErrGovRolloutConflict, datasource.Instance().PutGovRollout(getContext(), project,
+			&gov.Rollout{ID: "p2", Stages: []*gov.RolloutStage{{Percentage: 10}}}))
+	})
+
+	t.Run("delete rollouts", func(t *testing.T) {
+		for _, id := range []string{"p1", "p2"} {
+			assert.NoError(t, datasource.Instance().DeleteGovRollout(getContext(), project, id))
+		}
+		assert.Equal(t, datasource.ErrGovRolloutNotExist, datasource.Instance().DeleteGovRollout(getContext(), project, "p1"))
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/config"
 	"github.com/apache/servicecomb-service-center/server/core"
@@ -1391,3 +1392,104 @@ func TestInstance_Unregister(t *testing.T) {
 		assert.NotEqual(t, pb.ResponseSuccess, resp.Response.GetCode())
 	})
 }
+
+func TestInstance_Owner(t *testing.T) {
+	var (
+		serviceId  string
+		instanceId string
+	)
+	withAccount := func(name string, roles ...interface{}) context.Context {
+		return rbacframe.NewContext(getContext(), map[string]interface{}{
+			rbacframe.ClaimsUser:  name,
+			rbacframe.ClaimsRoles: roles,
+		})
+	}
+	owner := withAccount("owner_instance_ms", "developer")
+	other := withAccount("other_instance_ms", "developer")
+	admin := withAccount("root", rbacframe.RoleAdmin)
+
+	t.Run("register instance by account", func(t *testing.T) {
+		respCreateService, err := datasource.Instance().RegisterService(getContext(), &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "owner_instance_ms",
+				ServiceName: "owner_instance_service_ms",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, respCreateService.Response.GetCode())
+		serviceId = respCreateService.ServiceId
+
+		respCreateInstance, err := datasource.Instance().RegisterInstance(owner, &pb.RegisterInstanceRequest{
+			Instance: &pb.MicroServiceInstance{
+				ServiceId: serviceId,
+				HostName:  "UT-HOST-MS",
+				Endpoints: []string{
+					"owner:127.0.0.2:8080",
+				},
+				Status: pb.MSI_UP,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, respCreateInstance.Response.GetCode())
+		instanceId = respCreateInstance.InstanceId
+	})
+
+	t.Run("other account should be forbidden", func(t *testing.T) {
+		respHeartbeat, err := datasource.Instance().Heartbeat(other, &pb.HeartbeatRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ErrForbidden, respHeartbeat.Response.GetCode())
+
+		respStatus, err := datasource.Instance().UpdateInstanceStatus(other, &pb.UpdateInstanceStatusRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+			Status:     pb.MSI_DOWN,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ErrForbidden, respStatus.Response.GetCode())
+
+		respProps, err := datasource.Instance().UpdateInstanceProperties(other, &pb.UpdateInstancePropsRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+			Properties: map[string]string{"test": "test"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ErrForbidden, respProps.Response.GetCode())
+
+		respUnregister, err := datasource.Instance().UnregisterInstance(other, &pb.UnregisterInstanceRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ErrForbidden, respUnregister.Response.GetCode())
+	})
+
+	t.Run("owner and admin should pass", func(t *testing.T) {
+		respHeartbeat, err := datasource.Instance().Heartbeat(owner, &pb.HeartbeatRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, respHeartbeat.Response.GetCode())
+
+		respStatus, err := datasource.Instance().UpdateInstanceStatus(admin, &pb.UpdateInstanceStatusRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+			Status:     pb.MSI_DOWN,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, respStatus.Response.GetCode())
+
+		respUnregister, err := datasource.Instance().UnregisterInstance(owner, &pb.UnregisterInstanceRequest{
+			ServiceId:  serviceId,
+			InstanceId: instanceId,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, respUnregister.Response.GetCode())
+	})
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func (ds *DataSource) CreateAPIKey(ctx context.Context, key *rbacframe.APIKey) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionAPIKey, key)
+	if err != nil {
+		log.Error("failed to create api key", err)
+		return err
+	}
+	log.Infof("succeed to create new api key [%s] of account [%s]", key.ID, key.Account)
+	return nil
+}
+
+func (ds *DataSource) GetAPIKey(ctx context.Context, id string) (*rbacframe.APIKey, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionAPIKey, mutil.NewFilter(mutil.ID(id)))
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrAPIKeyNotExist
+	}
+	var key rbacframe.APIKey
+	err = result.Decode(&key)
+	if err != nil {
+		log.Error("failed to decode api key", err)
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (ds *DataSource) ListAPIKey(ctx context.Context, account string) ([]*rbacframe.APIKey, error) {
+	filter := mutil.NewFilter()
+	if len(account) > 0 {
+		filter = mutil.NewFilter(mutil.Account(account))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var keys []*rbacframe.APIKey
+	for cursor.Next(ctx) {
+		var key rbacframe.APIKey
+		err = cursor.Decode(&key)
+		if err != nil {
+			log.Error("failed to decode api key", err)
+			continue
+		}
+		keys = append(keys, &key)
+	}
+	return keys, nil
+}
+
+func (ds *DataSource) DeleteAPIKey(ctx context.Context, account, id string) error {
+	result, err := client.GetMongoClient().Delete(ctx, model.CollectionAPIKey,
+		mutil.NewFilter(mutil.ID(id), mutil.Account(account)))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return datasource.ErrAPIKeyNotExist
+	}
+	return nil
+}
+
+func (ds *DataSource) UpdateAPIKeyLastUsed(ctx context.Context, id, lastUsedTime string) error {
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionAPIKey, mutil.NewFilter(mutil.ID(id)),
+		mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.LastUsedTime(lastUsedTime)))))
+	return err
+}
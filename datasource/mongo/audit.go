@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+func (ds *DataSource) PutAuditRecords(ctx context.Context, rs []*audit.Record, ttl time.Duration) error {
+	// removed by the ttl index of expire_time
+	expireTime := time.Now().Add(ttl)
+	docs := make([]interface{}, 0, len(rs))
+	for _, r := range rs {
+		r.ExpireTime = expireTime
+		docs = append(docs, r)
+	}
+	_, err := client.GetMongoClient().BatchInsert(ctx, model.CollectionAudit, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		log.Error("failed to put audit records", err)
+	}
+	return err
+}
+
+func (ds *DataSource) ListAuditRecord(ctx context.Context, q *audit.Query) ([]*audit.Record, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAudit, auditFilter(q),
+		options.Find().SetSort(bson.M{model.ColumnTimestamp: -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var records []*audit.Record
+	for cursor.Next(ctx) {
+		var r audit.Record
+		err = cursor.Decode(&r)
+		if err != nil {
+			log.Error("failed to decode audit record", err)
+			continue
+		}
+		// the targets are filtered here
+		if !q.Match(&r) {
+			continue
+		}
+		records = append(records, &r)
+		if q != nil && q.Limit > 0 && len(records) >= q.Limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+func auditFilter(q *audit.Query) bson.M {
+	filter := mutil.NewFilter()
+	if q == nil {
+		return filter
+	}
+	for column, value := range map[string]string{
+		model.ColumnDomain:   q.Domain,
+		model.ColumnProject:  q.Project,
+		model.ColumnAccount:  q.Account,
+		model.ColumnResource: q.Resource,
+		model.ColumnVerb:     q.Verb,
+	} {
+		if len(value) > 0 {
+			filter[column] = value
+		}
+	}
+	if q.StatusCode > 0 {
+		filter[model.ColumnStatusCode] = q.StatusCode
+	}
+	timeRange := bson.M{}
+	if q.Start > 0 {
+		timeRange["$gte"] = q.Start
+	}
+	if q.End > 0 {
+		timeRange["$lte"] = q.End
+	}
+	if len(timeRange) > 0 {
+		filter[model.ColumnTimestamp] = timeRange
+	}
+	return filter
+}
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func (ds *DataSource) PutLoginAttempt(ctx context.Context, a *rbacframe.LoginAttempt, ttl time.Duration) error {
+	// removed by the ttl index of expire_time
+	a.ExpireTime = time.Now().Add(ttl)
+	_, err := client.GetMongoClient().GetDB().Collection(model.CollectionLogin).
+		ReplaceOne(ctx, mutil.NewFilter(mutil.ID(a.ID)), a, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("failed to put login attempt", err)
+	}
+	return err
+}
+
+// incRetries is the max times of increasing the failures concurrently
+const incRetries = 5
+
+// IncLoginAttempt increases the failures of the unexpired attempt by $inc,
+// or inserts a new one, the insertion conflicts with the unique id index if
+// the other replica inserted meanwhile or the expired one is not removed yet
+func (ds *DataSource) IncLoginAttempt(ctx context.Context, typ, value string,
+	ttl time.Duration) (*rbacframe.LoginAttempt, error) {
+	id := rbacframe.LoginAttemptID(typ, value)
+	for i := 0; i < incRetries; i++ {
+		now := time.Now()
+		result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionLogin,
+			mutil.NewFilter(mutil.ID(id), func(filter bson.M) {
+				filter[model.ColumnExpireTime] = bson.M{"$gt": now}
+			}),
+			bson.M{"$inc": bson.M{model.ColumnFailures: 1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After))
+		if err != nil {
+			return nil, err
+		}
+		if result.Err() == nil {
+			var a rbacframe.LoginAttempt
+			if err := result.Decode(&a); err != nil {
+				log.Error("failed to decode login attempt", err)
+				return nil, err
+			}
+			return &a, nil
+		}
+		if result.Err() != mongo.ErrNoDocuments {
+			return nil, result.Err()
+		}
+		a := rbacframe.NewLoginAttempt(typ, value)
+		a.Failures = 1
+		a.FirstFailTime = now.Unix()
+		a.ExpireTime = now.Add(ttl)
+		_, err = client.GetMongoClient().Insert(ctx, model.CollectionLogin, a)
+		if err == nil {
+			return a, nil
+		}
+		if !client.IsDuplicateKey(err) {
+			return nil, err
+		}
+		_, err = client.GetMongoClient().Delete(ctx, model.CollectionLogin,
+			mutil.NewFilter(mutil.ID(id), func(filter bson.M) {
+				filter[model.ColumnExpireTime] = bson.M{"$lte": now}
+			}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, datasource.ErrLoginAttemptConflict
+}
+
+func (ds *DataSource) GetLoginAttempt(ctx context.Context, id string) (*rbacframe.LoginAttempt, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionLogin, mutil.NewFilter(mutil.ID(id)))
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrLoginAttemptNotExist
+	}
+	var a rbacframe.LoginAttempt
+	err = result.Decode(&a)
+	if err != nil {
+		log.Error("failed to decode login attempt", err)
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (ds *DataSource) ListLoginAttempt(ctx context.Context) ([]*rbacframe.LoginAttempt, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionLogin, mutil.NewFilter())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var attempts []*rbacframe.LoginAttempt
+	for cursor.Next(ctx) {
+		var a rbacframe.LoginAttempt
+		err = cursor.Decode(&a)
+		if err != nil {
+			log.Error("failed to decode login attempt", err)
+			continue
+		}
+		attempts = append(attempts, &a)
+	}
+	return attempts, nil
+}
+
+func (ds *DataSource) DeleteLoginAttempt(ctx context.Context, id string) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionLogin, mutil.NewFilter(mutil.ID(id)))
+	return err
+}
@@ -20,19 +20,35 @@ package model
 import (
 	"time"
 
+	"github.com/apache/servicecomb-service-center/pkg/quota"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
 const (
-	CollectionAccount  = "account"
-	CollectionService  = "service"
-	CollectionSchema   = "schema"
-	CollectionRule     = "rule"
-	CollectionInstance = "instance"
-	CollectionDep      = "dependency"
-	CollectionRole     = "role"
-	CollectionDomain   = "domain"
-	CollectionProject  = "project"
+	CollectionAccount    = "account"
+	CollectionService    = "service"
+	CollectionSchema     = "schema"
+	CollectionRule       = "rule"
+	CollectionInstance   = "instance"
+	CollectionDep        = "dependency"
+	CollectionRole       = "role"
+	CollectionDomain     = "domain"
+	CollectionProject    = "project"
+	CollectionWebhook    = "webhook"
+	CollectionLetter     = "dead_letter"
+	CollectionAPIKey     = "api_key"
+	CollectionRevocation = "revocation"
+	CollectionLogin      = "login_attempt"
+	CollectionPassword   = "password_state"
+	CollectionAudit      = "audit_record"
+	CollectionQuota      = "quota_limits"
+	CollectionGovPolicy  = "gov_policy"
+	CollectionGovRev     = "gov_revision"
+	CollectionGovHistory = "gov_history"
+	CollectionGovRollout = "gov_rollout"
+	CollectionLeader     = "leader"
+	// CollectionQuotaRsv holds a document of the quota reservations per key
+	CollectionQuotaRsv = "quota_reservation"
 )
 
 const (
@@ -75,6 +91,20 @@ const (
 	ColumnCurrentPassword     = "current_password"
 	ColumnStatus              = "status"
 	ColumnRefreshTime         = "refresh_time"
+	ColumnWebhookID           = "webhook_id"
+	ColumnAccount             = "account"
+	ColumnLastUsedTime        = "last_used_time"
+	ColumnExpireTime          = "expire_time"
+	ColumnCandidate           = "candidate"
+	ColumnFailures            = "failures"
+	ColumnResource            = "resource"
+	ColumnVerb                = "verb"
+	ColumnStatusCode          = "status_code"
+	ColumnTimestamp           = "timestamp"
+	ColumnKind                = "kind"
+	ColumnRevision            = "revision"
+	ColumnRollout             = "rollout"
+	ColumnReservations        = "reservations"
 )
 
 type Service struct {
@@ -105,6 +135,8 @@ type Instance struct {
 	Project     string                   `json:"project,omitempty"`
 	RefreshTime time.Time                `json:"refreshTime,omitempty" bson:"refresh_time"`
 	Instance    *pb.MicroServiceInstance `json:"instance,omitempty"`
+	// Owner is the account registered the instance, empty if rbac is disabled
+	Owner string `json:"owner,omitempty" bson:"owner,omitempty"`
 }
 
 type ConsumerDep struct {
@@ -136,3 +168,48 @@ type Project struct {
 	Domain  string `json:"domain,omitempty"`
 	Project string `json:"project,omitempty"`
 }
+
+// GovPolicy keeps the policy json encoded, the spec of policies is schemaless
+type GovPolicy struct {
+	Project  string `json:"project,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+	Policy   []byte `json:"policy,omitempty"`
+}
+
+// GovHistory keeps the history json encoded, it is unique by the project, id and revision
+type GovHistory struct {
+	Project  string `json:"project,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+	History  []byte `json:"history,omitempty"`
+}
+
+// GovRollout keeps the rollout json encoded, it is unique by the project and id,
+// Revision is the revision of the rollout, the rollout is replaced only if it is not changed
+type GovRollout struct {
+	Project  string `json:"project,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+	Rollout  []byte `json:"rollout,omitempty"`
+}
+
+type GovRevision struct {
+	Project  string `json:"project,omitempty"`
+	Revision int64  `json:"revision,omitempty"`
+}
+
+// Leader is unique by the id, the candidate holds the leadership until the expire time
+type Leader struct {
+	ID         string    `json:"id,omitempty"`
+	Candidate  string    `json:"candidate,omitempty"`
+	ExpireTime time.Time `json:"expireTime,omitempty" bson:"expire_time"`
+}
+
+// QuotaReservation holds the reservations of a quota key, the revision increases on each reservation
+type QuotaReservation struct {
+	ID           string               `json:"id,omitempty"`
+	Revision     int64                `json:"revision,omitempty"`
+	Reservations []*quota.Reservation `json:"reservations,omitempty"`
+}
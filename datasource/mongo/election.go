@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+var candidate = fmt.Sprintf("%s-%d", util.HostName(), os.Getpid())
+
+// Campaign upserts the leader document only if it is held by self or expired,
+// the upsert conflicts with the unique id index if the other one holds it.
+// The expire time is compared with the local clock, so the ttl should be
+// much longer than the clock skew of the replicas
+func (ds *DataSource) Campaign(ctx context.Context, request *datasource.CampaignRequest) error {
+	now := time.Now()
+	filter := mutil.NewFilter(mutil.ID(request.ID), func(filter bson.M) {
+		filter["$or"] = bson.A{
+			bson.M{model.ColumnCandidate: candidate},
+			bson.M{model.ColumnExpireTime: bson.M{"$lt": now}},
+		}
+	})
+	update := bson.M{"$set": bson.M{
+		model.ColumnCandidate:  candidate,
+		model.ColumnExpireTime: now.Add(time.Duration(request.TTL) * time.Second),
+	}}
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionLeader, filter, update, options.Update().SetUpsert(true))
+	if client.IsDuplicateKey(err) {
+		return datasource.ErrNotLeader
+	}
+	return err
+}
+
+// Resign removes the leader document only if it is held by self
+func (ds *DataSource) Resign(ctx context.Context, request *datasource.ResignRequest) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionLeader,
+		mutil.NewFilter(mutil.ID(request.ID), func(filter bson.M) {
+			filter[model.ColumnCandidate] = candidate
+		}))
+	return err
+}
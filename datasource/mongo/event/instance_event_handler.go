@@ -92,6 +92,10 @@ func (h InstanceEventHandler) OnEvent(evt sd.MongoEvent) {
 	if !syncernotify.GetSyncerNotifyCenter().Closed() {
 		NotifySyncerInstanceEvent(evt, microService)
 	}
+	if action != discovery.EVT_INIT {
+		notify.PublishResourceEvent(notify.NewResourceEvent(notify.ResourceInstance, string(action),
+			domainProject, providerInstanceID, microService, instance.Instance))
+	}
 	ctx := util.SetDomainProject(context.Background(), instance.Domain, instance.Project)
 	consumerIDS, _, err := mongo.GetAllConsumerIds(ctx, microService)
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/sd"
 	"github.com/apache/servicecomb-service-center/server/metrics"
+	"github.com/apache/servicecomb-service-center/server/notify"
 )
 
 type SchemaSummaryEventHandler struct {
@@ -46,4 +47,15 @@ func (h *SchemaSummaryEventHandler) OnEvent(evt sd.MongoEvent) {
 		metrics.ReportSchemas(schema.Domain, decreaseOne)
 	default:
 	}
+
+	if action == pb.EVT_INIT {
+		return
+	}
+	var ms *pb.MicroService
+	if cacheService := sd.Store().Service().Cache().Get(schema.ServiceID); cacheService != nil {
+		ms = cacheService.(model.Service).Service
+	}
+	notify.PublishResourceEvent(notify.NewResourceEvent(notify.ResourceSchema, string(action),
+		schema.Domain+"/"+schema.Project, schema.SchemaID, ms,
+		&pb.Schema{SchemaId: schema.SchemaID, Summary: schema.SchemaSummary}))
 }
@@ -0,0 +1,376 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+const govRevisionRewatchInterval = time.Second
+
+// govRevisionChange is the change stream event of the governance revision collection
+type govRevisionChange struct {
+	FullDocument *model.GovRevision `bson:"fullDocument"`
+}
+
+func (ds *DataSource) PutGovPolicy(ctx context.Context, project string, p *gov.Policy) (int64, error) {
+	value, err := json.Marshal(p)
+	if err != nil {
+		log.Error("governance policy is invalid", err)
+		return 0, err
+	}
+	doc := &model.GovPolicy{
+		Project: project,
+		ID:      p.ID,
+		Kind:    p.Kind,
+		Policy:  value,
+	}
+	filter := mutil.NewFilter(mutil.Project(project), mutil.ID(p.ID))
+	_, err = client.GetMongoClient().GetDB().Collection(model.CollectionGovPolicy).
+		ReplaceOne(ctx, filter, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("failed to put governance policy", err)
+		return 0, err
+	}
+	// bump the revision after the write, the watchers woken by it must read the new policy
+	rev, err := incGovRevision(ctx, project)
+	if err != nil {
+		return 0, err
+	}
+	_, err = client.GetMongoClient().Update(ctx, model.CollectionGovPolicy, filter,
+		bson.M{"$set": bson.M{model.ColumnRevision: rev}})
+	if err != nil {
+		log.Error("failed to set the revision of governance policy", err)
+		return 0, err
+	}
+	return rev, nil
+}
+
+func (ds *DataSource) GetGovPolicy(ctx context.Context, project, id string) (*gov.Policy, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionGovPolicy,
+		mutil.NewFilter(mutil.Project(project), mutil.ID(id)))
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrGovPolicyNotExist
+	}
+	var doc model.GovPolicy
+	err = result.Decode(&doc)
+	if err != nil {
+		log.Error("failed to decode governance policy", err)
+		return nil, err
+	}
+	return toGovPolicy(&doc)
+}
+
+func (ds *DataSource) ListGovPolicy(ctx context.Context, project, kind string) ([]*gov.Policy, error) {
+	filter := mutil.NewFilter(mutil.Project(project))
+	if len(kind) > 0 {
+		filter = mutil.NewFilter(mutil.Project(project), mutil.Kind(kind))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionGovPolicy, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var policies []*gov.Policy
+	for cursor.Next(ctx) {
+		var doc model.GovPolicy
+		err = cursor.Decode(&doc)
+		if err != nil {
+			log.Error("failed to decode governance policy", err)
+			continue
+		}
+		p, err := toGovPolicy(&doc)
+		if err != nil {
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (ds *DataSource) DeleteGovPolicy(ctx context.Context, project, id string) (int64, error) {
+	result, err := client.GetMongoClient().Delete(ctx, model.CollectionGovPolicy,
+		mutil.NewFilter(mutil.Project(project), mutil.ID(id)))
+	if err != nil {
+		return 0, err
+	}
+	if result.DeletedCount == 0 {
+		return 0, datasource.ErrGovPolicyNotExist
+	}
+	return incGovRevision(ctx, project)
+}
+
+func (ds *DataSource) GetGovRevision(ctx context.Context, project string) (int64, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionGovRev, mutil.NewFilter(mutil.Project(project)))
+	if err != nil {
+		return 0, err
+	}
+	if result.Err() != nil {
+		return 0, nil
+	}
+	var rev model.GovRevision
+	err = result.Decode(&rev)
+	if err != nil {
+		log.Error("failed to decode governance revision", err)
+		return 0, err
+	}
+	return rev.Revision, nil
+}
+
+func (ds *DataSource) AddGovHistory(ctx context.Context, project string, h *gov.History) error {
+	value, err := json.Marshal(h)
+	if err != nil {
+		log.Error("governance policy history is invalid", err)
+		return err
+	}
+	_, err = client.GetMongoClient().Insert(ctx, model.CollectionGovHistory, &model.GovHistory{
+		Project:  project,
+		ID:       h.ID,
+		Revision: h.Revision,
+		History:  value,
+	})
+	if err != nil {
+		if client.IsDuplicateKey(err) {
+			return datasource.ErrGovHistoryConflict
+		}
+		log.Error("failed to add governance policy history", err)
+		return err
+	}
+	return nil
+}
+
+func (ds *DataSource) ListGovHistory(ctx context.Context, project, id string) ([]*gov.History, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionGovHistory,
+		mutil.NewFilter(mutil.Project(project), mutil.ID(id)),
+		options.Find().SetSort(bson.M{model.ColumnRevision: 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var histories []*gov.History
+	for cursor.Next(ctx) {
+		var doc model.GovHistory
+		err = cursor.Decode(&doc)
+		if err != nil {
+			log.Error("failed to decode governance policy history", err)
+			continue
+		}
+		h := &gov.History{}
+		err = json.Unmarshal(doc.History, h)
+		if err != nil {
+			log.Error("governance policy history format invalid", err)
+			continue
+		}
+		histories = append(histories, h)
+	}
+	return histories, nil
+}
+
+func (ds *DataSource) PutGovRollout(ctx context.Context, project string, r *gov.Rollout) error {
+	next := *r
+	next.Revision++
+	value, err := json.Marshal(&next)
+	if err != nil {
+		log.Error("governance policy rollout is invalid", err)
+		return err
+	}
+	if r.Revision == 0 {
+		_, err = client.GetMongoClient().Insert(ctx, model.CollectionGovRollout, &model.GovRollout{
+			Project:  project,
+			ID:       r.ID,
+			Revision: next.Revision,
+			Rollout:  value,
+		})
+		if client.IsDuplicateKey(err) {
+			return datasource.ErrGovRolloutConflict
+		}
+	} else {
+		var result *mongo.UpdateResult
+		result, err = client.GetMongoClient().GetDB().Collection(model.CollectionGovRollout).UpdateOne(ctx,
+			mutil.NewFilter(mutil.Project(project), mutil.ID(r.ID), func(filter bson.M) {
+				filter[model.ColumnRevision] = r.Revision
+			}),
+			bson.M{"$set": bson.M{model.ColumnRevision: next.Revision, model.ColumnRollout: value}})
+		if err == nil && result.MatchedCount == 0 {
+			return datasource.ErrGovRolloutConflict
+		}
+	}
+	if err != nil {
+		log.Error("failed to put governance policy rollout", err)
+		return err
+	}
+	r.Revision = next.Revision
+	_, err = incGovRevision(ctx, project)
+	return err
+}
+
+func (ds *DataSource) GetGovRollout(ctx context.Context, project, id string) (*gov.Rollout, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionGovRollout,
+		mutil.NewFilter(mutil.Project(project), mutil.ID(id)))
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrGovRolloutNotExist
+	}
+	var doc model.GovRollout
+	err = result.Decode(&doc)
+	if err != nil {
+		log.Error("failed to decode governance policy rollout", err)
+		return nil, err
+	}
+	return toGovRollout(&doc)
+}
+
+func (ds *DataSource) ListGovRollout(ctx context.Context, project string) ([]*gov.Rollout, error) {
+	filter := mutil.NewFilter()
+	if len(project) > 0 {
+		filter = mutil.NewFilter(mutil.Project(project))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionGovRollout, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var rollouts []*gov.Rollout
+	for cursor.Next(ctx) {
+		var doc model.GovRollout
+		err = cursor.Decode(&doc)
+		if err != nil {
+			log.Error("failed to decode governance policy rollout", err)
+			continue
+		}
+		r, err := toGovRollout(&doc)
+		if err != nil {
+			continue
+		}
+		rollouts = append(rollouts, r)
+	}
+	return rollouts, nil
+}
+
+func (ds *DataSource) DeleteGovRollout(ctx context.Context, project, id string) error {
+	result, err := client.GetMongoClient().Delete(ctx, model.CollectionGovRollout,
+		mutil.NewFilter(mutil.Project(project), mutil.ID(id)))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return datasource.ErrGovRolloutNotExist
+	}
+	_, err = incGovRevision(ctx, project)
+	return err
+}
+
+func incGovRevision(ctx context.Context, project string) (int64, error) {
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionGovRev,
+		mutil.NewFilter(mutil.Project(project)), bson.M{"$inc": bson.M{model.ColumnRevision: 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+	if err != nil {
+		return 0, err
+	}
+	var rev model.GovRevision
+	err = result.Decode(&rev)
+	if err != nil {
+		log.Error("failed to increase governance revision", err)
+		return 0, err
+	}
+	return rev.Revision, nil
+}
+
+// watchGovRevision propagates the governance changes made by other replicas,
+// every (re)watch wakes up all the watchers, so the changes missed in between are re-checked
+func watchGovRevision() {
+	gopool.Go(func(ctx context.Context) {
+		for {
+			if err := doWatchGovRevision(ctx); err != nil {
+				log.Error("watch governance revision failed", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(govRevisionRewatchInterval):
+			}
+		}
+	})
+}
+
+func doWatchGovRevision(ctx context.Context) error {
+	stream, err := client.GetMongoClient().Watch(ctx, model.CollectionGovRev, mongo.Pipeline{},
+		options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+	notify.PublishGovRevisionEvent(notify.NewGovRevisionEvent("", 0))
+	for stream.Next(ctx) {
+		var change govRevisionChange
+		if err := bson.Unmarshal(stream.Current, &change); err != nil {
+			log.Error("failed to decode governance revision change", err)
+			continue
+		}
+		if change.FullDocument == nil {
+			continue
+		}
+		notify.PublishGovRevisionEvent(notify.NewGovRevisionEvent(change.FullDocument.Project,
+			change.FullDocument.Revision))
+	}
+	return stream.Err()
+}
+
+func toGovPolicy(doc *model.GovPolicy) (*gov.Policy, error) {
+	p := &gov.Policy{}
+	err := json.Unmarshal(doc.Policy, p)
+	if err != nil {
+		log.Error("governance policy format invalid", err)
+		return nil, err
+	}
+	if p.GovernancePolicy == nil {
+		p.GovernancePolicy = &gov.GovernancePolicy{}
+	}
+	p.Revision = doc.Revision
+	return p, nil
+}
+
+func toGovRollout(doc *model.GovRollout) (*gov.Rollout, error) {
+	r := &gov.Rollout{}
+	err := json.Unmarshal(doc.Rollout, r)
+	if err != nil {
+		log.Error("governance policy rollout format invalid", err)
+		return nil, err
+	}
+	return r, nil
+}
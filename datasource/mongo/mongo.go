@@ -77,6 +77,9 @@ func (ds *DataSource) initialize() error {
 	EnsureDB()
 	// init cache
 	ds.initStore()
+	registerHealthChecks()
+	watchRevocation()
+	watchGovRevision()
 	return nil
 }
 
@@ -113,6 +116,16 @@ func EnsureDB() {
 	EnsureRule()
 	EnsureSchema()
 	EnsureDep()
+	EnsureWebhook()
+	EnsureAPIKey()
+	EnsureRevocation()
+	EnsureLoginAttempt()
+	EnsurePasswordState()
+	EnsureAuditRecord()
+	EnsureQuotaLimits()
+	EnsureGovPolicy()
+	EnsureLeader()
+	EnsureQuotaReservation()
 }
 
 func EnsureService() {
@@ -206,6 +219,78 @@ func EnsureDep() {
 	}
 }
 
+func EnsureWebhook() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionWebhook, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	webhookIndex := mutil.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnID)
+	webhookIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionWebhook, []mongo.IndexModel{webhookIndex})
+	wrapCreateIndexesError(err)
+
+	err = client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionLetter, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	letterIndex := mutil.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnWebhookID)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionLetter, []mongo.IndexModel{letterIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureAPIKey() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionAPIKey, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	idIndex := mutil.BuildIndexDoc(model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+	accountIndex := mutil.BuildIndexDoc(model.ColumnAccount)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionAPIKey, []mongo.IndexModel{idIndex, accountIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureRevocation() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionRevocation, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	idIndex := mutil.BuildIndexDoc(model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionRevocation, []mongo.IndexModel{idIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureLoginAttempt() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionLogin, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	idIndex := mutil.BuildIndexDoc(model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+	expireIndex := mutil.BuildIndexDoc(model.ColumnExpireTime)
+	expireIndex.Options = options.Index().SetExpireAfterSeconds(0)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionLogin, []mongo.IndexModel{idIndex, expireIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsurePasswordState() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionPassword, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	accountIndex := mutil.BuildIndexDoc(model.ColumnAccount)
+	accountIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionPassword, []mongo.IndexModel{accountIndex})
+	wrapCreateIndexesError(err)
+}
+
 func wrapCreateCollectionError(err error) {
 	if err != nil {
 		// commandError can be returned by any operation
@@ -236,3 +321,87 @@ func (ds *DataSource) initStore() {
 	sd.Store().Run()
 	<-sd.Store().Ready()
 }
+
+func EnsureAuditRecord() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionAudit, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	timeIndex := mutil.BuildIndexDoc(model.ColumnTimestamp)
+	expireIndex := mutil.BuildIndexDoc(model.ColumnExpireTime)
+	expireIndex.Options = options.Index().SetExpireAfterSeconds(0)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionAudit, []mongo.IndexModel{timeIndex, expireIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureQuotaLimits() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionQuota, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	domainProjectIndex := mutil.BuildIndexDoc(model.ColumnDomain, model.ColumnProject)
+	domainProjectIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionQuota, []mongo.IndexModel{domainProjectIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureGovPolicy() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionGovPolicy, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	idIndex := mutil.BuildIndexDoc(model.ColumnProject, model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+	kindIndex := mutil.BuildIndexDoc(model.ColumnProject, model.ColumnKind)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionGovPolicy, []mongo.IndexModel{idIndex, kindIndex})
+	wrapCreateIndexesError(err)
+
+	err = client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionGovRev, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	projectIndex := mutil.BuildIndexDoc(model.ColumnProject)
+	projectIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionGovRev, []mongo.IndexModel{projectIndex})
+	wrapCreateIndexesError(err)
+
+	err = client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionGovHistory, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	historyIndex := mutil.BuildIndexDoc(model.ColumnProject, model.ColumnID, model.ColumnRevision)
+	historyIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionGovHistory, []mongo.IndexModel{historyIndex})
+	wrapCreateIndexesError(err)
+
+	err = client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionGovRollout, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	rolloutIndex := mutil.BuildIndexDoc(model.ColumnProject, model.ColumnID)
+	rolloutIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionGovRollout, []mongo.IndexModel{rolloutIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureLeader() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionLeader, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	idIndex := mutil.BuildIndexDoc(model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionLeader, []mongo.IndexModel{idIndex})
+	wrapCreateIndexesError(err)
+}
+
+func EnsureQuotaReservation() {
+	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), model.CollectionQuotaRsv, options.CreateCollection().SetValidator(nil))
+	wrapCreateCollectionError(err)
+
+	idIndex := mutil.BuildIndexDoc(model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+
+	err = client.GetMongoClient().CreateIndexes(context.Background(), model.CollectionQuotaRsv, []mongo.IndexModel{idIndex})
+	wrapCreateIndexesError(err)
+}
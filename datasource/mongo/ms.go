@@ -45,6 +45,7 @@ import (
 	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
@@ -901,7 +902,8 @@ func (ds *DataSource) modifySchemas(ctx context.Context, service *discovery.Micr
 	if !ds.isSchemaEditable(service) {
 		if len(service.Schemas) == 0 {
 			res := quota.NewApplyQuotaResource(quota.TypeSchema, util.ParseDomainProject(ctx), serviceID, int64(len(nonExistSchemaIds)))
-			errQuota := quota.Apply(ctx, res)
+			release, errQuota := quota.Acquire(ctx, res)
+			defer release()
 			if errQuota != nil {
 				log.Error(fmt.Sprintf("modify service[%s] schemas failed, operator: %s", serviceID, remoteIP), errQuota)
 				return errQuota
@@ -953,7 +955,8 @@ func (ds *DataSource) modifySchemas(ctx context.Context, service *discovery.Micr
 		quotaSize := len(needAddSchemas) - len(needDeleteSchemas)
 		if quotaSize > 0 {
 			res := quota.NewApplyQuotaResource(quota.TypeSchema, util.ParseDomainProject(ctx), serviceID, int64(quotaSize))
-			err := quota.Apply(ctx, res)
+			release, err := quota.Acquire(ctx, res)
+			defer release()
 			if err != nil {
 				log.Error(fmt.Sprintf("modify service[%s] schemas failed, operator: %s", serviceID, remoteIP), err)
 				return err
@@ -1111,7 +1114,8 @@ func (ds *DataSource) AddRule(ctx context.Context, request *discovery.AddService
 		return &discovery.AddServiceRulesResponse{Response: discovery.CreateResponse(discovery.ErrServiceNotExists, "Service does not exist")}, nil
 	}
 	res := quota.NewApplyQuotaResource(quota.TypeRule, util.ParseDomainProject(ctx), request.ServiceId, int64(len(request.Rules)))
-	errQuota := quota.Apply(ctx, res)
+	release, errQuota := quota.Acquire(ctx, res)
+	defer release()
 	if errQuota != nil {
 		log.Error(fmt.Sprintf("add service[%s] rule failed, operator: %s", request.ServiceId, remoteIP), errQuota)
 		response := &discovery.AddServiceRulesResponse{
@@ -1739,6 +1743,13 @@ func (ds *DataSource) UpdateInstanceStatus(ctx context.Context, request *discove
 		}, nil
 	}
 
+	if !rbacframe.IsOwner(ctx, instance.Owner) {
+		log.Error(fmt.Sprintf("update instance %s status failed", updateStatusFlag), rbacframe.ErrNotOwner)
+		return &discovery.UpdateInstanceStatusResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, rbacframe.ErrNotOwner.Error()),
+		}, nil
+	}
+
 	copyInstanceRef := *instance
 	copyInstanceRef.Instance.Status = request.Status
 	setFilter := mutil.NewFilter(
@@ -1782,6 +1793,13 @@ func (ds *DataSource) UpdateInstanceProperties(ctx context.Context, request *dis
 		}, nil
 	}
 
+	if !rbacframe.IsOwner(ctx, instance.Owner) {
+		log.Error(fmt.Sprintf("update instance %s properties failed", instanceFlag), rbacframe.ErrNotOwner)
+		return &discovery.UpdateInstancePropsResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, rbacframe.ErrNotOwner.Error()),
+		}, nil
+	}
+
 	copyInstanceRef := *instance
 	copyInstanceRef.Instance.Properties = request.Properties
 
@@ -1816,6 +1834,13 @@ func (ds *DataSource) UnregisterInstance(ctx context.Context, request *discovery
 
 	instanceFlag := util.StringJoin([]string{serviceID, instanceID}, "/")
 
+	if err := checkInstanceOwner(ctx, serviceID, instanceID); err != nil {
+		log.Error(fmt.Sprintf("unregister instance failed, instance %s, operator %s", instanceFlag, remoteIP), err)
+		return &discovery.UnregisterInstanceResponse{
+			Response: discovery.CreateResponseWithSCErr(err),
+		}, nil
+	}
+
 	filter := mutil.NewBasicFilter(ctx, mutil.InstanceServiceID(serviceID), mutil.InstanceInstanceID(instanceID))
 	result, err := client.GetMongoClient().Delete(ctx, model.CollectionInstance, filter)
 	if err != nil || result.DeletedCount == 0 {
@@ -1834,7 +1859,10 @@ func (ds *DataSource) UnregisterInstance(ctx context.Context, request *discovery
 func (ds *DataSource) Heartbeat(ctx context.Context, request *discovery.HeartbeatRequest) (*discovery.HeartbeatResponse, error) {
 	remoteIP := util.GetIPFromContext(ctx)
 	instanceFlag := util.StringJoin([]string{request.ServiceId, request.InstanceId}, "/")
-	err := KeepAliveLease(ctx, request)
+	err := checkInstanceOwner(ctx, request.ServiceId, request.InstanceId)
+	if err == nil {
+		err = KeepAliveLease(ctx, request)
+	}
 	if err != nil {
 		log.Error(fmt.Sprintf("heartbeat failed, instance %s operator %s", instanceFlag, remoteIP), err)
 		resp := &discovery.HeartbeatResponse{
@@ -1944,6 +1972,7 @@ func registryInstance(ctx context.Context, request *discovery.RegisterInstanceRe
 		Project:     project,
 		RefreshTime: time.Now(),
 		Instance:    instance,
+		Owner:       rbacframe.OwnerFromContext(ctx),
 	}
 
 	insertRes, err := client.GetMongoClient().Insert(ctx, model.CollectionInstance, data)
@@ -2190,6 +2219,26 @@ func KeepAliveLease(ctx context.Context, request *discovery.HeartbeatRequest) *d
 	return nil
 }
 
+//checkInstanceOwner returns ErrForbidden if the account of the request
+//is neither the owner of the instance nor an admin
+func checkInstanceOwner(ctx context.Context, serviceID string, instanceID string) *discovery.Error {
+	if rbacframe.OwnerFromContext(ctx) == "" {
+		return nil
+	}
+	filter := mutil.NewBasicFilter(ctx, mutil.InstanceServiceID(serviceID), mutil.InstanceInstanceID(instanceID))
+	instance, err := dao.GetInstance(ctx, filter)
+	if err != nil {
+		return discovery.NewError(discovery.ErrUnavailableBackend, err.Error())
+	}
+	if instance == nil {
+		return nil
+	}
+	if !rbacframe.IsOwner(ctx, instance.Owner) {
+		return discovery.NewError(discovery.ErrForbidden, rbacframe.ErrNotOwner.Error())
+	}
+	return nil
+}
+
 func getHeartbeatFunc(ctx context.Context, domainProject string, instancesHbRst chan<- *discovery.InstanceHbRst, element *discovery.HeartbeatSetElement) func(context.Context) {
 	return func(_ context.Context) {
 		hbRst := &discovery.InstanceHbRst{
@@ -2203,7 +2252,10 @@ func getHeartbeatFunc(ctx context.Context, domainProject string, instancesHbRst
 			ServiceId:  element.ServiceId,
 		}
 
-		err := KeepAliveLease(ctx, req)
+		err := checkInstanceOwner(ctx, element.ServiceId, element.InstanceId)
+		if err == nil {
+			err = KeepAliveLease(ctx, req)
+		}
 		if err != nil {
 			hbRst.ErrMessage = err.Error()
 			log.Error(fmt.Sprintf("heartbeat set failed %s %s", element.ServiceId, element.InstanceId), err)
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func (ds *DataSource) PutPasswordState(ctx context.Context, s *rbacframe.PasswordState) error {
+	_, err := client.GetMongoClient().GetDB().Collection(model.CollectionPassword).
+		ReplaceOne(ctx, mutil.NewFilter(mutil.Account(s.Account)), s, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("failed to put password state", err)
+	}
+	return err
+}
+
+func (ds *DataSource) GetPasswordState(ctx context.Context, account string) (*rbacframe.PasswordState, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionPassword, mutil.NewFilter(mutil.Account(account)))
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrPasswordStateNotExist
+	}
+	var s rbacframe.PasswordState
+	err = result.Decode(&s)
+	if err != nil {
+		log.Error("failed to decode password state", err)
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (ds *DataSource) DeletePasswordState(ctx context.Context, account string) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionPassword, mutil.NewFilter(mutil.Account(account)))
+	return err
+}
@@ -0,0 +1,195 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/dao"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func (ds *DataSource) PutQuotaLimits(ctx context.Context, l *quota.Limits) error {
+	_, err := client.GetMongoClient().GetDB().Collection(model.CollectionQuota).
+		ReplaceOne(ctx, mutil.NewDomainProjectFilter(l.Domain, l.Project), l, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("failed to put quota limits", err)
+	}
+	return err
+}
+
+func (ds *DataSource) GetQuotaLimits(ctx context.Context, domain, project string) (*quota.Limits, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionQuota, mutil.NewDomainProjectFilter(domain, project))
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrQuotaLimitsNotExist
+	}
+	var l quota.Limits
+	err = result.Decode(&l)
+	if err != nil {
+		log.Error("failed to decode quota limits", err)
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (ds *DataSource) ListQuotaLimits(ctx context.Context) ([]*quota.Limits, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionQuota, mutil.NewFilter())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var limits []*quota.Limits
+	for cursor.Next(ctx) {
+		var l quota.Limits
+		err = cursor.Decode(&l)
+		if err != nil {
+			log.Error("failed to decode quota limits", err)
+			continue
+		}
+		limits = append(limits, &l)
+	}
+	return limits, nil
+}
+
+func (ds *DataSource) DeleteQuotaLimits(ctx context.Context, domain, project string) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionQuota, mutil.NewDomainProjectFilter(domain, project))
+	return err
+}
+
+func (ds *DataSource) CountQuotaUsage(ctx context.Context, resource, serviceID string) (int64, error) {
+	switch resource {
+	case quota.ResourceService:
+		return client.GetMongoClient().Count(ctx, model.CollectionService, mutil.NewBasicFilter(ctx))
+	case quota.ResourceInstance:
+		return client.GetMongoClient().Count(ctx, model.CollectionInstance, mutil.NewBasicFilter(ctx))
+	case quota.ResourceInstancePerService:
+		return client.GetMongoClient().Count(ctx, model.CollectionInstance,
+			mutil.NewBasicFilter(ctx, mutil.InstanceServiceID(serviceID)))
+	case quota.ResourceRule:
+		return client.GetMongoClient().Count(ctx, model.CollectionRule,
+			mutil.NewBasicFilter(ctx, mutil.ServiceID(serviceID)))
+	case quota.ResourceAccount:
+		return client.GetMongoClient().Count(ctx, model.CollectionAccount, mutil.NewFilter())
+	case quota.ResourceRole:
+		return client.GetMongoClient().Count(ctx, model.CollectionRole, mutil.NewFilter())
+	case quota.ResourceSchema, quota.ResourceTag, quota.ResourceDependency:
+		// counted from the service below
+	default:
+		return 0, datasource.ErrUnknownQuotaResource
+	}
+
+	svc, err := dao.GetService(ctx, mutil.NewBasicFilter(ctx, mutil.ServiceServiceID(serviceID)))
+	if err != nil {
+		// the resources of a service not exist are counted as 0
+		if errors.Is(err, datasource.ErrNoData) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	switch resource {
+	case quota.ResourceSchema:
+		return int64(len(svc.Service.Schemas)), nil
+	case quota.ResourceTag:
+		return int64(len(svc.Tags)), nil
+	default:
+		domainProject := util.ParseDomainProject(ctx)
+		filter := GenerateConsumerDependencyRuleKey(domainProject, pb.MicroServiceToKey(domainProject, svc.Service))
+		dep, err := TransferToMicroServiceDependency(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(dep.Dependency)), nil
+	}
+}
+
+func (ds *DataSource) ListQuotaReservation(ctx context.Context, key string) ([]*quota.Reservation, int64, error) {
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionQuotaRsv, mutil.NewFilter(mutil.ID(key)))
+	if err != nil {
+		return nil, 0, err
+	}
+	var doc model.QuotaReservation
+	if err := result.Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	now := time.Now().Unix()
+	rs := make([]*quota.Reservation, 0, len(doc.Reservations))
+	for _, r := range doc.Reservations {
+		if r.ExpireTime > now {
+			rs = append(rs, r)
+		}
+	}
+	return rs, doc.Revision, nil
+}
+
+func (ds *DataSource) PutQuotaReservation(ctx context.Context, key string, r *quota.Reservation, rev int64) error {
+	if rev == 0 {
+		_, err := client.GetMongoClient().Insert(ctx, model.CollectionQuotaRsv, &model.QuotaReservation{
+			ID:           key,
+			Revision:     1,
+			Reservations: []*quota.Reservation{r},
+		})
+		if client.IsDuplicateKey(err) {
+			return datasource.ErrQuotaReservationConflict
+		}
+		return err
+	}
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionQuotaRsv,
+		mutil.NewFilter(mutil.ID(key), func(filter bson.M) {
+			filter[model.ColumnRevision] = rev
+		}),
+		bson.M{
+			"$inc":  bson.M{model.ColumnRevision: 1},
+			"$push": bson.M{model.ColumnReservations: r},
+		})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return datasource.ErrQuotaReservationConflict
+	}
+	return nil
+}
+
+func (ds *DataSource) DeleteQuotaReservation(ctx context.Context, key, id string) error {
+	// the expired ones leaked by the crashed replicas are removed too
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionQuotaRsv, mutil.NewFilter(mutil.ID(key)),
+		bson.M{"$pull": bson.M{model.ColumnReservations: bson.M{"$or": bson.A{
+			bson.M{model.ColumnID: id},
+			bson.M{model.ColumnExpireTime: bson.M{"$lte": time.Now().Unix()}},
+		}}}})
+	return err
+}
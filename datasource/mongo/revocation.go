@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const revocationRewatchInterval = time.Second
+
+// revocationChange is the change stream event of the revocation collection
+type revocationChange struct {
+	OperationType string                `bson:"operationType"`
+	FullDocument  *rbacframe.Revocation `bson:"fullDocument"`
+}
+
+func (ds *DataSource) PutRevocation(ctx context.Context, r *rbacframe.Revocation) error {
+	_, err := client.GetMongoClient().GetDB().Collection(model.CollectionRevocation).
+		ReplaceOne(ctx, mutil.NewFilter(mutil.ID(r.ID)), r, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error("failed to put revocation", err)
+	}
+	return err
+}
+
+func (ds *DataSource) ListRevocation(ctx context.Context) ([]*rbacframe.Revocation, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRevocation, mutil.NewFilter())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var revocations []*rbacframe.Revocation
+	for cursor.Next(ctx) {
+		var r rbacframe.Revocation
+		err = cursor.Decode(&r)
+		if err != nil {
+			log.Error("failed to decode revocation", err)
+			continue
+		}
+		revocations = append(revocations, &r)
+	}
+	return revocations, nil
+}
+
+func (ds *DataSource) DeleteRevocation(ctx context.Context, id string) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionRevocation, mutil.NewFilter(mutil.ID(id)))
+	return err
+}
+
+
+// watchRevocation propagates the token revocations made by other replicas,
+// the deletions are only the purges of expired tokens, so they are skipped,
+// and the changes missed while re-watching are recovered by the periodic sync
+func watchRevocation() {
+	gopool.Go(func(ctx context.Context) {
+		for {
+			if err := doWatchRevocation(ctx); err != nil {
+				log.Error("watch revocation failed", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(revocationRewatchInterval):
+			}
+		}
+	})
+}
+
+func doWatchRevocation(ctx context.Context) error {
+	stream, err := client.GetMongoClient().Watch(ctx, model.CollectionRevocation, mongo.Pipeline{},
+		options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+	for stream.Next(ctx) {
+		var change revocationChange
+		if err := bson.Unmarshal(stream.Current, &change); err != nil {
+			log.Error("failed to decode revocation change", err)
+			continue
+		}
+		if change.FullDocument == nil {
+			continue
+		}
+		action := pb.EVT_UPDATE
+		if change.OperationType == "insert" {
+			action = pb.EVT_CREATE
+		}
+		notify.PublishRevocationEvent(notify.NewRevocationEvent(string(action), change.FullDocument))
+	}
+	return stream.Err()
+}
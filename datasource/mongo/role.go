@@ -20,7 +20,7 @@ package mongo
 import (
 	"context"
 
-	"github.com/go-chassis/cari/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
@@ -30,7 +30,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
-func (ds *DataSource) CreateRole(ctx context.Context, r *rbac.Role) error {
+func (ds *DataSource) CreateRole(ctx context.Context, r *rbacframe.Role) error {
 	exist, err := ds.RoleExist(ctx, r.Name)
 	if err != nil {
 		log.Error("failed to query role", err)
@@ -63,7 +63,7 @@ func (ds *DataSource) RoleExist(ctx context.Context, name string) (bool, error)
 	return true, nil
 }
 
-func (ds *DataSource) GetRole(ctx context.Context, name string) (*rbac.Role, error) {
+func (ds *DataSource) GetRole(ctx context.Context, name string) (*rbacframe.Role, error) {
 	filter := mutil.NewFilter(mutil.RoleName(name))
 	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionRole, filter)
 	if err != nil {
@@ -72,7 +72,7 @@ func (ds *DataSource) GetRole(ctx context.Context, name string) (*rbac.Role, err
 	if result.Err() != nil {
 		return nil, client.ErrNoDocuments
 	}
-	var role rbac.Role
+	var role rbacframe.Role
 	err = result.Decode(&role)
 	if err != nil {
 		log.Error("failed to decode role", err)
@@ -81,16 +81,16 @@ func (ds *DataSource) GetRole(ctx context.Context, name string) (*rbac.Role, err
 	return &role, nil
 }
 
-func (ds *DataSource) ListRole(ctx context.Context) ([]*rbac.Role, int64, error) {
+func (ds *DataSource) ListRole(ctx context.Context) ([]*rbacframe.Role, int64, error) {
 	filter := mutil.NewFilter()
 	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRole, filter)
 	if err != nil {
 		return nil, 0, err
 	}
-	var roles []*rbac.Role
+	var roles []*rbacframe.Role
 	defer cursor.Close(ctx)
 	for cursor.Next(ctx) {
-		var role rbac.Role
+		var role rbacframe.Role
 		err = cursor.Decode(&role)
 		if err != nil {
 			log.Error("failed to decode role", err)
@@ -113,7 +113,7 @@ func (ds *DataSource) DeleteRole(ctx context.Context, name string) (bool, error)
 	return true, nil
 }
 
-func (ds *DataSource) UpdateRole(ctx context.Context, name string, role *rbac.Role) error {
+func (ds *DataSource) UpdateRole(ctx context.Context, name string, role *rbacframe.Role) error {
 	filter := mutil.NewFilter(mutil.RoleName(name))
 	setFilter := mutil.NewFilter(
 		mutil.ID(role.ID),
@@ -20,12 +20,12 @@ package util
 import (
 	"context"
 
-	"github.com/go-chassis/cari/rbac"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
@@ -73,6 +73,18 @@ func CurrentPassword(password string) Option {
 	}
 }
 
+func Account(name string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAccount] = name
+	}
+}
+
+func LastUsedTime(t string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnLastUsedTime] = t
+	}
+}
+
 func Status(status string) Option {
 	return func(filter bson.M) {
 		filter[model.ColumnStatus] = status
@@ -91,12 +103,24 @@ func RoleName(name string) Option {
 	}
 }
 
-func Perms(perms []*rbac.Permission) Option {
+func Perms(perms []*rbacframe.Permission) Option {
 	return func(filter bson.M) {
 		filter[model.ColumnPerms] = perms
 	}
 }
 
+func Kind(kind string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnKind] = kind
+	}
+}
+
+func WebhookID(id string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnWebhookID] = id
+	}
+}
+
 func In(data interface{}) Option {
 	return func(filter bson.M) {
 		filter["$in"] = data
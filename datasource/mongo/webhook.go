@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+)
+
+func (ds *DataSource) CreateWebhook(ctx context.Context, hook *webhook.Webhook) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionWebhook, hook)
+	if err != nil {
+		log.Error("failed to create webhook", err)
+		return err
+	}
+	log.Info("succeed to create new webhook: " + hook.ID)
+	return nil
+}
+
+func (ds *DataSource) GetWebhook(ctx context.Context, domainProject, id string) (*webhook.Webhook, error) {
+	filter := newDomainProjectFilter(domainProject, mutil.ID(id))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionWebhook, filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		return nil, datasource.ErrWebhookNotExist
+	}
+	var hook webhook.Webhook
+	err = result.Decode(&hook)
+	if err != nil {
+		log.Error("failed to decode webhook", err)
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (ds *DataSource) ListWebhook(ctx context.Context, domainProject string) ([]*webhook.Webhook, error) {
+	filter := mutil.NewFilter()
+	if len(domainProject) > 0 {
+		filter = newDomainProjectFilter(domainProject)
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionWebhook, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var hooks []*webhook.Webhook
+	for cursor.Next(ctx) {
+		var hook webhook.Webhook
+		err = cursor.Decode(&hook)
+		if err != nil {
+			log.Error("failed to decode webhook", err)
+			continue
+		}
+		hooks = append(hooks, &hook)
+	}
+	return hooks, nil
+}
+
+func (ds *DataSource) DeleteWebhook(ctx context.Context, domainProject, id string) error {
+	result, err := client.GetMongoClient().Delete(ctx, model.CollectionWebhook,
+		newDomainProjectFilter(domainProject, mutil.ID(id)))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return datasource.ErrWebhookNotExist
+	}
+	_, err = client.GetMongoClient().Delete(ctx, model.CollectionLetter,
+		newDomainProjectFilter(domainProject, mutil.WebhookID(id)))
+	return err
+}
+
+func (ds *DataSource) PutDeadLetter(ctx context.Context, letter *webhook.DeadLetter) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionLetter, letter)
+	return err
+}
+
+func (ds *DataSource) ListDeadLetter(ctx context.Context, domainProject, id string) ([]*webhook.DeadLetter, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionLetter,
+		newDomainProjectFilter(domainProject, mutil.WebhookID(id)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var letters []*webhook.DeadLetter
+	for cursor.Next(ctx) {
+		var letter webhook.DeadLetter
+		err = cursor.Decode(&letter)
+		if err != nil {
+			log.Error("failed to decode dead letter", err)
+			continue
+		}
+		letters = append(letters, &letter)
+	}
+	return letters, nil
+}
+
+func (ds *DataSource) DeleteDeadLetter(ctx context.Context, domainProject, id, letterID string) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionLetter,
+		newDomainProjectFilter(domainProject, mutil.WebhookID(id), mutil.ID(letterID)))
+	return err
+}
+
+func newDomainProjectFilter(domainProject string, options ...func(filter bson.M)) bson.M {
+	domain, project := util.FromDomainProject(domainProject)
+	return mutil.NewDomainProjectFilter(domain, project, options...)
+}
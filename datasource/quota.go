@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+)
+
+var (
+	ErrQuotaLimitsNotExist  = errors.New("quota limits does not exist")
+	ErrUnknownQuotaResource = errors.New("unknown quota resource")
+	// ErrQuotaReservationConflict means the reservations changed after listed
+	ErrQuotaReservationConflict = errors.New("quota reservations changed concurrently")
+)
+
+// QuotaManager contains the quota limits overrides CRUD
+type QuotaManager interface {
+	// PutQuotaLimits creates or overwrites the limits of the domain project
+	PutQuotaLimits(ctx context.Context, l *quota.Limits) error
+	GetQuotaLimits(ctx context.Context, domain, project string) (*quota.Limits, error)
+	ListQuotaLimits(ctx context.Context) ([]*quota.Limits, error)
+	DeleteQuotaLimits(ctx context.Context, domain, project string) error
+	// CountQuotaUsage counts the resource in the domain project of ctx from the backend directly,
+	// the per service resources are counted for the serviceID,
+	// the accounts and roles are counted over the cluster
+	CountQuotaUsage(ctx context.Context, resource, serviceID string) (int64, error)
+	// ListQuotaReservation returns the unexpired reservations of the key and their revision
+	ListQuotaReservation(ctx context.Context, key string) ([]*quota.Reservation, int64, error)
+	// PutQuotaReservation adds the reservation if the revision of the reservations is still rev,
+	// otherwise it returns ErrQuotaReservationConflict
+	PutQuotaReservation(ctx context.Context, key string, r *quota.Reservation, rev int64) error
+	DeleteQuotaReservation(ctx context.Context, key, id string) error
+}
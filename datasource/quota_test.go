@@ -0,0 +1,141 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	pkgquota "github.com/apache/servicecomb-service-center/pkg/quota"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota_CountUsage(t *testing.T) {
+	var serviceID string
+
+	t.Run("create a service with tags and instances", func(t *testing.T) {
+		resp, err := datasource.Instance().RegisterService(getContext(), &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "quota_count_group",
+				ServiceName: "quota_count_service",
+				Version:     "1.0.0",
+				Schemas:     []string{"s1", "s2"},
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+		serviceID = resp.ServiceId
+
+		tagsResp, err := datasource.Instance().AddTags(getContext(), &pb.AddServiceTagsRequest{
+			ServiceId: serviceID,
+			Tags:      map[string]string{"a": "a", "b": "b", "c": "c"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, tagsResp.Response.GetCode())
+
+		for _, host := range []string{"quota-count-1", "quota-count-2"} {
+			instResp, err := datasource.Instance().RegisterInstance(getContext(), &pb.RegisterInstanceRequest{
+				Instance: &pb.MicroServiceInstance{
+					ServiceId: serviceID,
+					HostName:  host,
+					Endpoints: []string{"rest://" + host + ":8080"},
+					Status:    pb.MSI_UP,
+				},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, pb.ResponseSuccess, instResp.Response.GetCode())
+		}
+	})
+
+	t.Run("count the resources of the service", func(t *testing.T) {
+		n, err := datasource.Instance().CountQuotaUsage(getContext(), pkgquota.ResourceSchema, serviceID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		n, err = datasource.Instance().CountQuotaUsage(getContext(), pkgquota.ResourceTag, serviceID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+
+		n, err = datasource.Instance().CountQuotaUsage(getContext(), pkgquota.ResourceInstancePerService, serviceID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		n, err = datasource.Instance().CountQuotaUsage(getContext(), pkgquota.ResourceRule, serviceID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+
+		n, err = datasource.Instance().CountQuotaUsage(getContext(), pkgquota.ResourceService, "")
+		assert.NoError(t, err)
+		assert.True(t, n >= 1)
+	})
+
+	t.Run("count the resources of a service not exist", func(t *testing.T) {
+		n, err := datasource.Instance().CountQuotaUsage(getContext(), pkgquota.ResourceTag, "not-exist")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+	})
+
+	t.Run("count an unknown resource", func(t *testing.T) {
+		_, err := datasource.Instance().CountQuotaUsage(getContext(), "unknown", serviceID)
+		assert.Equal(t, datasource.ErrUnknownQuotaResource, err)
+	})
+
+	t.Run("delete the service", func(t *testing.T) {
+		resp, err := datasource.Instance().UnregisterService(getContext(), &pb.DeleteServiceRequest{
+			ServiceId: serviceID,
+			Force:     true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	})
+}
+
+func TestQuota_Reservation(t *testing.T) {
+	const key = "service/quota_reservation/default"
+	r1 := &pkgquota.Reservation{ID: "r1", Size: 1, ExpireTime: time.Now().Add(time.Minute).Unix()}
+	r2 := &pkgquota.Reservation{ID: "r2", Size: 2, ExpireTime: time.Now().Add(time.Minute).Unix()}
+
+	rs, rev, err := datasource.Instance().ListQuotaReservation(getContext(), key)
+	assert.NoError(t, err)
+	assert.Empty(t, rs)
+
+	t.Run("reserve with the listed revision, should succeed", func(t *testing.T) {
+		assert.NoError(t, datasource.Instance().PutQuotaReservation(getContext(), key, r1, rev))
+	})
+
+	t.Run("reserve with a stale revision, should conflict", func(t *testing.T) {
+		err := datasource.Instance().PutQuotaReservation(getContext(), key, r2, rev)
+		assert.Equal(t, datasource.ErrQuotaReservationConflict, err)
+
+		rs, rev, err = datasource.Instance().ListQuotaReservation(getContext(), key)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(rs))
+		assert.NoError(t, datasource.Instance().PutQuotaReservation(getContext(), key, r2, rev))
+	})
+
+	t.Run("delete the reservations, should list nothing", func(t *testing.T) {
+		assert.NoError(t, datasource.Instance().DeleteQuotaReservation(getContext(), key, r1.ID))
+		assert.NoError(t, datasource.Instance().DeleteQuotaReservation(getContext(), key, r2.ID))
+		rs, _, err := datasource.Instance().ListQuotaReservation(getContext(), key)
+		assert.NoError(t, err)
+		assert.Empty(t, rs)
+	})
+}
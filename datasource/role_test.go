@@ -19,7 +19,7 @@ package datasource_test
 
 import (
 	"context"
-	"github.com/go-chassis/cari/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,13 +28,13 @@ import (
 )
 
 var (
-	r1 = rbac.Role{
+	r1 = rbacframe.Role{
 		ID:    "11111-22222-33333",
 		Name:  "test-role1",
 		Perms: nil,
 	}
 
-	r2 = rbac.Role{
+	r2 = rbacframe.Role{
 		ID:    "11111-22222-33333-44444",
 		Name:  "test-role2",
 		Perms: nil,
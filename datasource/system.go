@@ -19,13 +19,21 @@ package datasource
 
 import (
 	"context"
+	"errors"
 
 	"github.com/apache/servicecomb-service-center/pkg/dump"
 )
 
+var ErrNotLeader = errors.New("the leadership is held by the other one")
+
 // SystemManager contains the APIs of system management
 type SystemManager interface {
 	DumpCache(ctx context.Context) *dump.Cache
 	DLock(ctx context.Context, request *DLockRequest) error
 	DUnlock(ctx context.Context, request *DUnlockRequest) error
+	// Campaign holds the leadership of the election, or renews the lease if
+	// already held, it returns ErrNotLeader if the other one is the leader
+	Campaign(ctx context.Context, request *CampaignRequest) error
+	// Resign releases the leadership only if it is held by the caller
+	Resign(ctx context.Context, request *ResignRequest) error
 }
@@ -61,3 +61,21 @@ func TestDumpCache(t *testing.T) {
 		assert.NotNil(t, cache)
 	})
 }
+
+func TestCampaign(t *testing.T) {
+	t.Run("campaign and resign, should pass", func(t *testing.T) {
+		req := &datasource.CampaignRequest{ID: "test_campaign", TTL: 10}
+		err := datasource.Instance().Campaign(getContext(), req)
+		assert.NoError(t, err)
+
+		// renew the leadership held by self
+		err = datasource.Instance().Campaign(getContext(), req)
+		assert.NoError(t, err)
+
+		err = datasource.Instance().Resign(getContext(), &datasource.ResignRequest{ID: req.ID})
+		assert.NoError(t, err)
+
+		err = datasource.Instance().Resign(getContext(), &datasource.ResignRequest{ID: req.ID})
+		assert.NoError(t, err)
+	})
+}
@@ -29,3 +29,15 @@ type DLockRequest struct {
 type DUnlockRequest struct {
 	ID string
 }
+
+type CampaignRequest struct {
+	// ID is the election global unique id
+	ID string
+	// TTL is the seconds of the leadership lease, the leader keeps
+	// the leadership by campaigning again before the lease expired
+	TTL int64
+}
+
+type ResignRequest struct {
+	ID string
+}
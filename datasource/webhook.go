@@ -0,0 +1,42 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+)
+
+var (
+	ErrWebhookNotExist = errors.New("webhook does not exist")
+)
+
+// WebhookManager contains the webhook and dead letter CRUD
+type WebhookManager interface {
+	CreateWebhook(ctx context.Context, hook *webhook.Webhook) error
+	GetWebhook(ctx context.Context, domainProject, id string) (*webhook.Webhook, error)
+	// ListWebhook returns the webhooks of domainProject, or all if domainProject is empty
+	ListWebhook(ctx context.Context, domainProject string) ([]*webhook.Webhook, error)
+	DeleteWebhook(ctx context.Context, domainProject, id string) error
+
+	PutDeadLetter(ctx context.Context, letter *webhook.DeadLetter) error
+	ListDeadLetter(ctx context.Context, domainProject, webhookID string) ([]*webhook.DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, domainProject, webhookID, id string) error
+}
@@ -4,9 +4,8 @@ replace google.golang.org/grpc => google.golang.org/grpc v1.19.0
 
 require (
 	bou.ke/monkey v1.0.2
-	github.com/jinzhu/copier v0.2.9-0.20210317033127-fc3adf52acab
 	github.com/NYTimes/gziphandler v1.0.2-0.20180820182813-253f1acb9d9f
-	github.com/Shopify/sarama v1.27.2 // indirect
+	github.com/Shopify/sarama v1.27.2
 	github.com/apache/thrift v0.0.0-20180125231006-3d556248a8b9 // indirect
 	github.com/astaxie/beego v1.8.0
 	github.com/cheggaaa/pb v1.0.25
@@ -33,6 +32,7 @@ require (
 	github.com/hashicorp/golang-lru v0.5.4 // indirect
 	github.com/hashicorp/serf v0.8.3
 	github.com/iancoleman/strcase v0.1.2
+	github.com/jinzhu/copier v0.2.9-0.20210317033127-fc3adf52acab
 	github.com/jonboulle/clockwork v0.2.2 // indirect
 	github.com/karlseguin/ccache v2.0.3-0.20170217060820-3ba9789cfd2c+incompatible
 	github.com/karlseguin/expect v1.0.7 // indirect
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"time"
+)
+
+//Record is the audit record of a mutating request,
+//it tells who did what to which resource, when and the result
+type Record struct {
+	ID       string   `json:"id"`
+	Domain   string   `json:"domain,omitempty"`
+	Project  string   `json:"project,omitempty"`
+	Account  string   `json:"account,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	RemoteIP string   `json:"remoteIP,omitempty" bson:"remote_ip"`
+	Method   string   `json:"method"`
+	Pattern  string   `json:"pattern"`
+	Resource string   `json:"resource,omitempty"`
+	Verb     string   `json:"verb,omitempty"`
+	// Targets are the path parameters of the request, e.g. serviceId, instanceId
+	Targets    map[string]string `json:"targets,omitempty"`
+	StatusCode int               `json:"statusCode" bson:"status_code"`
+	Timestamp  int64             `json:"timestamp"`
+
+	ExpireTime time.Time `json:"-" bson:"expire_time"`
+}
+
+//Query decides which records will be listed,
+//an empty field means matching any value
+type Query struct {
+	Domain   string
+	Project  string
+	Account  string
+	Resource string
+	Verb     string
+	// Target matches any of the record targets
+	Target     string
+	StatusCode int
+	// Start and End are the unix time range of records, both inclusive
+	Start int64
+	End   int64
+	// Limit is the max count of the latest records returned, 0 means no limit
+	Limit int
+}
+
+type ListResponse struct {
+	Total   int64     `json:"total"`
+	Records []*Record `json:"data,omitempty"`
+}
+
+//Match return true if the record passes all the conditions of query
+func (q *Query) Match(r *Record) bool {
+	if q == nil {
+		return true
+	}
+	if len(q.Domain) > 0 && q.Domain != r.Domain {
+		return false
+	}
+	if len(q.Project) > 0 && q.Project != r.Project {
+		return false
+	}
+	if len(q.Account) > 0 && q.Account != r.Account {
+		return false
+	}
+	if len(q.Resource) > 0 && q.Resource != r.Resource {
+		return false
+	}
+	if len(q.Verb) > 0 && q.Verb != r.Verb {
+		return false
+	}
+	if q.StatusCode > 0 && q.StatusCode != r.StatusCode {
+		return false
+	}
+	if q.Start > 0 && r.Timestamp < q.Start {
+		return false
+	}
+	if q.End > 0 && r.Timestamp > q.End {
+		return false
+	}
+	if len(q.Target) > 0 && !hasTarget(r.Targets, q.Target) {
+		return false
+	}
+	return true
+}
+
+func hasTarget(targets map[string]string, id string) bool {
+	for _, v := range targets {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Match(t *testing.T) {
+	r := &audit.Record{
+		Domain:     "default",
+		Project:    "default",
+		Account:    "root",
+		Resource:   "service",
+		Verb:       "delete",
+		Targets:    map[string]string{"serviceId": "s1"},
+		StatusCode: 200,
+		Timestamp:  100,
+	}
+	var q *audit.Query
+	assert.True(t, q.Match(r))
+	assert.True(t, (&audit.Query{}).Match(r))
+	assert.True(t, (&audit.Query{Account: "root", Resource: "service", Verb: "delete", Target: "s1",
+		StatusCode: 200, Start: 100, End: 100}).Match(r))
+
+	assert.False(t, (&audit.Query{Domain: "other"}).Match(r))
+	assert.False(t, (&audit.Query{Account: "admin"}).Match(r))
+	assert.False(t, (&audit.Query{Verb: "create"}).Match(r))
+	assert.False(t, (&audit.Query{Target: "s2"}).Match(r))
+	assert.False(t, (&audit.Query{StatusCode: 401}).Match(r))
+	assert.False(t, (&audit.Query{Start: 101}).Match(r))
+	assert.False(t, (&audit.Query{End: 99}).Match(r))
+}
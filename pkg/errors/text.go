@@ -26,4 +26,7 @@ const (
 	MsgGetRoleFailed        = "get role failed"
 	MsgRolePerm             = "check role permissions failed"
 	MsgNoPerm               = "no permission to operate"
+
+	MsgOperateWebhookFailed = "operate webhook failed"
+	MsgGetWebhookFailed     = "get webhook failed"
 )
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"errors"
+)
+
+//Bulkhead limits the concurrent calls
+type Bulkhead struct {
+	*GovernancePolicy
+	Spec *BulkheadSpec `json:"spec,omitempty"`
+}
+
+//BulkheadSpec rejects the call if it waits for MaxWaitDuration
+//while there are MaxConcurrentCalls calls in progress
+type BulkheadSpec struct {
+	MarkerName         string `json:"match"`
+	MaxConcurrentCalls int    `json:"maxConcurrentCalls"`
+	MaxWaitDuration    string `json:"maxWaitDuration,omitempty"`
+}
+
+func (s *BulkheadSpec) Validate() error {
+	if s.MarkerName == "" {
+		return errors.New("match can not be empty")
+	}
+	if s.MaxConcurrentCalls <= 0 {
+		return errors.New("maxConcurrentCalls must be positive")
+	}
+	return validateDuration("maxWaitDuration", s.MaxWaitDuration)
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	SlidingWindowCount = "count"
+	SlidingWindowTime  = "time"
+)
+
+//CircuitBreaker stops calling the unhealthy service for a while
+type CircuitBreaker struct {
+	*GovernancePolicy
+	Spec *CircuitBreakerSpec `json:"spec,omitempty"`
+}
+
+//CircuitBreakerSpec opens the breaker when the failure rate or the slow call rate
+//of the calls in the sliding window exceed the thresholds, the rates are percentages
+type CircuitBreakerSpec struct {
+	MarkerName                string  `json:"match"`
+	FailureRateThreshold      float64 `json:"failureRateThreshold,omitempty"`
+	SlowCallRateThreshold     float64 `json:"slowCallRateThreshold,omitempty"`
+	SlowCallDurationThreshold string  `json:"slowCallDurationThreshold,omitempty"`
+	// SlidingWindowType is count or time, the size is the calls count or seconds
+	SlidingWindowType    string `json:"slidingWindowType,omitempty"`
+	SlidingWindowSize    int    `json:"slidingWindowSize,omitempty"`
+	MinimumNumberOfCalls int    `json:"minimumNumberOfCalls,omitempty"`
+	// WaitDurationInOpenState is the time before trying the calls again
+	WaitDurationInOpenState               string `json:"waitDurationInOpenState,omitempty"`
+	PermittedNumberOfCallsInHalfOpenState int    `json:"permittedNumberOfCallsInHalfOpenState,omitempty"`
+}
+
+func (s *CircuitBreakerSpec) Validate() error {
+	if s.MarkerName == "" {
+		return errors.New("match can not be empty")
+	}
+	if s.FailureRateThreshold == 0 && s.SlowCallRateThreshold == 0 {
+		return errors.New("one of failureRateThreshold and slowCallRateThreshold must be set")
+	}
+	if err := validatePercentage("failureRateThreshold", s.FailureRateThreshold); err != nil {
+		return err
+	}
+	if err := validatePercentage("slowCallRateThreshold", s.SlowCallRateThreshold); err != nil {
+		return err
+	}
+	if s.SlowCallRateThreshold > 0 && s.SlowCallDurationThreshold == "" {
+		return errors.New("slowCallDurationThreshold must be set with slowCallRateThreshold")
+	}
+	if err := validateDuration("slowCallDurationThreshold", s.SlowCallDurationThreshold); err != nil {
+		return err
+	}
+	switch s.SlidingWindowType {
+	case "", SlidingWindowCount, SlidingWindowTime:
+	default:
+		return fmt.Errorf("slidingWindowType must be %s or %s", SlidingWindowCount, SlidingWindowTime)
+	}
+	if s.SlidingWindowSize < 0 || s.MinimumNumberOfCalls < 0 || s.PermittedNumberOfCallsInHalfOpenState < 0 {
+		return errors.New("slidingWindowSize, minimumNumberOfCalls and permittedNumberOfCallsInHalfOpenState can not be negative")
+	}
+	return validateDuration("waitDurationInOpenState", s.WaitDurationInOpenState)
+}
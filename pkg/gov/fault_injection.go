@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//FaultInjection delays or aborts the calls to test the resilience
+type FaultInjection struct {
+	*GovernancePolicy
+	Spec *FaultInjectionSpec `json:"spec,omitempty"`
+}
+
+//FaultInjectionSpec injects the faults to the percentages of the calls
+type FaultInjectionSpec struct {
+	MarkerName string      `json:"match"`
+	Delay      *DelayFault `json:"delay,omitempty"`
+	Abort      *AbortFault `json:"abort,omitempty"`
+}
+
+type DelayFault struct {
+	Percentage float64 `json:"percentage"`
+	FixedDelay string  `json:"fixedDelay"`
+}
+
+type AbortFault struct {
+	Percentage float64 `json:"percentage"`
+	HTTPStatus int     `json:"httpStatus"`
+}
+
+func (s *FaultInjectionSpec) Validate() error {
+	if s.MarkerName == "" {
+		return errors.New("match can not be empty")
+	}
+	if s.Delay == nil && s.Abort == nil {
+		return errors.New("one of delay and abort must be set")
+	}
+	if s.Delay != nil {
+		if err := validatePercentage("delay.percentage", s.Delay.Percentage); err != nil {
+			return err
+		}
+		if s.Delay.FixedDelay == "" {
+			return errors.New("delay.fixedDelay can not be empty")
+		}
+		if err := validateDuration("delay.fixedDelay", s.Delay.FixedDelay); err != nil {
+			return err
+		}
+	}
+	if s.Abort != nil {
+		if err := validatePercentage("abort.percentage", s.Abort.Percentage); err != nil {
+			return err
+		}
+		if s.Abort.HTTPStatus < http.StatusBadRequest || s.Abort.HTTPStatus > 599 {
+			return fmt.Errorf("abort.httpStatus must be in [%d, 599]", http.StatusBadRequest)
+		}
+	}
+	return nil
+}
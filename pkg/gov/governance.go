@@ -27,7 +27,10 @@ type GovernancePolicy struct {
 	Status     string   `json:"status,omitempty"`
 	CreatTime  int64    `json:"creatTime,omitempty"`
 	UpdateTime int64    `json:"updateTime,omitempty"`
+	Revision   int64    `json:"revision,omitempty"`
 	Selector   Selector `json:"selector,omitempty"`
+	// Rollout is set if the policy is applied to part of the instances
+	Rollout *Rollout `json:"rollout,omitempty"`
 }
 
 //DisplayData define display data
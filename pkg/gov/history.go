@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+//History is an immutable revision of a governance policy, it is appended by every change of the policy
+//Revision is the sequence of the policy changes starting from 1,
+//Policy is the policy after the change, it is nil if the policy is deleted
+type History struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind,omitempty"`
+	Revision  int64     `json:"revision"`
+	Action    string    `json:"action"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp int64     `json:"timestamp"`
+	Policy    *Policy   `json:"policy,omitempty"`
+	Diff      []*Change `json:"diff,omitempty"`
+}
+
+//Change is a changed field of the policy, Path is the json path of the field, like spec.rate
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
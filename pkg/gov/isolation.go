@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"errors"
+)
+
+//InstanceIsolation stops calling the unhealthy instances for a while
+type InstanceIsolation struct {
+	*GovernancePolicy
+	Spec *InstanceIsolationSpec `json:"spec,omitempty"`
+}
+
+//InstanceIsolationSpec isolates the instance after ConsecutiveErrors errors,
+//or when the error rate percentage of at least MinimumNumberOfCalls calls exceeds ErrorRateThreshold
+type InstanceIsolationSpec struct {
+	MarkerName           string  `json:"match"`
+	ConsecutiveErrors    int     `json:"consecutiveErrors,omitempty"`
+	ErrorRateThreshold   float64 `json:"errorRateThreshold,omitempty"`
+	MinimumNumberOfCalls int     `json:"minimumNumberOfCalls,omitempty"`
+	IsolationDuration    string  `json:"isolationDuration"`
+	// MaxIsolationPercent is the max percentage of the instances isolated
+	MaxIsolationPercent float64 `json:"maxIsolationPercent,omitempty"`
+}
+
+func (s *InstanceIsolationSpec) Validate() error {
+	if s.MarkerName == "" {
+		return errors.New("match can not be empty")
+	}
+	if s.ConsecutiveErrors <= 0 && s.ErrorRateThreshold == 0 {
+		return errors.New("one of consecutiveErrors and errorRateThreshold must be set")
+	}
+	if s.ConsecutiveErrors < 0 || s.MinimumNumberOfCalls < 0 {
+		return errors.New("consecutiveErrors and minimumNumberOfCalls can not be negative")
+	}
+	if err := validatePercentage("errorRateThreshold", s.ErrorRateThreshold); err != nil {
+		return err
+	}
+	if err := validatePercentage("maxIsolationPercent", s.MaxIsolationPercent); err != nil {
+		return err
+	}
+	if s.IsolationDuration == "" {
+		return errors.New("isolationDuration can not be empty")
+	}
+	return validateDuration("isolationDuration", s.IsolationDuration)
+}
@@ -29,6 +29,7 @@ type MatchSpec struct {
 
 //MatchPolicy specify a request mach policy
 type MatchPolicy struct {
+	Name     string                       `json:"name,omitempty"`
 	Headers  map[string]map[string]string `json:"headers,omitempty"`
 	APIPaths map[string]string            `json:"apiPath,omitempty"`
 	Methods  []string                     `json:"methods,omitempty"`
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"fmt"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//the operators of matching the api path and header values
+const (
+	OperatorExact    = "exact"
+	OperatorPrefix   = "prefix"
+	OperatorSuffix   = "suffix"
+	OperatorContains = "contains"
+	OperatorRegex    = "regex"
+	// OperatorCompare compares the value as a number, like ">=10", the comparators are >, >=, <, <=, = and !=
+	OperatorCompare = "compare"
+)
+
+var comparators = []string{">=", "<=", "!=", ">", "<", "="}
+
+//Match returns true if the request matches all the conditions of the match policy,
+//the request matches any method if methods are empty, the header names are case insensitive,
+//the regex must match the whole value, and an error is returned if an operator is invalid
+func (m *MatchPolicy) Match(method, path string, headers map[string]string) (bool, error) {
+	if len(m.Methods) > 0 {
+		matched := false
+		for _, v := range m.Methods {
+			if strings.EqualFold(v, method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if ok, err := matchOperators(path, m.APIPaths); !ok || err != nil {
+		return false, err
+	}
+	canonical := make(map[string]string, len(headers))
+	for k, v := range headers {
+		canonical[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+	for name, operators := range m.Headers {
+		v, ok := canonical[textproto.CanonicalMIMEHeaderKey(name)]
+		if !ok {
+			return false, nil
+		}
+		if ok, err := matchOperators(v, operators); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchOperators(v string, operators map[string]string) (bool, error) {
+	for operator, expected := range operators {
+		ok, err := matchOperator(operator, v, expected)
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchOperator(operator, v, expected string) (bool, error) {
+	switch operator {
+	case OperatorExact:
+		return v == expected, nil
+	case OperatorPrefix:
+		return strings.HasPrefix(v, expected), nil
+	case OperatorSuffix:
+		return strings.HasSuffix(v, expected), nil
+	case OperatorContains:
+		return strings.Contains(v, expected), nil
+	case OperatorRegex:
+		re, err := regexp.Compile("^(?:" + expected + ")$")
+		if err != nil {
+			return false, fmt.Errorf("invalid regex %s: %s", expected, err)
+		}
+		return re.MatchString(v), nil
+	case OperatorCompare:
+		return compare(v, expected)
+	default:
+		return false, fmt.Errorf("unknown operator %s", operator)
+	}
+}
+
+func compare(v, expected string) (bool, error) {
+	for _, c := range comparators {
+		if !strings.HasPrefix(expected, c) {
+			continue
+		}
+		e, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(expected, c)), 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid compare %s: %s", expected, err)
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			// the value not a number never matches
+			return false, nil
+		}
+		switch c {
+		case ">=":
+			return f >= e, nil
+		case "<=":
+			return f <= e, nil
+		case "!=":
+			return f != e, nil
+		case ">":
+			return f > e, nil
+		case "<":
+			return f < e, nil
+		default:
+			return f == e, nil
+		}
+	}
+	return false, fmt.Errorf("invalid compare %s, it must start with one of %s", expected, strings.Join(comparators, " "))
+}
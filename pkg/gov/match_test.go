@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPolicy_Match(t *testing.T) {
+	headers := map[string]string{"x-user": "jack", "X-Version": "12"}
+	cases := []struct {
+		name    string
+		policy  gov.MatchPolicy
+		matched bool
+		err     bool
+	}{
+		{"empty", gov.MatchPolicy{}, true, false},
+		{"method", gov.MatchPolicy{Methods: []string{"GET", "POST"}}, true, false},
+		{"other method", gov.MatchPolicy{Methods: []string{"PUT"}}, false, false},
+		{"exact path", gov.MatchPolicy{APIPaths: map[string]string{"exact": "/pay/order"}}, true, false},
+		{"prefix and suffix path", gov.MatchPolicy{APIPaths: map[string]string{"prefix": "/pay", "suffix": "order"}}, true, false},
+		{"other prefix path", gov.MatchPolicy{APIPaths: map[string]string{"prefix": "/order"}}, false, false},
+		{"header regex", gov.MatchPolicy{Headers: map[string]map[string]string{"X-User": {"regex": "ja.*"}}}, true, false},
+		{"header regex matches the whole value", gov.MatchPolicy{Headers: map[string]map[string]string{"X-User": {"regex": "ja"}}}, false, false},
+		{"header compare", gov.MatchPolicy{Headers: map[string]map[string]string{"x-version": {"compare": ">=10"}}}, true, false},
+		{"missing header", gov.MatchPolicy{Headers: map[string]map[string]string{"X-Region": {"exact": "az1"}}}, false, false},
+		{"invalid regex", gov.MatchPolicy{Headers: map[string]map[string]string{"X-User": {"regex": "ja("}}}, false, true},
+		{"invalid compare", gov.MatchPolicy{Headers: map[string]map[string]string{"X-Version": {"compare": "10"}}}, false, true},
+		{"unknown operator", gov.MatchPolicy{APIPaths: map[string]string{"like": "/pay"}}, false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			matched, err := c.policy.Match("GET", "/pay/order", headers)
+			assert.Equal(t, c.matched, matched)
+			assert.Equal(t, c.err, err != nil)
+		})
+	}
+}
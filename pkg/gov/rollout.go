@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	RolloutProgressing = "progressing"
+	RolloutAborted     = "aborted"
+)
+
+//Rollout applies a policy to part of the instances stage by stage,
+//the policy applies to all the instances once the last stage is promoted,
+//and applies to none of the instances once aborted
+type Rollout struct {
+	ID         string          `json:"id"`
+	Project    string          `json:"project,omitempty"`
+	Kind       string          `json:"kind,omitempty"`
+	Stages     []*RolloutStage `json:"stages"`
+	Stage      int             `json:"stage"`
+	Status     string          `json:"status"`
+	Reason     string          `json:"reason,omitempty"`
+	UpdateTime int64           `json:"updateTime,omitempty"`
+	// Revision increases by every change of the rollout, the change is written only if the revision is not changed
+	Revision int64 `json:"revision,omitempty"`
+	// Instances are the ids of the instances selected by the current stage, resolved from the registry when read
+	Instances []string `json:"instances,omitempty"`
+}
+
+//RolloutStage selects the instances by Percentage, and the instances of the services with Tags,
+//and the instances with Properties, the conditions set are all required
+type RolloutStage struct {
+	Percentage int               `json:"percentage,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+//Current returns the stage the rollout is in
+func (r *Rollout) Current() *RolloutStage {
+	if r.Stage < 0 || r.Stage >= len(r.Stages) {
+		return nil
+	}
+	return r.Stages[r.Stage]
+}
+
+func (r *Rollout) Validate() error {
+	if len(r.Stages) == 0 {
+		return errors.New("stages can not be empty")
+	}
+	for i, s := range r.Stages {
+		if s == nil {
+			return fmt.Errorf("stages[%d] can not be empty", i)
+		}
+		if s.Percentage < 0 || s.Percentage > 100 {
+			return fmt.Errorf("stages[%d].percentage must be a percentage in [0, 100]", i)
+		}
+		if s.Percentage == 0 && len(s.Tags) == 0 && len(s.Properties) == 0 {
+			return fmt.Errorf("stages[%d] must select instances by percentage, tags or properties", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+//SimulateRequest is a sample request sent to an app in an environment,
+//Policies are the drafts evaluated in place of the stored policies of the same kind and name,
+//the policies in rollout apply only if InstanceID is selected by the current stage, they all apply if InstanceID is empty
+type SimulateRequest struct {
+	App         string            `json:"app"`
+	Environment string            `json:"environment,omitempty"`
+	InstanceID  string            `json:"instanceId,omitempty"`
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Policies    []*Policy         `json:"policies,omitempty"`
+}
+
+//SimulateResult is the match groups evaluated and the policies apply to the sample request
+type SimulateResult struct {
+	Markers  []*MarkerResult `json:"markers"`
+	Policies []*Policy       `json:"policies"`
+}
+
+//MarkerResult is the result of a match group, Match is the name of the first match matched,
+//Errors are the invalid operators of the matches
+type MarkerResult struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name"`
+	Matched bool     `json:"matched"`
+	Match   string   `json:"match,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"fmt"
+	"time"
+)
+
+//Validator is implemented by the typed specs
+type Validator interface {
+	Validate() error
+}
+
+func validatePercentage(name string, v float64) error {
+	if v < 0 || v > 100 {
+		return fmt.Errorf("%s must be a percentage in [0, 100]", name)
+	}
+	return nil
+}
+
+func validateDuration(name, v string) error {
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration like 10s: %s", name, err)
+	}
+	if d < 0 {
+		return fmt.Errorf("%s can not be negative", name)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		spec  gov.Validator
+		valid bool
+	}{
+		{"circuit breaker", &gov.CircuitBreakerSpec{MarkerName: "m", FailureRateThreshold: 50, SlidingWindowType: gov.SlidingWindowCount, SlidingWindowSize: 10}, true},
+		{"circuit breaker without match", &gov.CircuitBreakerSpec{FailureRateThreshold: 50}, false},
+		{"circuit breaker with illegal rate", &gov.CircuitBreakerSpec{MarkerName: "m", FailureRateThreshold: 101}, false},
+		{"circuit breaker with illegal window type", &gov.CircuitBreakerSpec{MarkerName: "m", FailureRateThreshold: 50, SlidingWindowType: "size"}, false},
+		{"circuit breaker with illegal duration", &gov.CircuitBreakerSpec{MarkerName: "m", FailureRateThreshold: 50, WaitDurationInOpenState: "1 minute"}, false},
+		{"bulkhead", &gov.BulkheadSpec{MarkerName: "m", MaxConcurrentCalls: 10, MaxWaitDuration: "100ms"}, true},
+		{"bulkhead without concurrency", &gov.BulkheadSpec{MarkerName: "m"}, false},
+		{"fault injection", &gov.FaultInjectionSpec{MarkerName: "m", Delay: &gov.DelayFault{Percentage: 10, FixedDelay: "2s"}}, true},
+		{"fault injection without fault", &gov.FaultInjectionSpec{MarkerName: "m"}, false},
+		{"fault injection with illegal status", &gov.FaultInjectionSpec{MarkerName: "m", Abort: &gov.AbortFault{Percentage: 10, HTTPStatus: 200}}, false},
+		{"instance isolation", &gov.InstanceIsolationSpec{MarkerName: "m", ConsecutiveErrors: 5, IsolationDuration: "30s"}, true},
+		{"instance isolation without duration", &gov.InstanceIsolationSpec{MarkerName: "m", ConsecutiveErrors: 5}, false},
+		{"rollout", &gov.Rollout{Stages: []*gov.RolloutStage{{Percentage: 10}, {Tags: map[string]string{"canary": "true"}}}}, true},
+		{"rollout without stages", &gov.Rollout{}, false},
+		{"rollout with illegal percentage", &gov.Rollout{Stages: []*gov.RolloutStage{{Percentage: 110}}}, false},
+		{"rollout selecting nothing", &gov.Rollout{Stages: []*gov.RolloutStage{{}}}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.spec.Validate()
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
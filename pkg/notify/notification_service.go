@@ -132,6 +132,26 @@ func (s *Service) Publish(evt Event) error {
 	return nil
 }
 
+// Subscribed returns true if any subscriber of type t has been added,
+// publishing an event of an unsubscribed type will fail.
+func (s *Service) Subscribed(t Type) (b bool) {
+	s.mux.RLock()
+	_, b = s.processors[t]
+	s.mux.RUnlock()
+	return
+}
+
+// Processors returns the processors of all the subscribed types
+func (s *Service) Processors() []*Processor {
+	s.mux.RLock()
+	ps := make([]*Processor, 0, len(s.processors))
+	for _, p := range s.processors {
+		ps = append(ps, p)
+	}
+	s.mux.RUnlock()
+	return ps
+}
+
 func (s *Service) Closed() (b bool) {
 	s.mux.RLock()
 	b = s.isClose
@@ -57,11 +57,17 @@ func TestGetNotifyService(t *testing.T) {
 	}
 	notifyService.RemoveSubscriber(s)
 
+	if notifyService.Subscribed(INSTANCE) {
+		t.Fatalf("TestGetNotifyService failed")
+	}
 	s = NewSubscriber(INSTANCE, "s", "g")
 	err = notifyService.AddSubscriber(s)
 	if err != nil {
 		t.Fatalf("TestGetNotifyService failed, %v", err)
 	}
+	if !notifyService.Subscribed(INSTANCE) {
+		t.Fatalf("TestGetNotifyService failed")
+	}
 	j := &baseEvent{INSTANCE, "s", "g", simple.FromTime(time.Now())}
 	err = notifyService.Publish(j)
 	if err != nil {
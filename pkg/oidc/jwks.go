@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var ErrNoKeys = errors.New("jwks has no rsa keys")
+
+// JWK is a json web key, only the rsa keys are supported
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is a json web key set
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// KeySet is the parsed public keys of a JWKS indexed by kid
+type KeySet struct {
+	keys map[string]*rsa.PublicKey
+}
+
+// ParseJWKS parses the rsa signing keys of a JWKS document
+func ParseJWKS(data []byte) (*KeySet, error) {
+	jwks := &JWKS{}
+	if err := json.Unmarshal(data, jwks); err != nil {
+		return nil, err
+	}
+	ks := &KeySet{keys: make(map[string]*rsa.PublicKey, len(jwks.Keys))}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (len(k.Use) > 0 && k.Use != "sig") {
+			continue
+		}
+		pub, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[k.Kid] = pub
+	}
+	if len(ks.keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return ks, nil
+}
+
+// PublicKey decodes the modulus and exponent of the key
+func (k *JWK) PublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 || len(e) == 0 {
+		return nil, errors.New("invalid rsa key " + k.Kid)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// NewJWK returns the JWK of the rsa public key
+func NewJWK(kid string, pub *rsa.PublicKey) *JWK {
+	return &JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+// Key returns the key of kid, the only key is returned if kid is empty
+func (ks *KeySet) Key(kid string) (*rsa.PublicKey, bool) {
+	if len(kid) == 0 && len(ks.keys) == 1 {
+		for _, k := range ks.keys {
+			return k, true
+		}
+	}
+	k, ok := ks.keys[kid]
+	return k, ok
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package oidc verifies the OpenID Connect ID tokens issued by an external identity provider
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrUnknownKey      = errors.New("id token is signed by an unknown key")
+	ErrInvalidIssuer   = errors.New("id token issuer mismatch")
+	ErrInvalidAudience = errors.New("id token audience mismatch")
+	ErrExpired         = errors.New("id token is expired or has no exp claim")
+)
+
+// Verifier checks the signature, issuer, audience and expiry of the ID tokens
+type Verifier struct {
+	Issuer   string
+	Audience string
+	Keys     *KeySet
+	// Now returns current time, used by tests
+	Now func() time.Time
+}
+
+// NewVerifier returns a Verifier of the issuer,
+// the audience is not checked if it is empty
+func NewVerifier(issuer, audience string, keys *KeySet) *Verifier {
+	return &Verifier{Issuer: issuer, Audience: audience, Keys: keys, Now: time.Now}
+}
+
+// Verify returns the claims of a valid ID token
+func (v *Verifier) Verify(rawIDToken string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{
+		ValidMethods:         []string{"RS256", "RS384", "RS512"},
+		SkipClaimsValidation: true,
+	}
+	_, err := parser.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		k, ok := v.Keys.Key(kid)
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		return k, nil
+	})
+	if err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Inner != nil {
+			return nil, ve.Inner
+		}
+		return nil, err
+	}
+	now := v.Now().Unix()
+	if !claims.VerifyExpiresAt(now, true) {
+		return nil, ErrExpired
+	}
+	if !claims.VerifyNotBefore(now, false) {
+		return nil, fmt.Errorf("id token is not valid before %v", claims["nbf"])
+	}
+	if !claims.VerifyIssuer(v.Issuer, true) {
+		return nil, ErrInvalidIssuer
+	}
+	if len(v.Audience) > 0 && !verifyAudience(claims["aud"], v.Audience) {
+		return nil, ErrInvalidAudience
+	}
+	return claims, nil
+}
+
+// jwt-go v3 only supports the string aud
+func verifyAudience(aud interface{}, audience string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == audience
+	case []interface{}:
+		for _, s := range a {
+			if s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StringClaim returns the string value of the claim
+func StringClaim(claims jwt.MapClaims, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// StringsClaim returns the values of a string or string array claim
+func StringsClaim(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		ss := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				ss = append(ss, str)
+			}
+		}
+		return ss
+	}
+	return nil
+}
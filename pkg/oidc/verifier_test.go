@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/oidc"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const issuer = "https://idp.example.com"
+
+func newKeySet(t *testing.T) (*rsa.PrivateKey, *oidc.KeySet) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	b, err := json.Marshal(&oidc.JWKS{Keys: []*oidc.JWK{oidc.NewJWK("k1", &key.PublicKey)}})
+	assert.NoError(t, err)
+	ks, err := oidc.ParseJWKS(b)
+	assert.NoError(t, err)
+	return key, ks
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestParseJWKS(t *testing.T) {
+	_, err := oidc.ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"a"}]}`))
+	assert.Equal(t, oidc.ErrNoKeys, err)
+	_, err = oidc.ParseJWKS([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	key, ks := newKeySet(t)
+	v := oidc.NewVerifier(issuer, "service-center", ks)
+	exp := time.Now().Add(time.Hour).Unix()
+
+	t.Run("valid token should pass", func(t *testing.T) {
+		claims, err := v.Verify(sign(t, key, "k1", jwt.MapClaims{
+			"iss": issuer, "aud": []string{"other", "service-center"}, "exp": exp,
+			"sub": "1", "groups": []string{"sc-admins"},
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", oidc.StringClaim(claims, "sub"))
+		assert.Equal(t, []string{"sc-admins"}, oidc.StringsClaim(claims, "groups"))
+	})
+	t.Run("unknown kid should fail", func(t *testing.T) {
+		_, err := v.Verify(sign(t, key, "k2", jwt.MapClaims{"iss": issuer, "aud": "service-center", "exp": exp}))
+		assert.Equal(t, oidc.ErrUnknownKey, err)
+	})
+	t.Run("token signed by other key should fail", func(t *testing.T) {
+		other, _ := newKeySet(t)
+		_, err := v.Verify(sign(t, other, "k1", jwt.MapClaims{"iss": issuer, "aud": "service-center", "exp": exp}))
+		assert.Error(t, err)
+	})
+	t.Run("wrong issuer or audience should fail", func(t *testing.T) {
+		_, err := v.Verify(sign(t, key, "k1", jwt.MapClaims{"iss": "x", "aud": "service-center", "exp": exp}))
+		assert.Equal(t, oidc.ErrInvalidIssuer, err)
+		_, err = v.Verify(sign(t, key, "k1", jwt.MapClaims{"iss": issuer, "aud": "x", "exp": exp}))
+		assert.Equal(t, oidc.ErrInvalidAudience, err)
+	})
+	t.Run("expired token should fail", func(t *testing.T) {
+		_, err := v.Verify(sign(t, key, "k1", jwt.MapClaims{"iss": issuer, "aud": "service-center",
+			"exp": time.Now().Add(-time.Minute).Unix()}))
+		assert.Equal(t, oidc.ErrExpired, err)
+		_, err = v.Verify(sign(t, key, "k1", jwt.MapClaims{"iss": issuer, "aud": "service-center"}))
+		assert.Equal(t, oidc.ErrExpired, err)
+	})
+}
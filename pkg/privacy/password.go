@@ -46,17 +46,17 @@ func ScryptPassword(pwd string) (string, error) {
 	return string(hash), nil
 }
 func SamePassword(hashedPwd, pwd string) bool {
-	if strings.HasPrefix(hashedPwd, algBcrypt) {
-		err := bcrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(pwd))
-		if err == bcrypt.ErrMismatchedHashAndPassword {
-			log.Warn("incorrect password attempts")
-		}
-		return err == nil
-	}
-	err := scrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(pwd))
-	if err == scrypt.ErrMismatchedHashAndPassword {
+	same := MatchPassword(hashedPwd, pwd)
+	if !same {
 		log.Warn("incorrect password attempts")
 	}
-	return err == nil
+	return same
+}
 
+//MatchPassword compares the password with the hash silently
+func MatchPassword(hashedPwd, pwd string) bool {
+	if strings.HasPrefix(hashedPwd, algBcrypt) {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(pwd)) == nil
+	}
+	return scrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(pwd)) == nil
 }
@@ -53,6 +53,16 @@ func (q *TaskQueue) Add(t Task) {
 	q.taskCh <- t
 }
 
+// Len returns the number of tasks waiting in queue
+func (q *TaskQueue) Len() int {
+	return len(q.taskCh)
+}
+
+// Cap returns the max number of tasks can wait in queue
+func (q *TaskQueue) Cap() int {
+	return cap(q.taskCh)
+}
+
 func (q *TaskQueue) dispatch(ctx context.Context, w Worker, obj interface{}) {
 	w.Handle(ctx, obj)
 }
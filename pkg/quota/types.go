@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quota
+
+// the resource names counted by datasource, same as the lower case quota types
+const (
+	ResourceService            = "service"
+	ResourceInstance           = "instance"
+	ResourceInstancePerService = "instance_per_service"
+	ResourceSchema             = "schema"
+	ResourceTag                = "tag"
+	ResourceRule               = "rule"
+	ResourceDependency         = "dependency"
+	ResourceAccount            = "account"
+	ResourceRole               = "role"
+)
+
+//Limits overrides the default quota limits of a domain project,
+//the resources absent use the default limits
+type Limits struct {
+	Domain  string `json:"domain,omitempty"`
+	Project string `json:"project,omitempty"`
+	// Limits is keyed by the lower case resource type, e.g. service, instance
+	Limits map[string]int64 `json:"limits,omitempty"`
+
+	UpdateTime string `json:"updateTime,omitempty" bson:"update_time"`
+}
+
+//Usage is the consumption of a resource type
+type Usage struct {
+	Resource string `json:"resource"`
+	Limit    int64  `json:"limit"`
+	Used     int64  `json:"used"`
+	// PerService means the limit applies to each service,
+	// then Used is of the service in query
+	PerService bool `json:"perService,omitempty"`
+}
+
+//Reservation is the quota applied but not used by the resource yet,
+//it is counted as used until it is deleted or expired
+type Reservation struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+	// ExpireTime is the unix time the reservation is ignored after,
+	// it limits the reservations leaked by the crashed replicas
+	ExpireTime int64 `json:"expireTime" bson:"expire_time"`
+}
+
+type UsageResponse struct {
+	Domain  string   `json:"domain"`
+	Project string   `json:"project"`
+	Usage   []*Usage `json:"usage"`
+}
+
+//Get returns the limit of the resource, false if it is not overridden
+func (l *Limits) Get(resource string) (int64, bool) {
+	if l == nil {
+		return 0, false
+	}
+	v, ok := l.Limits[resource]
+	return v, ok
+}
@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quota_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_Get(t *testing.T) {
+	var l *quota.Limits
+	_, ok := l.Get("service")
+	assert.False(t, ok)
+
+	l = &quota.Limits{Limits: map[string]int64{"service": 10, "instance": 0}}
+	v, ok := l.Get("service")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), v)
+	v, ok = l.Get("instance")
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), v)
+	_, ok = l.Get("schema")
+	assert.False(t, ok)
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix is the prefix of api keys, distinguishing them from the jwt tokens
+const APIKeyPrefix = "sck_"
+
+// APIKey is a long-lived credential of a service account,
+// only the hash of the key is persisted
+type APIKey struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Account  string   `json:"account,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+	Hash     string   `json:"hash,omitempty"`
+	// Key is the plain api key, it is only returned once when created
+	Key string `json:"key,omitempty" bson:"-"`
+	// ExpireAfter is the lifetime of the key when created, e.g. 720h, empty means never expire
+	ExpireAfter string `json:"expireAfter,omitempty" bson:"-"`
+
+	ExpireTime   string `json:"expireTime,omitempty" bson:"expire_time"`
+	LastUsedTime string `json:"lastUsedTime,omitempty" bson:"last_used_time"`
+	CreateTime   string `json:"createTime,omitempty" bson:"create_time"`
+}
+
+type APIKeyResponse struct {
+	Total int64     `json:"total"`
+	Keys  []*APIKey `json:"data,omitempty"`
+}
+
+// NewAPIKeySecret generates the id and the plain key of a new api key
+func NewAPIKeySecret() (id string, key string, err error) {
+	b := make([]byte, 8+32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	id = hex.EncodeToString(b[:8])
+	key = APIKeyPrefix + id + "_" + base64.RawURLEncoding.EncodeToString(b[8:])
+	return id, key, nil
+}
+
+// IsAPIKey return true if the credential is an api key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// ParseAPIKeyID return the id part of the api key
+func ParseAPIKeyID(key string) (string, bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, APIKeyPrefix), "_", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// HashAPIKey returns the hex sha256 of the key,
+// the key is random enough so that a slow hash is unnecessary
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify return true if the plain key matches the hash
+func (k *APIKey) Verify(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(k.Hash)) == 1
+}
+
+// Expired return true if the key is expired at now
+func (k *APIKey) Expired(now time.Time) bool {
+	if len(k.ExpireTime) == 0 {
+		return false
+	}
+	t, err := strconv.ParseInt(k.ExpireTime, 10, 64)
+	if err != nil {
+		return true
+	}
+	return now.Unix() >= t
+}
+
+// MatchProject return true if the key can be used in project
+func (k *APIKey) MatchProject(project string) bool {
+	if len(k.Projects) == 0 || len(project) == 0 {
+		return true
+	}
+	for _, p := range k.Projects {
+		if p == "*" || p == project {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAPIKeySecret(t *testing.T) {
+	id, key, err := rbacframe.NewAPIKeySecret()
+	assert.NoError(t, err)
+	assert.True(t, rbacframe.IsAPIKey(key))
+	parsed, ok := rbacframe.ParseAPIKeyID(key)
+	assert.True(t, ok)
+	assert.Equal(t, id, parsed)
+
+	_, ok = rbacframe.ParseAPIKeyID("sck_abc")
+	assert.False(t, ok)
+	_, ok = rbacframe.ParseAPIKeyID("eyJhbGciOi")
+	assert.False(t, ok)
+
+	k := &rbacframe.APIKey{Hash: rbacframe.HashAPIKey(key)}
+	assert.True(t, k.Verify(key))
+	assert.False(t, k.Verify(key+"x"))
+}
+
+func TestAPIKey_Expired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&rbacframe.APIKey{}).Expired(now))
+	k := &rbacframe.APIKey{ExpireTime: strconv.FormatInt(now.Add(time.Hour).Unix(), 10)}
+	assert.False(t, k.Expired(now))
+	assert.True(t, k.Expired(now.Add(2*time.Hour)))
+}
+
+func TestAPIKey_MatchProject(t *testing.T) {
+	assert.True(t, (&rbacframe.APIKey{}).MatchProject("a"))
+	k := &rbacframe.APIKey{Projects: []string{"a"}}
+	assert.True(t, k.MatchProject("a"))
+	assert.False(t, k.MatchProject("b"))
+	assert.True(t, k.MatchProject(""))
+}
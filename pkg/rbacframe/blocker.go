@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"time"
+)
+
+const (
+	// BlockIP counts the login failures of a client ip
+	BlockIP = "ip"
+	// BlockAccount counts the login failures of an account from any client
+	BlockAccount = "account"
+)
+
+// LoginAttempt records the recent login failures of an ip or account
+type LoginAttempt struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Failures is the count of failures since FirstFailTime
+	Failures      int32 `json:"failures"`
+	FirstFailTime int64 `json:"firstFailTime,omitempty" bson:"first_fail_time"`
+	Banned        bool  `json:"banned"`
+	// ReleaseTime is the unix time the ban is lifted
+	ReleaseTime int64 `json:"releaseTime,omitempty" bson:"release_time"`
+	// ExpireTime is only used by the datasource without native TTL
+	ExpireTime time.Time `json:"-" bson:"expire_time"`
+}
+
+// BannedResponse is the response of listing the banned clients
+type BannedResponse struct {
+	Total  int64           `json:"total"`
+	Banned []*LoginAttempt `json:"data"`
+}
+
+func LoginAttemptID(typ, value string) string {
+	return typ + ":" + value
+}
+
+func NewLoginAttempt(typ, value string) *LoginAttempt {
+	return &LoginAttempt{
+		ID:    LoginAttemptID(typ, value),
+		Type:  typ,
+		Value: value,
+	}
+}
+
+// IsBanned return true if the ban is not lifted at now
+func (a *LoginAttempt) IsBanned(now time.Time) bool {
+	return a.Banned && now.Unix() < a.ReleaseTime
+}
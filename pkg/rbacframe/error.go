@@ -25,6 +25,7 @@ var (
 	ErrInvalidHeader = errors.New("invalid auth header")
 	ErrNoHeader      = errors.New("should provide Authorization header")
 	ErrInvalidCtx    = errors.New("invalid context")
+	ErrUnknownLabel  = errors.New("unknown permission label, only support appId, environment and serviceName")
 
 	ErrConvertErr = errors.New(MsgConvertErr)
 	MsgConvertErr = "type convert error"
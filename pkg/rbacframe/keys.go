@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// HeaderKid is the token header identifying the signing key
+	HeaderKid = "kid"
+	// DefaultAlg is the signing algorithm if the key does not specify one
+	DefaultAlg = "RS512"
+)
+
+var (
+	ErrUnknownKid = errors.New("unknown signing key")
+	ErrInvalidAlg = errors.New("signing algorithm must be one of RS256, RS384 and RS512")
+)
+
+// KeyFunc returns the public key of kid to verify the token
+type KeyFunc func(kid string) (*rsa.PublicKey, error)
+
+// SigningMethod returns the rsa signing method of alg, the default is RS512
+func SigningMethod(alg string) (*jwt.SigningMethodRSA, error) {
+	switch alg {
+	case "":
+		return jwt.SigningMethodRS512, nil
+	case "RS256":
+		return jwt.SigningMethodRS256, nil
+	case "RS384":
+		return jwt.SigningMethodRS384, nil
+	case "RS512":
+		return jwt.SigningMethodRS512, nil
+	}
+	return nil, ErrInvalidAlg
+}
+
+// Sign signs the claims by the key and sets kid in token header,
+// the token expires after expireAfter if it is not empty
+func Sign(claims map[string]interface{}, kid, alg string, key *rsa.PrivateKey, expireAfter string) (string, error) {
+	method, err := SigningMethod(alg)
+	if err != nil {
+		return "", err
+	}
+	if len(expireAfter) > 0 {
+		d, err := time.ParseDuration(expireAfter)
+		if err != nil {
+			return "", err
+		}
+		claims[ClaimsExpire] = time.Now().Add(d).Unix()
+	}
+	t := jwt.NewWithClaims(method, jwt.MapClaims(claims))
+	if len(kid) > 0 {
+		t.Header[HeaderKid] = kid
+	}
+	return t.SignedString(key)
+}
+
+// AuthenticateWithKeys verifies the token by the public key of the kid in token header,
+// kid is empty if the token is signed before the key rotation supported
+func AuthenticateWithKeys(tokenStr string, f KeyFunc) (map[string]interface{}, error) {
+	t, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, ErrInvalidAlg
+		}
+		kid, _ := t.Header[HeaderKid].(string)
+		return f(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := t.Claims.(jwt.MapClaims)
+	if !ok || !t.Valid {
+		return nil, ErrConvertErr
+	}
+	return claims, nil
+}
@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	k1, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	k2, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	keys := func(kid string) (*rsa.PublicKey, error) {
+		switch kid {
+		case "", "k1":
+			return &k1.PublicKey, nil
+		case "k2":
+			return &k2.PublicKey, nil
+		}
+		return nil, rbacframe.ErrUnknownKid
+	}
+
+	t.Run("sign by k2, should verify by kid", func(t *testing.T) {
+		s, err := rbacframe.Sign(map[string]interface{}{"account": "a"}, "k2", "RS256", k2, "1m")
+		assert.NoError(t, err)
+		claims, err := rbacframe.AuthenticateWithKeys(s, keys)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", claims["account"])
+		assert.NotNil(t, claims["exp"])
+	})
+
+	t.Run("sign without kid, should verify by the default key", func(t *testing.T) {
+		s, err := rbacframe.Sign(map[string]interface{}{"account": "a"}, "", "", k1, "")
+		assert.NoError(t, err)
+		_, err = rbacframe.AuthenticateWithKeys(s, keys)
+		assert.NoError(t, err)
+	})
+
+	t.Run("kid not match the key or unknown, should fail", func(t *testing.T) {
+		s, err := rbacframe.Sign(map[string]interface{}{"account": "a"}, "k1", "", k2, "")
+		assert.NoError(t, err)
+		_, err = rbacframe.AuthenticateWithKeys(s, keys)
+		assert.Error(t, err)
+
+		s, err = rbacframe.Sign(map[string]interface{}{"account": "a"}, "k3", "", k2, "")
+		assert.NoError(t, err)
+		_, err = rbacframe.AuthenticateWithKeys(s, keys)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired or invalid alg, should fail", func(t *testing.T) {
+		s, err := rbacframe.Sign(map[string]interface{}{"account": "a"}, "k1", "", k1, "-1m")
+		assert.NoError(t, err)
+		_, err = rbacframe.AuthenticateWithKeys(s, keys)
+		assert.Error(t, err)
+
+		_, err = rbacframe.Sign(map[string]interface{}{"account": "a"}, "k1", "HS256", k1, "")
+		assert.Equal(t, rbacframe.ErrInvalidAlg, err)
+	})
+}
@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotOwner = errors.New("the account is not the owner of the instance")
+
+//OwnerFromContext returns the account name of the request, empty if the request is not authenticated
+func OwnerFromContext(ctx context.Context) string {
+	account, err := AccountFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return account.Name
+}
+
+//IsOwner returns true if the account of the request is the owner or an admin,
+//the instances without owner and the requests without account are not restricted
+func IsOwner(ctx context.Context, owner string) bool {
+	if owner == "" {
+		return true
+	}
+	account, err := AccountFromContext(ctx)
+	if err != nil {
+		return true
+	}
+	if account.Name == owner {
+		return true
+	}
+	for _, r := range account.Roles {
+		if r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
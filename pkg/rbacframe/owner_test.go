@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAccountContext(name string, roles ...interface{}) context.Context {
+	return rbacframe.NewContext(context.TODO(), map[string]interface{}{
+		rbacframe.ClaimsUser:  name,
+		rbacframe.ClaimsRoles: roles,
+	})
+}
+
+func TestIsOwner(t *testing.T) {
+	t.Run("request without account should not be restricted", func(t *testing.T) {
+		assert.Equal(t, "", rbacframe.OwnerFromContext(context.TODO()))
+		assert.True(t, rbacframe.IsOwner(context.TODO(), "order"))
+	})
+	t.Run("instance without owner should not be restricted", func(t *testing.T) {
+		assert.True(t, rbacframe.IsOwner(newAccountContext("order", "developer"), ""))
+	})
+	t.Run("only the owner or admin should pass", func(t *testing.T) {
+		ctx := newAccountContext("order", "developer")
+		assert.Equal(t, "order", rbacframe.OwnerFromContext(ctx))
+		assert.True(t, rbacframe.IsOwner(ctx, "order"))
+		assert.False(t, rbacframe.IsOwner(newAccountContext("payment", "developer"), "order"))
+		assert.True(t, rbacframe.IsOwner(newAccountContext("root", rbacframe.RoleAdmin), "order"))
+	})
+}
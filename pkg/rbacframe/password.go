@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"time"
+)
+
+// ClaimsPasswordExpired marks the token can only be used to change the password
+const ClaimsPasswordExpired = "pwdExpired"
+
+// PasswordState records the password changes of an account
+type PasswordState struct {
+	Account string `json:"account,omitempty"`
+	// History is the scrypt hashes of the recent passwords, the newest is the last
+	History []string `json:"history,omitempty"`
+	// ChangeTime is the unix time the password changed last time
+	ChangeTime int64 `json:"changeTime,omitempty" bson:"change_time"`
+	// MustChange is true if the password is set by others and must be changed on first login
+	MustChange bool `json:"mustChange,omitempty" bson:"must_change"`
+}
+
+// TokenResponse is the login response
+type TokenResponse struct {
+	TokenStr        string `json:"token,omitempty"`
+	PasswordExpired bool   `json:"passwordExpired,omitempty"`
+}
+
+// Push records the new password hash and keeps the last size hashes
+func (s *PasswordState) Push(hash string, size int, now time.Time) {
+	s.ChangeTime = now.Unix()
+	if size <= 0 {
+		s.History = nil
+		return
+	}
+	s.History = append(s.History, hash)
+	if len(s.History) > size {
+		s.History = s.History[len(s.History)-size:]
+	}
+}
+
+// Expired return true if the password must be changed,
+// the password never expires if maxAge is not positive
+func (s *PasswordState) Expired(now time.Time, maxAge time.Duration) bool {
+	if s.MustChange {
+		return true
+	}
+	return maxAge > 0 && now.Sub(time.Unix(s.ChangeTime, 0)) > maxAge
+}
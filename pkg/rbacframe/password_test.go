@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordState_Push(t *testing.T) {
+	now := time.Now()
+	s := &rbacframe.PasswordState{Account: "a"}
+	s.Push("h1", 2, now)
+	s.Push("h2", 2, now)
+	s.Push("h3", 2, now)
+	assert.Equal(t, []string{"h2", "h3"}, s.History)
+	assert.Equal(t, now.Unix(), s.ChangeTime)
+
+	s.Push("h4", 0, now)
+	assert.Empty(t, s.History)
+}
+
+func TestPasswordState_Expired(t *testing.T) {
+	now := time.Now()
+	s := &rbacframe.PasswordState{Account: "a", ChangeTime: now.Add(-2 * time.Hour).Unix()}
+	assert.False(t, s.Expired(now, 0))
+	assert.False(t, s.Expired(now, 3*time.Hour))
+	assert.True(t, s.Expired(now, time.Hour))
+
+	s.MustChange = true
+	assert.True(t, s.Expired(now, 0))
+}
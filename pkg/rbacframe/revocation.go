@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	ClaimsJTI      = "jti"
+	ClaimsIssuedAt = "iat"
+	ClaimsExpire   = "exp"
+)
+
+const (
+	// RevokeToken revokes a single token by jti
+	RevokeToken = "token"
+	// RevokeAccount revokes all the tokens of an account issued before
+	RevokeAccount = "account"
+	// RevokeRole revokes all the tokens having a role issued before,
+	// it covers the identities of the identity providers which have no account
+	RevokeRole = "role"
+)
+
+// Revocation is an entry of the token denylist
+type Revocation struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type,omitempty"`
+	JTI     string `json:"jti,omitempty"`
+	Account string `json:"account,omitempty"`
+	Role    string `json:"role,omitempty"`
+	// RevokeBefore is the cutoff in unix milliseconds,
+	// the tokens of the account or role issued before it are revoked
+	RevokeBefore int64 `json:"revokeBefore,omitempty" bson:"revoke_before"`
+	// ExpireTime is the expiry of the revoked token, the entry is useless after it
+	ExpireTime int64  `json:"expireTime,omitempty" bson:"expire_time"`
+	RevokeTime string `json:"revokeTime,omitempty" bson:"revoke_time"`
+}
+
+func NewTokenRevocation(jti, account string, expireTime int64) *Revocation {
+	return &Revocation{
+		ID:         RevokeToken + ":" + jti,
+		Type:       RevokeToken,
+		JTI:        jti,
+		Account:    account,
+		ExpireTime: expireTime,
+		RevokeTime: strconv.FormatInt(time.Now().Unix(), 10),
+	}
+}
+
+func NewAccountRevocation(account string, before time.Time) *Revocation {
+	return &Revocation{
+		ID:           RevokeAccount + ":" + account,
+		Type:         RevokeAccount,
+		Account:      account,
+		RevokeBefore: UnixMilli(before),
+		RevokeTime:   strconv.FormatInt(time.Now().Unix(), 10),
+	}
+}
+
+func NewRoleRevocation(role string, before time.Time) *Revocation {
+	return &Revocation{
+		ID:           RevokeRole + ":" + role,
+		Type:         RevokeRole,
+		Role:         role,
+		RevokeBefore: UnixMilli(before),
+		RevokeTime:   strconv.FormatInt(time.Now().Unix(), 10),
+	}
+}
+
+// Expired return true if the revoked token is expired at now,
+// the account and role revocations never expire
+func (r *Revocation) Expired(now time.Time) bool {
+	return r.Type == RevokeToken && r.ExpireTime > 0 && now.Unix() > r.ExpireTime
+}
+
+// Int64Claim returns the number value of the claim, the json numbers are float64
+func Int64Claim(claims map[string]interface{}, name string) int64 {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	}
+	return 0
+}
+
+// IssuedAt returns the iat claim in unix milliseconds, the iat of
+// the tokens issued by service center carries the milliseconds as fraction
+func IssuedAt(claims map[string]interface{}) int64 {
+	switch v := claims[ClaimsIssuedAt].(type) {
+	case float64:
+		return int64(math.Round(v * 1000))
+	case int64:
+		return v * 1000
+	case int:
+		return int64(v) * 1000
+	}
+	return 0
+}
+
+// NumericDate returns t as the jwt numeric date with milliseconds
+func NumericDate(t time.Time) float64 {
+	return float64(UnixMilli(t)) / 1000
+}
+
+func UnixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocation_Expired(t *testing.T) {
+	now := time.Now()
+	r := rbacframe.NewTokenRevocation("jti", "a", now.Unix())
+	assert.Equal(t, "token:jti", r.ID)
+	assert.False(t, r.Expired(now))
+	assert.True(t, r.Expired(now.Add(time.Second)))
+
+	r = rbacframe.NewTokenRevocation("jti", "a", 0)
+	assert.False(t, r.Expired(now.Add(time.Hour)))
+
+	r = rbacframe.NewAccountRevocation("a", now)
+	assert.Equal(t, "account:a", r.ID)
+	assert.Equal(t, rbacframe.UnixMilli(now), r.RevokeBefore)
+	assert.False(t, r.Expired(now.Add(time.Hour)))
+}
+
+func TestInt64Claim(t *testing.T) {
+	claims := map[string]interface{}{
+		"exp":   float64(1700000000),
+		"iat":   int64(2),
+		"other": "x",
+	}
+	assert.Equal(t, int64(1700000000), rbacframe.Int64Claim(claims, rbacframe.ClaimsExpire))
+	assert.Equal(t, int64(2), rbacframe.Int64Claim(claims, rbacframe.ClaimsIssuedAt))
+	assert.Equal(t, int64(0), rbacframe.Int64Claim(claims, "other"))
+	assert.Equal(t, int64(0), rbacframe.Int64Claim(claims, "none"))
+}
+
+func TestIssuedAt(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	claims := map[string]interface{}{"iat": rbacframe.NumericDate(now)}
+	assert.Equal(t, int64(1700000000123), rbacframe.IssuedAt(claims))
+	assert.Equal(t, int64(1700000000000), rbacframe.IssuedAt(map[string]interface{}{"iat": int64(1700000000)}))
+	assert.Equal(t, int64(0), rbacframe.IssuedAt(map[string]interface{}{}))
+}
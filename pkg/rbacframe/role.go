@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe
+
+import (
+	"context"
+
+	"github.com/go-chassis/cari/discovery"
+)
+
+// label keys supported by Permission.Labels
+const (
+	LabelAppID       = "appId"
+	LabelEnvironment = "environment"
+	LabelServiceName = "serviceName"
+)
+
+// scopeKey is the key of the Scope value in Contexts
+var scopeKey key = "rbac-scope"
+
+// Role is compatible with the rbac.Role of cari,
+// the Perms can be scoped by projects and labels
+type Role struct {
+	ID    string        `json:"id,omitempty"`
+	Name  string        `json:"name,omitempty"`
+	Perms []*Permission `json:"perms,omitempty"`
+}
+
+// Permission grants the verbs on resources,
+// an empty Projects means any project,
+// an empty Labels means any service
+type Permission struct {
+	Resources []string          `json:"resources,omitempty"`
+	Verbs     []string          `json:"verbs,omitempty"`
+	Projects  []string          `json:"projects,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type RoleResponse struct {
+	Roles []*Role `json:"data,omitempty"`
+}
+
+// MatchProject return true if the permission can be used in project,
+// the permission scoped by projects can not be used in the apis without project
+func (p *Permission) MatchProject(project string) bool {
+	if len(p.Projects) == 0 {
+		return true
+	}
+	if len(project) == 0 {
+		return false
+	}
+	for _, pr := range p.Projects {
+		if pr == "*" || pr == project {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckLabels return ErrUnknownLabel if the labels contain unsupported keys
+func (p *Permission) CheckLabels() error {
+	for k := range p.Labels {
+		switch k {
+		case LabelAppID, LabelEnvironment, LabelServiceName:
+		default:
+			return ErrUnknownLabel
+		}
+	}
+	return nil
+}
+
+// Scope is the label selectors of the permissions which allow a request,
+// a nil Scope means the request can access any service
+type Scope struct {
+	Selectors []map[string]string
+}
+
+// Match return true if the labels match any of the selectors
+func (s *Scope) Match(labels map[string]string) bool {
+	if s == nil {
+		return true
+	}
+	for _, selector := range s.Selectors {
+		if matchSelector(selector, labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchService return true if the labels of service match the scope
+func (s *Scope) MatchService(service *discovery.MicroService) bool {
+	if s == nil {
+		return true
+	}
+	return s.Match(ServiceLabels(service))
+}
+
+func matchSelector(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if v == "*" {
+			continue
+		}
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ServiceLabels return the labels of service used by Permission.Labels
+func ServiceLabels(service *discovery.MicroService) map[string]string {
+	if service == nil {
+		return nil
+	}
+	return map[string]string{
+		LabelAppID:       service.AppId,
+		LabelEnvironment: service.Environment,
+		LabelServiceName: service.ServiceName,
+	}
+}
+
+// WithScope returns a new Context that carries the scope
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey, scope)
+}
+
+// ScopeFromContext returns the scope stored in ctx, a nil scope means unrestricted
+func ScopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey).(*Scope)
+	return s
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbacframe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermission_MatchProject(t *testing.T) {
+	assert.True(t, (&rbacframe.Permission{}).MatchProject("a"))
+	assert.True(t, (&rbacframe.Permission{Projects: []string{"a"}}).MatchProject("a"))
+	assert.True(t, (&rbacframe.Permission{Projects: []string{"*"}}).MatchProject("a"))
+	assert.False(t, (&rbacframe.Permission{Projects: []string{"b"}}).MatchProject("a"))
+	assert.True(t, (&rbacframe.Permission{}).MatchProject(""))
+	assert.False(t, (&rbacframe.Permission{Projects: []string{"*"}}).MatchProject(""))
+}
+
+func TestPermission_CheckLabels(t *testing.T) {
+	assert.NoError(t, (&rbacframe.Permission{Labels: map[string]string{rbacframe.LabelAppID: "a"}}).CheckLabels())
+	assert.Equal(t, rbacframe.ErrUnknownLabel, (&rbacframe.Permission{Labels: map[string]string{"x": "a"}}).CheckLabels())
+}
+
+func TestScope_Match(t *testing.T) {
+	service := &discovery.MicroService{AppId: "app", ServiceName: "a", Environment: "production"}
+
+	var scope *rbacframe.Scope
+	assert.True(t, scope.MatchService(service))
+
+	scope = &rbacframe.Scope{Selectors: []map[string]string{
+		{rbacframe.LabelAppID: "other"},
+		{rbacframe.LabelAppID: "app", rbacframe.LabelServiceName: "*"},
+	}}
+	assert.True(t, scope.MatchService(service))
+	assert.False(t, scope.Match(map[string]string{rbacframe.LabelAppID: "app2"}))
+
+	scope = &rbacframe.Scope{Selectors: []map[string]string{{rbacframe.LabelEnvironment: "development"}}}
+	assert.False(t, scope.MatchService(service))
+
+	ctx := rbacframe.WithScope(context.TODO(), scope)
+	assert.Equal(t, scope, rbacframe.ScopeFromContext(ctx))
+	assert.Nil(t, rbacframe.ScopeFromContext(context.TODO()))
+}
@@ -185,6 +185,8 @@ func (roa *ROAServerHandler) serve(ph *urlPatternHandler, w http.ResponseWriter,
 		WithContext(CtxMatchFunc, ph.Name).
 		Invoke(
 			func(ret chain.Result) {
+				// the handlers may wrap the response writer
+				w := inv.Context().Value(CtxResponse).(http.ResponseWriter)
 				defer func() {
 					err := ret.Err
 					itf := recover()
@@ -19,19 +19,49 @@ package validate
 
 import "unicode"
 
+// PasswordPolicy is the complexity rule of passwords
+type PasswordPolicy struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireNumber  bool
+	RequireSpecial bool
+}
+
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:      8,
+		MaxLength:      32,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireNumber:  true,
+		RequireSpecial: true,
+	}
+}
+
+// PasswordChecker checks the password by Policy, the default policy is used if it is nil
 type PasswordChecker struct {
+	Policy *PasswordPolicy
+}
+
+func (p *PasswordChecker) policy() *PasswordPolicy {
+	if p.Policy == nil {
+		return DefaultPasswordPolicy()
+	}
+	return p.Policy
 }
 
 func (p *PasswordChecker) MatchString(s string) bool {
+	policy := p.policy()
 	var (
-		hasValidLen = false
-		hasUpper    = false
-		hasLower    = false
-		hasNumber   = false
-		hasSpecial  = false
+		hasUpper   = false
+		hasLower   = false
+		hasNumber  = false
+		hasSpecial = false
 	)
-	if len(s) >= 8 && len(s) <= 32 {
-		hasValidLen = true
+	if len(s) < policy.MinLength || (policy.MaxLength > 0 && len(s) > policy.MaxLength) {
+		return false
 	}
 	for _, char := range s {
 		switch {
@@ -45,7 +75,8 @@ func (p *PasswordChecker) MatchString(s string) bool {
 			hasSpecial = true
 		}
 	}
-	return hasValidLen && hasUpper && hasLower && hasNumber && hasSpecial
+	return (hasUpper || !policy.RequireUpper) && (hasLower || !policy.RequireLower) &&
+		(hasNumber || !policy.RequireNumber) && (hasSpecial || !policy.RequireSpecial)
 }
 func (p *PasswordChecker) String() string {
 	return "password"
@@ -0,0 +1,38 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordChecker_MatchString(t *testing.T) {
+	checker := &PasswordChecker{}
+	assert.True(t, checker.MatchString("Passw0rd!"))
+	assert.False(t, checker.MatchString("Pa0!"))
+	assert.False(t, checker.MatchString("password0!"))
+	assert.False(t, checker.MatchString("Password!"))
+
+	checker.Policy = &PasswordPolicy{MinLength: 12, RequireNumber: true}
+	assert.False(t, checker.MatchString("Passw0rd!"))
+	assert.True(t, checker.MatchString("longpassword1"))
+	assert.True(t, checker.MatchString("longpassword1longpassword1longpassword1"))
+	assert.False(t, checker.MatchString("longpassword"))
+}
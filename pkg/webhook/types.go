@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+const (
+	HeaderEvent     = "X-SC-Event"
+	HeaderDelivery  = "X-SC-Delivery"
+	HeaderSignature = "X-SC-Signature"
+)
+
+//Webhook is an http endpoint registered by user,
+//service center will post the matched registry events to it
+type Webhook struct {
+	ID      string  `json:"id,omitempty"`
+	Name    string  `json:"name,omitempty"`
+	URL     string  `json:"url,omitempty"`
+	Secret  string  `json:"secret,omitempty"`
+	Domain  string  `json:"domain,omitempty"`
+	Project string  `json:"project,omitempty"`
+	Filter  *Filter `json:"filter,omitempty"`
+
+	CreateTime string `json:"createTime,omitempty" bson:"create_time"`
+}
+
+//Filter decides which events will be delivered,
+//an empty field means matching any value
+type Filter struct {
+	Resources   []string `json:"resources,omitempty"`
+	Actions     []string `json:"actions,omitempty"`
+	AppID       string   `json:"appId,omitempty" bson:"app_id"`
+	ServiceName string   `json:"serviceName,omitempty" bson:"service_name"`
+}
+
+//Payload is the json body posted to webhook
+type Payload struct {
+	ID          string      `json:"id"`
+	Resource    string      `json:"resource"`
+	Action      string      `json:"action"`
+	Domain      string      `json:"domain"`
+	Project     string      `json:"project"`
+	AppID       string      `json:"appId,omitempty"`
+	ServiceName string      `json:"serviceName,omitempty"`
+	ServiceID   string      `json:"serviceId,omitempty"`
+	ResourceID  string      `json:"resourceId,omitempty"`
+	Timestamp   int64       `json:"timestamp"`
+	Data        interface{} `json:"data,omitempty"`
+}
+
+//DeadLetter is the payload failed to deliver after all retries
+type DeadLetter struct {
+	ID        string   `json:"id,omitempty"`
+	WebhookID string   `json:"webhookId,omitempty" bson:"webhook_id"`
+	Domain    string   `json:"domain,omitempty"`
+	Project   string   `json:"project,omitempty"`
+	Payload   *Payload `json:"payload,omitempty"`
+	Attempts  int      `json:"attempts,omitempty"`
+	Error     string   `json:"error,omitempty"`
+
+	CreateTime string `json:"createTime,omitempty" bson:"create_time"`
+}
+
+type ListResponse struct {
+	Total    int64      `json:"total"`
+	Webhooks []*Webhook `json:"data,omitempty"`
+}
+
+type DeadLetterResponse struct {
+	Total       int64         `json:"total"`
+	DeadLetters []*DeadLetter `json:"data,omitempty"`
+}
+
+//Match return true if the payload passes all the conditions of filter
+func (f *Filter) Match(p *Payload) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Resources) > 0 && !contains(f.Resources, p.Resource) {
+		return false
+	}
+	if len(f.Actions) > 0 && !contains(f.Actions, p.Action) {
+		return false
+	}
+	if len(f.AppID) > 0 && f.AppID != p.AppID {
+		return false
+	}
+	if len(f.ServiceName) > 0 && f.ServiceName != p.ServiceName {
+		return false
+	}
+	return true
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Match(t *testing.T) {
+	p := &webhook.Payload{Resource: "instance", Action: "CREATE", AppID: "default", ServiceName: "a"}
+	var f *webhook.Filter
+	assert.True(t, f.Match(p))
+	assert.True(t, (&webhook.Filter{}).Match(p))
+	assert.True(t, (&webhook.Filter{Resources: []string{"service", "instance"}, AppID: "default"}).Match(p))
+	assert.False(t, (&webhook.Filter{Resources: []string{"service"}}).Match(p))
+	assert.False(t, (&webhook.Filter{Actions: []string{"DELETE"}}).Match(p))
+	assert.False(t, (&webhook.Filter{ServiceName: "b"}).Match(p))
+}
+
+func TestSign(t *testing.T) {
+	s := webhook.Sign("secret", []byte(`{"id":"1"}`))
+	assert.Equal(t, "sha256=", s[:7])
+	assert.True(t, webhook.Verify("secret", []byte(`{"id":"1"}`), s))
+	assert.False(t, webhook.Verify("other", []byte(`{"id":"1"}`), s))
+}
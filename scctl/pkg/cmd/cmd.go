@@ -49,6 +49,9 @@ func init() {
 		if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
 			ScClientConfig.RequestTimeout = d
 		}
+		if len(ScClientConfig.APIKey) == 0 {
+			ScClientConfig.APIKey = os.Getenv("SC_API_KEY")
+		}
 	}
 
 	rootCmd.PersistentFlags().StringSliceVar(&ScClientConfig.Endpoints, "addr",
@@ -57,6 +60,8 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&ScClientConfig.Token, "token", "",
 		"the auth token string to access service center.")
+	rootCmd.PersistentFlags().StringVar(&ScClientConfig.APIKey, "api-key", "",
+		"the api key of a service account to access service center, can be overrode by env SC_API_KEY.")
 
 	rootCmd.PersistentFlags().BoolVarP(&ScClientConfig.VerifyPeer, "peer", "p", false,
 		"verify service center certificates.")
@@ -32,10 +32,13 @@ const (
 	IDInternalError           model.ID = "InternalError"
 	IDIncrementPullError      model.ID = "IncrementPullError"
 	IDWebsocketOfScSyncerLost model.ID = "WebsocketOfScSyncerLost"
+	IDInstanceProbeFailed     model.ID = "InstanceProbeFailed"
+	IDAccountUnderAttack      model.ID = "AccountUnderAttack"
 )
 
 const (
 	FieldAdditionalContext = "detail"
+	FieldDomainProject     = "domainProject"
 )
 
 const (
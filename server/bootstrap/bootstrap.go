@@ -39,6 +39,10 @@ import (
 
 	//auth
 	_ "github.com/apache/servicecomb-service-center/server/plugin/auth/buildin"
+	_ "github.com/apache/servicecomb-service-center/server/plugin/auth/mtls"
+
+	//auditlog
+	_ "github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
 
 	//uuid
 	_ "github.com/apache/servicecomb-service-center/server/plugin/uuid/buildin"
@@ -48,6 +52,11 @@ import (
 	//tracing
 	_ "github.com/apache/servicecomb-service-center/server/plugin/tracing/pzipkin"
 
+	//event sink
+	_ "github.com/apache/servicecomb-service-center/server/plugin/eventsink/buildin"
+	_ "github.com/apache/servicecomb-service-center/server/plugin/eventsink/file"
+	_ "github.com/apache/servicecomb-service-center/server/plugin/eventsink/kafka"
+
 	//tlsconf
 	_ "github.com/apache/servicecomb-service-center/server/plugin/security/tlsconf/buildin"
 
@@ -61,19 +70,26 @@ import (
 	_ "github.com/apache/servicecomb-service-center/server/rest/syncer"
 
 	//governance
+	_ "github.com/apache/servicecomb-service-center/server/service/gov/buildin"
+	_ "github.com/apache/servicecomb-service-center/server/service/gov/istio"
 	_ "github.com/apache/servicecomb-service-center/server/service/gov/kie"
 
 	//metrics
 	_ "github.com/apache/servicecomb-service-center/server/rest/prometheus"
 
+	//liveness and readiness
+	_ "github.com/apache/servicecomb-service-center/server/rest/health"
+
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/broker"
 	"github.com/apache/servicecomb-service-center/server/handler/accesslog"
+	"github.com/apache/servicecomb-service-center/server/handler/audit"
 	"github.com/apache/servicecomb-service-center/server/handler/auth"
 	"github.com/apache/servicecomb-service-center/server/handler/cache"
 	"github.com/apache/servicecomb-service-center/server/handler/context"
 	"github.com/apache/servicecomb-service-center/server/handler/maxbody"
 	"github.com/apache/servicecomb-service-center/server/handler/metrics"
+	"github.com/apache/servicecomb-service-center/server/handler/ratelimit"
 	"github.com/apache/servicecomb-service-center/server/handler/tracing"
 	"github.com/apache/servicecomb-service-center/server/interceptor"
 	"github.com/apache/servicecomb-service-center/server/interceptor/access"
@@ -92,8 +108,11 @@ func init() {
 	maxbody.RegisterHandlers()
 	metrics.RegisterHandlers()
 	tracing.RegisterHandlers()
+	ratelimit.RegisterIPHandlers()
+	audit.RegisterHandlers()
 	auth.RegisterHandlers()
 	context.RegisterHandlers()
+	ratelimit.RegisterHandlers()
 	cache.RegisterHandlers()
 
 	// init broker
@@ -54,6 +54,48 @@ func GetGov() *Gov {
 	return Configurations.Gov
 }
 
+//GetIdP return the identity provider configs, nil if not configured
+func GetIdP() *IdentityProvider {
+	if Configurations.RBAC == nil {
+		return nil
+	}
+	return Configurations.RBAC.IdP
+}
+
+//GetSigningKeys return the token signing keys, it is nil if the keys are not configured
+func GetSigningKeys() *SigningKeys {
+	if Configurations.RBAC == nil {
+		return nil
+	}
+	return Configurations.RBAC.SigningKeys
+}
+
+//GetMTLS return the client certificate mapping rules
+func GetMTLS() *MTLS {
+	if Configurations.RBAC == nil {
+		return nil
+	}
+	return Configurations.RBAC.MTLS
+}
+
+//GetRateLimit reads the rate limits from the current configs,
+//the caller reloads the limits changed at runtime by calling it again
+func GetRateLimit() *RateLimit {
+	c := &struct {
+		Server struct {
+			Limit RateLimit `yaml:"limit"`
+		} `yaml:"server"`
+	}{}
+	if err := archaius.UnmarshalConfig(c); err != nil {
+		log.Error("unmarshal rate limit config failed", err)
+	}
+	l := &c.Server.Limit
+	l.Unit = GetString("server.limit.unit", "s", WithStandby("limit_ttl"))
+	l.Connections = GetInt64("server.limit.connections", 0, WithStandby("limit_conns"))
+	l.IPLookups = GetString("server.limit.ipLookups", "RemoteAddr,X-Forwarded-For,X-Real-IP", WithStandby("limit_iplookups"))
+	return l
+}
+
 //GetServer return the http server configs
 func GetServer() ServerConfig {
 	return Configurations.Server.Config
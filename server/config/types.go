@@ -20,6 +20,7 @@ package config
 //Config is yaml file struct
 type Config struct {
 	Gov    *Gov               `yaml:"gov"`
+	RBAC   *RBAC              `yaml:"rbac"`
 	Server *ServerInformation `yaml:"server"`
 }
 type Gov struct {
@@ -29,4 +30,96 @@ type DistributorOptions struct {
 	Name     string `yaml:"name"`
 	Type     string `yaml:"type"`
 	Endpoint string `yaml:"endpoint"`
+	// Primary distributor serves the reads, it is the first one if not specified
+	Primary bool `yaml:"primary"`
+}
+
+type RBAC struct {
+	IdP         *IdentityProvider `yaml:"idp"`
+	SigningKeys *SigningKeys      `yaml:"signingKeys"`
+	MTLS        *MTLS             `yaml:"mtls"`
+}
+
+//MTLS maps the client certificates to accounts or roles, it works with auth plugin mtls
+type MTLS struct {
+	//AllowToken authenticates the requests by bearer token if the certificate matches no rule
+	AllowToken bool       `yaml:"allowToken"`
+	Rules      []CertRule `yaml:"rules"`
+}
+
+//CertRule matches the client certificate by the non-empty fields,
+//URI and DNSName support the suffix wildcard "*"
+type CertRule struct {
+	URI        string `yaml:"uri"`
+	DNSName    string `yaml:"dnsName"`
+	CommonName string `yaml:"commonName"`
+	//Account is the account the certificate acts as, its roles are granted
+	Account string `yaml:"account"`
+	//Roles are granted if Account is empty
+	Roles []string `yaml:"roles"`
+}
+
+//SigningKeys are the rsa key pairs signing and verifying the tokens
+type SigningKeys struct {
+	//Active is the kid of the key signing the new tokens
+	Active string `yaml:"active"`
+	//ReloadInterval is the interval of checking the key files changes
+	ReloadInterval string       `yaml:"reloadInterval"`
+	Keys           []SigningKey `yaml:"keys"`
+}
+
+//SigningKey is a key pair identified by kid, the key without private key file only verifies tokens
+type SigningKey struct {
+	Kid            string `yaml:"kid"`
+	Alg            string `yaml:"alg"`
+	PrivateKeyFile string `yaml:"privateKeyFile"`
+	PublicKeyFile  string `yaml:"publicKeyFile"`
+}
+
+//IdentityProvider is the OIDC provider which issues the ID tokens
+type IdentityProvider struct {
+	Enabled  bool   `yaml:"enable"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	JWKSFile string `yaml:"jwksFile"`
+	//UsernameClaim is the claim used as the account name, default is preferred_username
+	UsernameClaim string `yaml:"usernameClaim"`
+	//UsernamePrefix is prepended to the account name to avoid conflicts with local accounts
+	UsernamePrefix string      `yaml:"usernamePrefix"`
+	GroupsClaim    string      `yaml:"groupsClaim"`
+	Rules          []GroupRule `yaml:"rules"`
+	//DefaultRoles are granted to the users not matching any rule
+	DefaultRoles []string `yaml:"defaultRoles"`
+}
+
+//GroupRule maps an IdP group to service center roles
+type GroupRule struct {
+	Group string   `yaml:"group"`
+	Roles []string `yaml:"roles"`
+}
+
+//RateLimit limits the requests of each tenant, client IP and api,
+//a limit is the requests count per Unit, 0 means no limit
+type RateLimit struct {
+	Unit string `yaml:"unit"`
+	//Connections is the limit of each client IP
+	Connections int64 `yaml:"connections"`
+	//IPLookups are the places to look for the client IP in order, e.g. RemoteAddr,X-Forwarded-For
+	IPLookups string `yaml:"ipLookups"`
+	//Tenant is the limit of each domain project
+	Tenant int64 `yaml:"tenant"`
+	//Burst is the max requests handled at once, it is the limit if 0
+	Burst int            `yaml:"burst"`
+	APIs  []APIRateLimit `yaml:"apis"`
+	//RefreshInterval is the interval of reloading the limits
+	RefreshInterval string `yaml:"refreshInterval"`
+}
+
+//APIRateLimit is the limit of an api over all the clients
+type APIRateLimit struct {
+	//Method matches all methods if it is empty
+	Method string `yaml:"method"`
+	//Pattern is the route pattern, e.g. /v4/:project/registry/microservices
+	Pattern string `yaml:"pattern"`
+	Limit   int64  `yaml:"limit"`
 }
@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+)
+
+// heartbeats are too frequent to audit
+var ignoreAPIs = map[string]struct{}{
+	"/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat": {},
+	"/v4/:project/registry/heartbeats":                                               {},
+	"/registry/v3/microservices/:serviceId/instances/:instanceId/heartbeat":          {},
+	"/registry/v3/heartbeats":                                                        {},
+}
+
+// Handler records the mutating requests by the auditlog plugin
+type Handler struct {
+}
+
+func (h *Handler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CtxRequest).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CtxMatchPattern).(string)
+	if !ShouldRecord(r.Method, pattern) {
+		i.Next()
+		return
+	}
+	w := &statusWriter{ResponseWriter: i.Context().Value(rest.CtxResponse).(http.ResponseWriter)}
+	i.WithContext(rest.CtxResponse, w)
+	i.Next(chain.WithAsyncFunc(func(_ chain.Result) {
+		// the request context carries the claims set by the auth handler
+		auditlog.RecordStatus(r, w.Header(), w.StatusCode())
+	}))
+}
+
+// statusWriter captures the status code written by the handlers
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// StatusCode returns the status written, or the one in the response header if nothing written
+func (w *statusWriter) StatusCode() int {
+	if w.status > 0 {
+		return w.status
+	}
+	status, _ := strconv.Atoi(w.Header().Get(rest.HeaderResponseStatus))
+	return status
+}
+
+// ShouldRecord returns true if the request changes the resources
+func ShouldRecord(method, pattern string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	_, ok := ignoreAPIs[pattern]
+	return !ok
+}
+
+// RegisterHandlers registers an audit handler before the auth handler,
+// so that the unauthorized requests are also recorded
+func RegisterHandlers() {
+	if !auditlog.Enabled() {
+		return
+	}
+	chain.RegisterHandler(rest.ServerChainName, &Handler{})
+}
@@ -0,0 +1,283 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"hash/fnv"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+	"golang.org/x/time/rate"
+)
+
+// the dimensions of the rate limits
+const (
+	DimensionTenant = "tenant"
+	DimensionIP     = "ip"
+	DimensionAPI    = "api"
+)
+
+const (
+	defaultRefreshInterval = 30 * time.Second
+	// shardCount is the number of the bucket shards, the keys in different shards do not contend
+	shardCount = 32
+)
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+	// refill is the duration filling the empty bucket
+	refill time.Duration
+}
+
+// Rejection is the rate limit rejecting a request
+type Rejection struct {
+	Dimension  string
+	Key        string
+	RetryAfter time.Duration
+}
+
+// limits is the snapshot of the loaded rate limits, it is never modified
+type limits struct {
+	config   *config.RateLimit
+	unit     time.Duration
+	interval time.Duration
+	apis     map[string]int64
+	loadTime time.Time
+}
+
+type shard struct {
+	lock    sync.Mutex
+	buckets map[string]*bucket
+}
+
+// Limiter holds a token bucket for each key of the dimensions,
+// the limits are reloaded every refresh interval, and the refilled buckets are removed meanwhile
+type Limiter struct {
+	// load returns the current limits
+	load func() *config.RateLimit
+
+	// lock serializes the refreshes
+	lock    sync.Mutex
+	current atomic.Value
+	shards  [shardCount]*shard
+}
+
+func NewLimiter(load func() *config.RateLimit) *Limiter {
+	l := &Limiter{load: load}
+	for i := range l.shards {
+		l.shards[i] = &shard{buckets: make(map[string]*bucket)}
+	}
+	l.current.Store(&limits{})
+	return l
+}
+
+// Allow takes a token from the buckets of the request in the dimensions, all the dimensions if none is given,
+// the tokens are returned if any bucket rejects, the Rejection is the one with the longest delay
+func (l *Limiter) Allow(r *http.Request, pattern string, dimensions ...string) *Rejection {
+	now := time.Now()
+	lim := l.limits(now)
+
+	var (
+		reservations []*rate.Reservation
+		rejection    *Rejection
+	)
+	check := func(dimension, key string, limit int64) {
+		if limit <= 0 || len(key) == 0 || !contains(dimensions, dimension) {
+			return
+		}
+		res := l.bucket(lim, dimension, key, limit, now).ReserveN(now, 1)
+		delay := res.DelayFrom(now)
+		if !res.OK() {
+			delay = lim.unit
+		}
+		ok := res.OK() && delay == 0
+		api := ""
+		if dimension == DimensionAPI {
+			api = key
+		}
+		metrics.ReportRateLimited(dimension, api, ok)
+		reservations = append(reservations, res)
+		if !ok && (rejection == nil || delay > rejection.RetryAfter) {
+			rejection = &Rejection{Dimension: dimension, Key: key, RetryAfter: delay}
+		}
+	}
+
+	check(DimensionTenant, util.ParseDomainProject(r.Context()), lim.config.Tenant)
+	check(DimensionIP, ClientIP(r, lim.config.IPLookups), lim.config.Connections)
+	if limit, ok := lim.apis[r.Method+" "+pattern]; ok {
+		check(DimensionAPI, r.Method+" "+pattern, limit)
+	} else if limit, ok := lim.apis[" "+pattern]; ok {
+		check(DimensionAPI, pattern, limit)
+	}
+
+	if rejection != nil {
+		for _, res := range reservations {
+			res.CancelAt(now)
+		}
+	}
+	return rejection
+}
+
+func contains(dimensions []string, dimension string) bool {
+	if len(dimensions) == 0 {
+		return true
+	}
+	for _, d := range dimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Limiter) shard(id string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return l.shards[h.Sum32()%shardCount]
+}
+
+func (l *Limiter) bucket(lim *limits, dimension, key string, limit int64, now time.Time) *rate.Limiter {
+	id := dimension + "/" + key
+	s := l.shard(id)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	b, ok := s.buckets[id]
+	if !ok {
+		burst := lim.config.Burst
+		if burst <= 0 {
+			burst = int(limit)
+		}
+		b = &bucket{
+			limiter: rate.NewLimiter(rate.Limit(float64(limit)/lim.unit.Seconds()), burst),
+			refill:  time.Duration(float64(lim.unit) * float64(burst) / float64(limit)),
+		}
+		s.buckets[id] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// limits returns the current limits, and refreshes them if the refresh interval passed
+func (l *Limiter) limits(now time.Time) *limits {
+	lim := l.current.Load().(*limits)
+	if lim.config != nil && now.Sub(lim.loadTime) <= lim.interval {
+		return lim
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	lim = l.current.Load().(*limits)
+	if lim.config != nil && now.Sub(lim.loadTime) <= lim.interval {
+		return lim
+	}
+	lim = l.refresh(lim, now)
+	l.current.Store(lim)
+	return lim
+}
+
+// refresh reloads the limits, the buckets are reset if the limits change,
+// otherwise the buckets refilled are removed, they are the same as the new ones
+func (l *Limiter) refresh(old *limits, now time.Time) *limits {
+	c := l.load()
+	if old.config != nil && reflect.DeepEqual(c, old.config) {
+		for _, s := range l.shards {
+			s.lock.Lock()
+			for id, b := range s.buckets {
+				if now.Sub(b.lastSeen) > b.refill {
+					delete(s.buckets, id)
+				}
+			}
+			s.lock.Unlock()
+		}
+		lim := *old
+		lim.loadTime = now
+		return &lim
+	}
+
+	if old.config != nil {
+		log.Infof("rate limits changed, tenant: %d, ip: %d, apis: %d per %s",
+			c.Tenant, c.Connections, len(c.APIs), c.Unit)
+	}
+	lim := &limits{
+		config:   c,
+		unit:     parseUnit(c.Unit),
+		interval: defaultRefreshInterval,
+		apis:     make(map[string]int64, len(c.APIs)),
+		loadTime: now,
+	}
+	if d, err := time.ParseDuration(c.RefreshInterval); err == nil && d > 0 {
+		lim.interval = d
+	}
+	for _, api := range c.APIs {
+		lim.apis[strings.ToUpper(api.Method)+" "+api.Pattern] = api.Limit
+	}
+	for _, s := range l.shards {
+		s.lock.Lock()
+		s.buckets = make(map[string]*bucket)
+		s.lock.Unlock()
+	}
+	return lim
+}
+
+func parseUnit(unit string) time.Duration {
+	switch unit {
+	case "ms":
+		return time.Millisecond
+	case "m":
+		return time.Minute
+	case "h":
+		return time.Hour
+	default:
+		return time.Second
+	}
+}
+
+// ClientIP returns the first client IP found in the lookups,
+// a lookup is RemoteAddr or a header name
+func ClientIP(r *http.Request, lookups string) string {
+	for _, lookup := range strings.Split(lookups, ",") {
+		lookup = strings.TrimSpace(lookup)
+		switch lookup {
+		case "":
+		case "RemoteAddr":
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			if len(host) > 0 {
+				return host
+			}
+		default:
+			// X-Forwarded-For is a list, the first is the client
+			ip := strings.TrimSpace(strings.Split(r.Header.Get(lookup), ",")[0])
+			if len(ip) > 0 {
+				return ip
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/handler/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequest(domain, project, remoteAddr string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/v4/default/registry/microservices", nil)
+	r.RemoteAddr = remoteAddr
+	return r.WithContext(util.SetDomainProject(r.Context(), domain, project))
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	const pattern = "/v4/:project/registry/microservices"
+	c := &config.RateLimit{Unit: "m", IPLookups: "RemoteAddr", RefreshInterval: "1ns"}
+	l := ratelimit.NewLimiter(func() *config.RateLimit { return c })
+
+	t.Run("no limit should pass", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			assert.Nil(t, l.Allow(newRequest("a", "a", "1.1.1.1:80"), pattern))
+		}
+	})
+
+	t.Run("limit the tenant", func(t *testing.T) {
+		c = &config.RateLimit{Unit: "m", IPLookups: "RemoteAddr", Tenant: 2, RefreshInterval: "1ns"}
+		assert.Nil(t, l.Allow(newRequest("a", "a", "1.1.1.1:80"), pattern))
+		assert.Nil(t, l.Allow(newRequest("a", "a", "1.1.1.2:80"), pattern))
+		rejection := l.Allow(newRequest("a", "a", "1.1.1.3:80"), pattern)
+		assert.NotNil(t, rejection)
+		assert.Equal(t, ratelimit.DimensionTenant, rejection.Dimension)
+		assert.Equal(t, "a/a", rejection.Key)
+		assert.True(t, rejection.RetryAfter > 0)
+		assert.Nil(t, l.Allow(newRequest("b", "b", "1.1.1.1:80"), pattern))
+	})
+
+	t.Run("limit the client ip", func(t *testing.T) {
+		c = &config.RateLimit{Unit: "m", IPLookups: "X-Forwarded-For,RemoteAddr", Connections: 1, RefreshInterval: "1ns"}
+		r := newRequest("a", "a", "1.1.1.1:80")
+		r.Header.Set("X-Forwarded-For", "2.2.2.2, 1.1.1.1")
+		assert.Nil(t, l.Allow(r, pattern))
+		rejection := l.Allow(r, pattern)
+		assert.NotNil(t, rejection)
+		assert.Equal(t, ratelimit.DimensionIP, rejection.Dimension)
+		assert.Equal(t, "2.2.2.2", rejection.Key)
+		assert.Nil(t, l.Allow(newRequest("a", "a", "1.1.1.1:80"), pattern))
+	})
+
+	t.Run("limit the api", func(t *testing.T) {
+		c = &config.RateLimit{Unit: "m", RefreshInterval: "1ns", Tenant: 2,
+			APIs: []config.APIRateLimit{{Method: "post", Pattern: pattern, Limit: 1}}}
+		assert.Nil(t, l.Allow(newRequest("a", "a", "1.1.1.1:80"), pattern))
+		rejection := l.Allow(newRequest("a", "a", "1.1.1.1:80"), pattern)
+		assert.NotNil(t, rejection)
+		assert.Equal(t, ratelimit.DimensionAPI, rejection.Dimension)
+
+		// the tenant token is returned when the api rejects
+		assert.Nil(t, l.Allow(newRequest("a", "a", "1.1.1.1:80"), "/v4/:project/registry/microservices/:serviceId"))
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "1.1.1.1:80"
+	r.Header.Set("X-Real-IP", "3.3.3.3")
+	assert.Equal(t, "1.1.1.1", ratelimit.ClientIP(r, "RemoteAddr,X-Forwarded-For,X-Real-IP"))
+	assert.Equal(t, "3.3.3.3", ratelimit.ClientIP(r, "X-Forwarded-For,X-Real-IP,RemoteAddr"))
+	assert.Equal(t, "", ratelimit.ClientIP(r, "X-Forwarded-For"))
+}
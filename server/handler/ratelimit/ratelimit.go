@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+)
+
+// limiter is shared by the handlers of the dimensions
+var limiter = NewLimiter(config.GetRateLimit)
+
+// Handler rejects the requests exceeding the rate limits of the dimensions with 429
+type Handler struct {
+	limiter    *Limiter
+	dimensions []string
+}
+
+func (h *Handler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CtxRequest).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CtxMatchPattern).(string)
+	rejection := h.limiter.Allow(r, pattern, h.dimensions...)
+	if rejection == nil {
+		i.Next()
+		return
+	}
+
+	// the rejections are counted by metrics, do not flood the log
+	log.Debugf("request is rate limited by %s [%s], %s %s", rejection.Dimension, rejection.Key, r.Method, r.RequestURI)
+
+	w := i.Context().Value(rest.CtxResponse).(http.ResponseWriter)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rejection.RetryAfter.Seconds()))))
+	controller.WriteError(w, errorsEx.ErrTooManyRequests,
+		fmt.Sprintf("too many requests of %s %s", rejection.Dimension, rejection.Key))
+
+	i.Fail(nil)
+}
+
+// RegisterIPHandlers registers the rate limit handler of client ip before the auth handler,
+// so the unauthenticated floods are rejected before verifying the tokens
+func RegisterIPHandlers() {
+	chain.RegisterHandler(rest.ServerChainName, &Handler{limiter: limiter, dimensions: []string{DimensionIP}})
+}
+
+// RegisterHandlers registers the rate limit handler of tenant and api after the context handler
+// which resolves the domain project, the limits are reloaded at runtime,
+// so the handlers are registered even if no limit is configured
+func RegisterHandlers() {
+	chain.RegisterHandler(rest.ServerChainName, &Handler{limiter: limiter, dimensions: []string{DimensionTenant, DimensionAPI}})
+}
@@ -0,0 +1,60 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+func init() {
+	Register("backend_connection", Readiness, &DefaultHealthChecker{})
+	Register("notify_queue", Readiness, CheckFunc(CheckNotifyQueue))
+	Register("syncer", Readiness, CheckFunc(CheckSyncer))
+}
+
+// CheckNotifyQueue fails if any notify queue usage exceeds 'health.maxQueueUsage' percent
+func CheckNotifyQueue() error {
+	max := config.GetInt("health.maxQueueUsage", 90)
+	var saturated []string
+	for _, p := range notify.Center().Processors() {
+		if p.Cap() == 0 {
+			continue
+		}
+		if usage := p.Len() * 100 / p.Cap(); usage >= max {
+			saturated = append(saturated, fmt.Sprintf("%s %d/%d", p.Name(), p.Len(), p.Cap()))
+		}
+	}
+	if len(saturated) > 0 {
+		return fmt.Errorf("queue saturated: %s", strings.Join(saturated, ", "))
+	}
+	return nil
+}
+
+// CheckSyncer fails if the websocket to syncer is lost
+func CheckSyncer() error {
+	for _, a := range alarm.ListAll() {
+		if a.ID == alarm.IDWebsocketOfScSyncerLost && a.Status != alarm.Cleared {
+			return errors.New(a.FieldString(alarm.FieldAdditionalContext))
+		}
+	}
+	return nil
+}
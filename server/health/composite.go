@@ -0,0 +1,201 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+// Scope decides which endpoints the check belongs to
+type Scope int
+
+const (
+	// Liveness checks fail if service center needs to be restarted
+	Liveness Scope = 1 << iota
+	// Readiness checks fail if service center can not serve the requests
+	Readiness
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+
+	DefaultTimeout = 3 * time.Second
+)
+
+var ErrCheckTimeout = errors.New("check timed out")
+
+// Check is a named Checker
+type Check struct {
+	Name    string
+	Scope   Scope
+	Checker Checker
+}
+
+// Result is the result of one check
+type Result struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	Latency string `json:"latency"`
+}
+
+// Report is the result of all the checks in a scope
+type Report struct {
+	Status string    `json:"status"`
+	Checks []*Result `json:"checks"`
+}
+
+// Healthy returns nil if all checks are up,
+// or an error contains the details of the down checks
+func (r *Report) Healthy() error {
+	if r.Status == StatusUp {
+		return nil
+	}
+	var details []string
+	for _, c := range r.Checks {
+		if c.Status != StatusUp {
+			details = append(details, c.Name+": "+c.Detail)
+		}
+	}
+	return errors.New(strings.Join(details, "; "))
+}
+
+var (
+	checksLock sync.RWMutex
+	checks     = make(map[string]*Check)
+)
+
+// Register adds a named check, the check of the same name is replaced
+func Register(name string, scope Scope, checker Checker) {
+	checksLock.Lock()
+	checks[name] = &Check{Name: name, Scope: scope, Checker: checker}
+	checksLock.Unlock()
+}
+
+// Unregister removes the named check
+func Unregister(name string) {
+	checksLock.Lock()
+	delete(checks, name)
+	checksLock.Unlock()
+}
+
+// Checks returns the checks in scope sorted by name
+func Checks(scope Scope) []*Check {
+	checksLock.RLock()
+	arr := make([]*Check, 0, len(checks))
+	for _, c := range checks {
+		if c.Scope&scope != 0 {
+			arr = append(arr, c)
+		}
+	}
+	checksLock.RUnlock()
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].Name < arr[j].Name
+	})
+	return arr
+}
+
+// Run runs the checks in scope concurrently, every check must finish in timeout
+func Run(ctx context.Context, scope Scope) *Report {
+	timeout := config.GetDuration("health.timeout", DefaultTimeout)
+	arr := Checks(scope)
+	report := &Report{Status: StatusUp, Checks: make([]*Result, len(arr))}
+	var wg sync.WaitGroup
+	for i, c := range arr {
+		wg.Add(1)
+		i, c := i, c
+		gopool.Go(func(_ context.Context) {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, c, timeout)
+		})
+	}
+	wg.Wait()
+	for _, r := range report.Checks {
+		if r.Status != StatusUp {
+			report.Status = StatusDown
+			break
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, c *Check, timeout time.Duration) *Result {
+	start := time.Now()
+	ch := make(chan error, 1)
+	gopool.Go(func(_ context.Context) {
+		ch <- c.Checker.Healthy()
+	})
+	var err error
+	select {
+	case err = <-ch:
+	case <-time.After(timeout):
+		err = ErrCheckTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	r := &Result{Name: c.Name, Status: StatusUp, Latency: time.Since(start).String()}
+	if err != nil {
+		r.Status, r.Detail = StatusDown, err.Error()
+	}
+	return r
+}
+
+// Live runs the liveness checks
+func Live(ctx context.Context) *Report {
+	return Run(ctx, Liveness)
+}
+
+// Ready runs the readiness checks
+func Ready(ctx context.Context) *Report {
+	return Run(ctx, Readiness)
+}
+
+// CompositeChecker is healthy if all the checks in scope are up
+type CompositeChecker struct {
+	Scope Scope
+}
+
+func (c *CompositeChecker) Healthy() error {
+	return Run(context.Background(), c.Scope).Healthy()
+}
+
+// NewLatencyChecker returns a Checker which fails if ping fails or
+// takes longer than 'health.maxBackendLatency'
+func NewLatencyChecker(ping func(ctx context.Context) error) Checker {
+	return CheckFunc(func() error {
+		max := config.GetDuration("health.maxBackendLatency", time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), config.GetDuration("health.timeout", DefaultTimeout))
+		defer cancel()
+		start := time.Now()
+		if err := ping(ctx); err != nil {
+			return err
+		}
+		if latency := time.Since(start); latency > max {
+			return fmt.Errorf("latency %s exceeds %s", latency, max)
+		}
+		return nil
+	})
+}
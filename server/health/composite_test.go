@@ -0,0 +1,80 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	_ = archaius.Init(archaius.WithMemorySource(), archaius.WithENVSource())
+}
+
+func TestRun(t *testing.T) {
+	Register("test_up", Liveness, CheckFunc(func() error { return nil }))
+	Register("test_down", Readiness, CheckFunc(func() error { return errors.New("down") }))
+	defer Unregister("test_up")
+	defer Unregister("test_down")
+
+	report := Live(context.Background())
+	assert.Equal(t, StatusUp, report.Status)
+	assert.NoError(t, report.Healthy())
+	found := false
+	for _, r := range report.Checks {
+		assert.NotEqual(t, "test_down", r.Name)
+		if r.Name == "test_up" {
+			found = true
+			assert.Equal(t, StatusUp, r.Status)
+		}
+	}
+	assert.True(t, found)
+
+	report = Ready(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Contains(t, report.Healthy().Error(), "test_down: down")
+	assert.Error(t, (&CompositeChecker{Scope: Readiness}).Healthy())
+
+	Unregister("test_down")
+	assert.NoError(t, (&CompositeChecker{Scope: Readiness}).Healthy())
+}
+
+func TestRun_Timeout(t *testing.T) {
+	Register("test_block", Liveness, CheckFunc(func() error {
+		time.Sleep(time.Minute)
+		return nil
+	}))
+	defer Unregister("test_block")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	report := Live(ctx)
+	assert.Equal(t, StatusDown, report.Status)
+}
+
+func TestNewLatencyChecker(t *testing.T) {
+	assert.NoError(t, NewLatencyChecker(func(ctx context.Context) error { return nil }).Healthy())
+	assert.Error(t, NewLatencyChecker(func(ctx context.Context) error { return errors.New("refused") }).Healthy())
+}
+
+func TestNewDiskChecker(t *testing.T) {
+	assert.NoError(t, NewDiskChecker(".").Healthy())
+}
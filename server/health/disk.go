@@ -0,0 +1,41 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package health
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+// NewDiskChecker returns a Checker which fails if the free space of dir
+// is less than 'health.minDiskFree' MB
+func NewDiskChecker(dir string) Checker {
+	return CheckFunc(func() error {
+		min := uint64(config.GetInt("health.minDiskFree", 500)) << 20
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(dir, &st); err != nil {
+			return err
+		}
+		if free := uint64(st.Bavail) * uint64(st.Bsize); free < min {
+			return fmt.Errorf("free space of %s is %dMB, less than %dMB", dir, free>>20, min>>20)
+		}
+		return nil
+	})
+}
@@ -27,6 +27,14 @@ type Checker interface {
 	Healthy() error
 }
 
+// CheckFunc is an adapter to allow the use of ordinary functions as Checker
+type CheckFunc func() error
+
+func (f CheckFunc) Healthy() error {
+	return f()
+}
+
+// DefaultHealthChecker checks the backend connection alarm
 type DefaultHealthChecker struct {
 }
 
@@ -0,0 +1,361 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package heartbeat
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	nf "github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const (
+	Group = "__HEARTBEAT_GROUP__"
+
+	DefaultFlushInterval = time.Second
+	DefaultMargin        = 30 * time.Second
+	DefaultBatchSize     = 100
+)
+
+// Store is the backend which renews the instance leases
+type Store interface {
+	Heartbeat(ctx context.Context, request *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error)
+	HeartbeatSet(ctx context.Context, request *pb.HeartbeatSetRequest) (*pb.HeartbeatSetResponse, error)
+	GetInstance(ctx context.Context, request *pb.GetOneInstanceRequest) (*pb.GetOneInstanceResponse, error)
+}
+
+type Options struct {
+	// FlushInterval is the interval of checking the leases to renew
+	FlushInterval time.Duration
+	// Margin is the min time left before the lease expires when renewing it,
+	// it is ttl/2 if the lease ttl is less than 2*Margin
+	Margin time.Duration
+	// BatchSize is the max number of leases renewed in one backend request
+	BatchSize int
+	Store     Store
+}
+
+type entry struct {
+	domainProject string
+	serviceID     string
+	instanceID    string
+	// account is the one renewed the lease in backend, which passed the owner check
+	account string
+	ttl     time.Duration
+	// renewed is the last time the lease was renewed in backend
+	renewed time.Time
+	// beats is the number of heartbeats acknowledged in memory since renewed
+	beats int
+}
+
+func (e *entry) deadline(margin time.Duration) time.Time {
+	if e.ttl < 2*margin {
+		margin = e.ttl / 2
+	}
+	return e.renewed.Add(e.ttl - margin)
+}
+
+// Aggregator acknowledges the heartbeats in memory and renews the leases
+// in batches before they expire.
+// The first heartbeat of an instance on a replica always renews the lease in backend,
+// so an instance switching to another replica is renewed immediately,
+// and the leases with heartbeats acknowledged are renewed before the replica stops
+type Aggregator struct {
+	nf.Subscriber
+	opts      Options
+	lock      sync.Mutex
+	entries   map[string]*entry
+	goroutine *gopool.Pool
+}
+
+func key(domainProject, serviceID, instanceID string) string {
+	return util.StringJoin([]string{domainProject, serviceID, instanceID}, "/")
+}
+
+// ack returns true if the heartbeat can be acknowledged in memory,
+// the heartbeats from other accounts are sent to backend to check the owner
+func (a *Aggregator) ack(ctx context.Context, domainProject, serviceID, instanceID string) bool {
+	account := rbacframe.OwnerFromContext(ctx)
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	e, ok := a.entries[key(domainProject, serviceID, instanceID)]
+	if !ok || e.account != account || !time.Now().Before(e.deadline(a.opts.Margin)) {
+		return false
+	}
+	e.beats++
+	return true
+}
+
+// learn records the instance lease just renewed in backend
+func (a *Aggregator) learn(ctx context.Context, domainProject, serviceID, instanceID string) {
+	k := key(domainProject, serviceID, instanceID)
+	account := rbacframe.OwnerFromContext(ctx)
+	a.lock.Lock()
+	if e, ok := a.entries[k]; ok {
+		e.account = account
+		e.renewed = time.Now()
+		a.saved(e.beats)
+		e.beats = 0
+		a.lock.Unlock()
+		return
+	}
+	a.lock.Unlock()
+
+	resp, err := a.opts.Store.GetInstance(ctx, &pb.GetOneInstanceRequest{
+		ProviderServiceId:  serviceID,
+		ProviderInstanceId: instanceID,
+	})
+	if err != nil || resp.Response.GetCode() != pb.ResponseSuccess || resp.Instance == nil {
+		log.Warnf("get instance[%s/%s] failed, its heartbeats will not be coalesced", serviceID, instanceID)
+		return
+	}
+	hc := resp.Instance.HealthCheck
+	if hc == nil || hc.Mode != pb.CHECK_BY_HEARTBEAT || hc.Interval <= 0 {
+		return
+	}
+	a.lock.Lock()
+	a.entries[k] = &entry{
+		domainProject: domainProject,
+		serviceID:     serviceID,
+		instanceID:    instanceID,
+		account:       account,
+		ttl:           time.Duration(hc.Interval*(hc.Times+1)) * time.Second,
+		renewed:       time.Now(),
+	}
+	a.lock.Unlock()
+}
+
+// Forget drops the instance, the following heartbeats will be sent to backend
+func (a *Aggregator) Forget(domainProject, serviceID, instanceID string) {
+	a.lock.Lock()
+	k := key(domainProject, serviceID, instanceID)
+	if e, ok := a.entries[k]; ok {
+		a.saved(e.beats)
+		delete(a.entries, k)
+	}
+	a.lock.Unlock()
+}
+
+func (a *Aggregator) saved(n int) {
+	if n > 0 {
+		metrics.ReportHeartbeatRenewalSaved(n)
+	}
+}
+
+func (a *Aggregator) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if a.ack(ctx, domainProject, in.ServiceId, in.InstanceId) {
+		metrics.ReportHeartbeat(metrics.HeartbeatCoalesced, 1)
+		return &pb.HeartbeatResponse{
+			Response: pb.CreateResponse(pb.ResponseSuccess,
+				"Update service instance heartbeat successfully."),
+		}, nil
+	}
+	metrics.ReportHeartbeat(metrics.HeartbeatRenewed, 1)
+	resp, err := a.opts.Store.Heartbeat(ctx, in)
+	if err == nil && resp.Response.GetCode() == pb.ResponseSuccess {
+		a.learn(ctx, domainProject, in.ServiceId, in.InstanceId)
+	}
+	return resp, err
+}
+
+func (a *Aggregator) HeartbeatSet(ctx context.Context, in *pb.HeartbeatSetRequest) (*pb.HeartbeatSetResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	exist := make(map[string]struct{}, len(in.Instances))
+	results := make([]*pb.InstanceHbRst, 0, len(in.Instances))
+	var elements []*pb.HeartbeatSetElement
+	for _, element := range in.Instances {
+		k := element.ServiceId + "/" + element.InstanceId
+		if _, ok := exist[k]; ok {
+			continue
+		}
+		exist[k] = struct{}{}
+		if a.ack(ctx, domainProject, element.ServiceId, element.InstanceId) {
+			results = append(results, &pb.InstanceHbRst{ServiceId: element.ServiceId, InstanceId: element.InstanceId})
+			continue
+		}
+		elements = append(elements, element)
+	}
+	metrics.ReportHeartbeat(metrics.HeartbeatCoalesced, len(results))
+	if len(elements) == 0 {
+		return &pb.HeartbeatSetResponse{
+			Response:  pb.CreateResponse(pb.ResponseSuccess, "Heartbeat set successfully."),
+			Instances: results,
+		}, nil
+	}
+
+	metrics.ReportHeartbeat(metrics.HeartbeatRenewed, len(elements))
+	resp, err := a.opts.Store.HeartbeatSet(ctx, &pb.HeartbeatSetRequest{Instances: elements})
+	if err != nil {
+		return resp, err
+	}
+	for _, rst := range resp.Instances {
+		if len(rst.ErrMessage) == 0 {
+			a.learn(ctx, domainProject, rst.ServiceId, rst.InstanceId)
+		}
+	}
+	resp.Instances = append(results, resp.Instances...)
+	return resp, nil
+}
+
+// due returns the entries whose leases should be renewed before next flush,
+// the entries without heartbeats until the lease expired are dropped
+func (a *Aggregator) due(all bool) map[string][]*entry {
+	now := time.Now()
+	groups := make(map[string][]*entry)
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for k, e := range a.entries {
+		if e.beats == 0 {
+			if now.After(e.renewed.Add(e.ttl)) {
+				delete(a.entries, k)
+			}
+			continue
+		}
+		if all || !now.Add(a.opts.FlushInterval).Before(e.deadline(a.opts.Margin)) {
+			groups[e.domainProject] = append(groups[e.domainProject], e)
+		}
+	}
+	return groups
+}
+
+// Flush renews the due leases in batches, all the leases with heartbeats
+// acknowledged are renewed if all is true
+func (a *Aggregator) Flush(ctx context.Context, all bool) {
+	for domainProject, entries := range a.due(all) {
+		domain, project := util.FromDomainProject(domainProject)
+		dctx := util.SetDomainProject(util.CloneContext(ctx), domain, project)
+		for i := 0; i < len(entries); i += a.opts.BatchSize {
+			end := i + a.opts.BatchSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			a.renew(dctx, domainProject, entries[i:end])
+		}
+	}
+}
+
+func (a *Aggregator) renew(ctx context.Context, domainProject string, entries []*entry) {
+	request := &pb.HeartbeatSetRequest{Instances: make([]*pb.HeartbeatSetElement, 0, len(entries))}
+	for _, e := range entries {
+		request.Instances = append(request.Instances, &pb.HeartbeatSetElement{
+			ServiceId:  e.serviceID,
+			InstanceId: e.instanceID,
+		})
+	}
+	now := time.Now()
+	resp, err := a.opts.Store.HeartbeatSet(ctx, request)
+	if err != nil {
+		// retry in next flush, the heartbeats go to backend after the deadline
+		log.Errorf(err, "renew %d instance leases of domain project[%s] failed", len(entries), domainProject)
+		metrics.ReportHeartbeatFlushed(len(entries), err)
+		return
+	}
+	metrics.ReportHeartbeatFlushed(len(entries), nil)
+
+	failed := make(map[string]string)
+	for _, rst := range resp.Instances {
+		if len(rst.ErrMessage) > 0 {
+			failed[rst.ServiceId+"/"+rst.InstanceId] = rst.ErrMessage
+		}
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for _, e := range entries {
+		k := key(e.domainProject, e.serviceID, e.instanceID)
+		if msg, ok := failed[e.serviceID+"/"+e.instanceID]; ok {
+			// the next heartbeat will get the error from backend
+			log.Warnf("renew instance[%s/%s] lease failed, %s", e.serviceID, e.instanceID, msg)
+			delete(a.entries, k)
+			continue
+		}
+		a.saved(e.beats - 1)
+		e.renewed = now
+		e.beats = 0
+	}
+}
+
+// OnMessage drops the deleted instances
+func (a *Aggregator) OnMessage(evt nf.Event) {
+	re, ok := evt.(*notify.ResourceEvent)
+	if !ok || re.Resource != notify.ResourceInstance || re.Action != string(pb.EVT_DELETE) {
+		return
+	}
+	instance, ok := re.Value.(*pb.MicroServiceInstance)
+	if !ok {
+		return
+	}
+	a.Forget(re.DomainProject, instance.ServiceId, instance.InstanceId)
+}
+
+// Len returns the number of instances cached
+func (a *Aggregator) Len() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return len(a.entries)
+}
+
+func (a *Aggregator) loop(ctx context.Context) {
+	ticker := time.NewTicker(a.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Flush(ctx, false)
+		}
+	}
+}
+
+func (a *Aggregator) Start() {
+	a.goroutine.Do(a.loop)
+}
+
+// Stop renews all the leases with heartbeats acknowledged,
+// so that the instances do not expire while switching to other replicas
+func (a *Aggregator) Stop() {
+	a.goroutine.Close(true)
+	a.Flush(context.Background(), true)
+}
+
+func NewAggregator(opts Options) *Aggregator {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.Margin <= 0 {
+		opts.Margin = DefaultMargin
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	return &Aggregator{
+		Subscriber: nf.NewSubscriber(notify.RESOURCE, notify.ResourceSubject, Group),
+		opts:       opts,
+		entries:    make(map[string]*entry),
+		goroutine:  gopool.New(context.Background()),
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package heartbeat_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/heartbeat"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStore struct {
+	lock     sync.Mutex
+	renewals map[string]int
+	missing  map[string]bool
+}
+
+func (s *mockStore) renew(serviceID, instanceID string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.missing[instanceID] {
+		return false
+	}
+	s.renewals[instanceID]++
+	return true
+}
+
+func (s *mockStore) count(instanceID string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.renewals[instanceID]
+}
+
+func (s *mockStore) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	if !s.renew(in.ServiceId, in.InstanceId) {
+		return &pb.HeartbeatResponse{Response: pb.CreateResponse(pb.ErrInstanceNotExists, "not exist")}, nil
+	}
+	return &pb.HeartbeatResponse{Response: pb.CreateResponse(pb.ResponseSuccess, "")}, nil
+}
+
+func (s *mockStore) HeartbeatSet(ctx context.Context, in *pb.HeartbeatSetRequest) (*pb.HeartbeatSetResponse, error) {
+	resp := &pb.HeartbeatSetResponse{Response: pb.CreateResponse(pb.ResponseSuccess, "")}
+	for _, e := range in.Instances {
+		rst := &pb.InstanceHbRst{ServiceId: e.ServiceId, InstanceId: e.InstanceId}
+		if !s.renew(e.ServiceId, e.InstanceId) {
+			rst.ErrMessage = "not exist"
+			resp.Response = pb.CreateResponse(pb.ErrInstanceNotExists, "Heartbeat set failed.")
+		}
+		resp.Instances = append(resp.Instances, rst)
+	}
+	return resp, nil
+}
+
+func (s *mockStore) GetInstance(ctx context.Context, in *pb.GetOneInstanceRequest) (*pb.GetOneInstanceResponse, error) {
+	return &pb.GetOneInstanceResponse{
+		Response: pb.CreateResponse(pb.ResponseSuccess, ""),
+		Instance: &pb.MicroServiceInstance{
+			ServiceId:   in.ProviderServiceId,
+			InstanceId:  in.ProviderInstanceId,
+			HealthCheck: &pb.HealthCheck{Mode: pb.CHECK_BY_HEARTBEAT, Interval: 30, Times: 3},
+		},
+	}, nil
+}
+
+func newAggregator(margin time.Duration) (*heartbeat.Aggregator, *mockStore) {
+	store := &mockStore{renewals: make(map[string]int), missing: make(map[string]bool)}
+	return heartbeat.NewAggregator(heartbeat.Options{Margin: margin, Store: store}), store
+}
+
+func TestAggregator_Heartbeat(t *testing.T) {
+	a, store := newAggregator(0)
+	ctx := util.SetDomainProject(context.Background(), "default", "default")
+	req := &pb.HeartbeatRequest{ServiceId: "s1", InstanceId: "i1"}
+
+	for i := 0; i < 5; i++ {
+		resp, err := a.Heartbeat(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	}
+	// only the first heartbeat renews the lease
+	assert.Equal(t, 1, store.count("i1"))
+	assert.Equal(t, 1, a.Len())
+
+	// not due yet
+	a.Flush(ctx, false)
+	assert.Equal(t, 1, store.count("i1"))
+	// flush all the acknowledged heartbeats before stop
+	a.Flush(ctx, true)
+	assert.Equal(t, 2, store.count("i1"))
+	a.Flush(ctx, true)
+	assert.Equal(t, 2, store.count("i1"))
+
+	// the instance deleted by other replica
+	a.Heartbeat(ctx, req)
+	store.missing["i1"] = true
+	a.Flush(ctx, true)
+	assert.Equal(t, 0, a.Len())
+	resp, err := a.Heartbeat(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ErrInstanceNotExists, resp.Response.GetCode())
+}
+
+func TestAggregator_HeartbeatSet(t *testing.T) {
+	a, store := newAggregator(0)
+	ctx := util.SetDomainProject(context.Background(), "default", "default")
+	req := &pb.HeartbeatSetRequest{Instances: []*pb.HeartbeatSetElement{
+		{ServiceId: "s1", InstanceId: "i1"},
+		{ServiceId: "s1", InstanceId: "i2"},
+		{ServiceId: "s1", InstanceId: "i2"},
+	}}
+	resp, err := a.HeartbeatSet(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	assert.Equal(t, 2, len(resp.Instances))
+
+	resp, err = a.HeartbeatSet(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	assert.Equal(t, 2, len(resp.Instances))
+	assert.Equal(t, 1, store.count("i1"))
+	assert.Equal(t, 1, store.count("i2"))
+
+	a.OnMessage(notify.NewResourceEvent(notify.ResourceInstance, string(pb.EVT_DELETE), "default/default",
+		"i2", nil, &pb.MicroServiceInstance{ServiceId: "s1", InstanceId: "i2"}))
+	assert.Equal(t, 1, a.Len())
+
+	store.missing["i2"] = true
+	resp, err = a.HeartbeatSet(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ErrInstanceNotExists, resp.Response.GetCode())
+	assert.Equal(t, 2, len(resp.Instances))
+}
+
+func TestAggregator_Deadline(t *testing.T) {
+	// the margin is more than ttl/2, renew every ttl/2
+	a, store := newAggregator(time.Hour)
+	ctx := util.SetDomainProject(context.Background(), "default", "default")
+	req := &pb.HeartbeatRequest{ServiceId: "s1", InstanceId: "i1"}
+	a.Heartbeat(ctx, req)
+	a.Heartbeat(ctx, req)
+	assert.Equal(t, 1, store.count("i1"))
+	a.Flush(ctx, false)
+	assert.Equal(t, 1, store.count("i1"))
+}
+
+func TestAggregator_Owner(t *testing.T) {
+	a, store := newAggregator(0)
+	withAccount := func(name string) context.Context {
+		return rbacframe.NewContext(util.SetDomainProject(context.Background(), "default", "default"),
+			map[string]interface{}{
+				rbacframe.ClaimsUser:  name,
+				rbacframe.ClaimsRoles: []interface{}{"developer"},
+			})
+	}
+	req := &pb.HeartbeatRequest{ServiceId: "s1", InstanceId: "i1"}
+	a.Heartbeat(withAccount("owner"), req)
+	a.Heartbeat(withAccount("owner"), req)
+	assert.Equal(t, 1, store.count("i1"))
+
+	// the heartbeats from other accounts are checked by backend,
+	// the account passed the check last time is coalesced
+	a.Heartbeat(withAccount("other"), req)
+	assert.Equal(t, 2, store.count("i1"))
+	a.HeartbeatSet(withAccount("owner"), &pb.HeartbeatSetRequest{Instances: []*pb.HeartbeatSetElement{
+		{ServiceId: "s1", InstanceId: "i1"},
+	}})
+	assert.Equal(t, 3, store.count("i1"))
+}
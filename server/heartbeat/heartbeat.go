@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package heartbeat
+
+import (
+	"sync"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+var (
+	aggregator *Aggregator
+	once       sync.Once
+)
+
+// Enabled returns true if the heartbeats coalescing is enabled
+func Enabled() bool {
+	return config.GetBool("registry.instance.heartbeat.coalesce", false)
+}
+
+// Init starts the aggregator if heartbeats coalescing is enabled
+func Init() {
+	if !Enabled() {
+		return
+	}
+	once.Do(func() {
+		a := NewAggregator(Options{
+			FlushInterval: config.GetDuration("registry.instance.heartbeat.flushInterval", DefaultFlushInterval),
+			Margin:        config.GetDuration("registry.instance.heartbeat.margin", DefaultMargin),
+			BatchSize:     config.GetInt("registry.instance.heartbeat.batchSize", DefaultBatchSize),
+			Store:         datasource.Instance(),
+		})
+		if err := notify.Center().AddSubscriber(a); err != nil {
+			log.Error("add heartbeat aggregator failed", err)
+			return
+		}
+		a.Start()
+		aggregator = a
+		log.Info("heartbeat aggregator started")
+	})
+}
+
+// Instance returns the aggregator, it is nil if heartbeats coalescing is disabled
+func Instance() *Aggregator {
+	return aggregator
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/metrics"
+	helper "github.com/apache/servicecomb-service-center/pkg/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// HeartbeatCoalesced means the heartbeat is acknowledged in memory
+	HeartbeatCoalesced = "coalesced"
+	// HeartbeatRenewed means the heartbeat renews the lease in backend
+	HeartbeatRenewed = "renewed"
+)
+
+var (
+	heartbeatReceivedCounter = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "heartbeat",
+			Name:      "received_total",
+			Help:      "Counter of heartbeats received by the aggregator",
+		}, []string{"instance", "mode"})
+
+	heartbeatFlushCounter = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "heartbeat",
+			Name:      "flushed_total",
+			Help:      "Counter of leases renewed in batches by the aggregator",
+		}, []string{"instance", "status"})
+
+	heartbeatSavedCounter = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "heartbeat",
+			Name:      "renewal_saved_total",
+			Help:      "Counter of backend lease renewals saved by coalescing heartbeats",
+		}, []string{"instance"})
+)
+
+func ReportHeartbeat(mode string, n int) {
+	if n <= 0 {
+		return
+	}
+	heartbeatReceivedCounter.WithLabelValues(metrics.InstanceName(), mode).Add(float64(n))
+}
+
+func ReportHeartbeatFlushed(n int, err error) {
+	status := success
+	if err != nil {
+		status = failure
+	}
+	heartbeatFlushCounter.WithLabelValues(metrics.InstanceName(), status).Add(float64(n))
+}
+
+func ReportHeartbeatRenewalSaved(n int) {
+	heartbeatSavedCounter.WithLabelValues(metrics.InstanceName()).Add(float64(n))
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/metrics"
+	helper "github.com/apache/servicecomb-service-center/pkg/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	allowed  = "allowed"
+	rejected = "rejected"
+)
+
+var (
+	rateLimitCounter = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "http",
+			Name:      "rate_limit_total",
+			Help:      "Counter of requests checked by rate limits",
+		}, []string{"instance", "dimension", "api", "result"})
+)
+
+// ReportRateLimited counts the request checked by the rate limit of dimension,
+// the api is the key of the api dimension, the configured route patterns are bounded.
+// The keys of the other dimensions are not labeled, the client ips and tenants are unbounded
+func ReportRateLimited(dimension, api string, ok bool) {
+	instance := metrics.InstanceName()
+	result := allowed
+	if !ok {
+		result = rejected
+	}
+	rateLimitCounter.WithLabelValues(instance, dimension, api, result).Inc()
+}
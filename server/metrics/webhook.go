@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/metrics"
+	helper "github.com/apache/servicecomb-service-center/pkg/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	webhookCounter = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "webhook",
+			Name:      "delivery_total",
+			Help:      "Counter of webhook deliveries",
+		}, []string{"instance", "resource", "status"})
+
+	webhookLatency = helper.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  metrics.FamilyName,
+			Subsystem:  "webhook",
+			Name:       "delivery_durations_microseconds",
+			Help:       "Latency of webhook deliveries",
+			Objectives: metrics.Pxx,
+		}, []string{"instance", "resource", "status"})
+
+	webhookDeadLetterCounter = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "webhook",
+			Name:      "dead_letter_total",
+			Help:      "Counter of webhook deliveries given up after retries",
+		}, []string{"instance", "resource"})
+)
+
+func ReportWebhookDelivered(resource string, err error, start time.Time) {
+	instance := metrics.InstanceName()
+	elapsed := float64(time.Since(start).Nanoseconds()) / float64(time.Microsecond)
+	status := success
+	if err != nil {
+		status = failure
+	}
+	webhookLatency.WithLabelValues(instance, resource, status).Observe(elapsed)
+	webhookCounter.WithLabelValues(instance, resource, status).Inc()
+}
+
+func ReportWebhookDeadLetter(resource string) {
+	instance := metrics.InstanceName()
+	webhookDeadLetterCounter.WithLabelValues(instance, resource).Inc()
+}
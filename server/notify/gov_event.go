@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/notify"
+)
+
+// GovernanceSubject is the only subject of GOVERNANCE events
+const GovernanceSubject = "__GOVERNANCE_SUBJECT__"
+
+var GOVERNANCE = notify.RegisterType("GOVERNANCE", EventQueueSize)
+
+// GovRevisionEvent is the change of the governance revision of a project,
+// it is published by the datasource watches, the empty Project means any project may change
+type GovRevisionEvent struct {
+	notify.Event
+	Project  string
+	Revision int64
+}
+
+func NewGovRevisionEvent(project string, revision int64) *GovRevisionEvent {
+	return &GovRevisionEvent{
+		Event:    notify.NewEvent(GOVERNANCE, GovernanceSubject, ""),
+		Project:  project,
+		Revision: revision,
+	}
+}
+
+// PublishGovRevisionEvent broadcasts the event to all GOVERNANCE subscribers
+func PublishGovRevisionEvent(evt *GovRevisionEvent) {
+	if notifyService.Closed() || !notifyService.Subscribed(GOVERNANCE) {
+		return
+	}
+	if err := notifyService.Publish(evt); err != nil {
+		log.Errorf(err, "publish governance revision[%s] event failed", evt.Project)
+	}
+}
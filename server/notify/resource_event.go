@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// ResourceSubject is the only subject of RESOURCE events,
+// subscribers use different groups to receive every registry change
+const ResourceSubject = "__RESOURCE_SUBJECT__"
+
+const (
+	ResourceService  = "service"
+	ResourceInstance = "instance"
+	ResourceSchema   = "schema"
+	ResourceRule     = "rule"
+)
+
+var RESOURCE = notify.RegisterType("RESOURCE", EventQueueSize)
+
+// ResourceEvent is the change of a registry resource,
+// it is published by the datasource event handlers
+type ResourceEvent struct {
+	notify.Event
+	Resource      string
+	Action        string
+	DomainProject string
+	// ResourceID is the id of the changed resource
+	ResourceID string
+	// Service is the service which the resource belongs to, it may be nil
+	Service *pb.MicroService
+	// Value is the resource object after changed, or before deleted
+	Value interface{}
+}
+
+func NewResourceEvent(resource, action, domainProject, resourceID string,
+	service *pb.MicroService, value interface{}) *ResourceEvent {
+	return &ResourceEvent{
+		Event:         notify.NewEvent(RESOURCE, ResourceSubject, ""),
+		Resource:      resource,
+		Action:        action,
+		DomainProject: domainProject,
+		ResourceID:    resourceID,
+		Service:       service,
+		Value:         value,
+	}
+}
+
+// PublishResourceEvent broadcasts the event to all RESOURCE subscribers,
+// it does nothing if there is no any subscriber
+func PublishResourceEvent(evt *ResourceEvent) {
+	if notifyService.Closed() || !notifyService.Subscribed(RESOURCE) {
+		return
+	}
+	if err := notifyService.Publish(evt); err != nil {
+		log.Errorf(err, "publish %s[%s] %s event failed", evt.Resource, evt.ResourceID, evt.Action)
+	}
+}
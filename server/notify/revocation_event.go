@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notify
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+// RevocationSubject is the only subject of REVOCATION events
+const RevocationSubject = "__REVOCATION_SUBJECT__"
+
+var REVOCATION = notify.RegisterType("REVOCATION", EventQueueSize)
+
+// RevocationEvent is the change of the token denylist,
+// it is published by the datasource event handlers
+type RevocationEvent struct {
+	notify.Event
+	Action     string
+	Revocation *rbacframe.Revocation
+}
+
+func NewRevocationEvent(action string, r *rbacframe.Revocation) *RevocationEvent {
+	return &RevocationEvent{
+		Event:      notify.NewEvent(REVOCATION, RevocationSubject, ""),
+		Action:     action,
+		Revocation: r,
+	}
+}
+
+// PublishRevocationEvent broadcasts the event to all REVOCATION subscribers
+func PublishRevocationEvent(evt *RevocationEvent) {
+	if notifyService.Closed() || !notifyService.Subscribed(REVOCATION) {
+		return
+	}
+	if err := notifyService.Publish(evt); err != nil {
+		log.Errorf(err, "publish revocation[%s] %s event failed", evt.Revocation.ID, evt.Action)
+	}
+}
@@ -20,6 +20,7 @@ package auditlog
 import (
 	"net/http"
 
+	"github.com/apache/servicecomb-service-center/server/config"
 	"github.com/apache/servicecomb-service-center/server/plugin"
 )
 
@@ -29,6 +30,28 @@ type AuditLogger interface {
 	Record(r *http.Request, responseHeaders http.Header)
 }
 
+//StatusRecorder is optionally implemented by the AuditLogger,
+//it records the status code written by the handlers, which may be absent in the response headers
+type StatusRecorder interface {
+	RecordStatus(r *http.Request, responseHeaders http.Header, statusCode int)
+}
+
+//Enabled returns true if the mutating requests should be recorded
+func Enabled() bool {
+	return config.GetBool("audit.enable", false)
+}
+
 func Record(r *http.Request, responseHeaders http.Header) {
 	plugin.Plugins().Instance(AUDITLOG).(AuditLogger).Record(r, responseHeaders)
 }
+
+//RecordStatus records the request with the status code if the plugin is a StatusRecorder,
+//or records it by the response headers
+func RecordStatus(r *http.Request, responseHeaders http.Header, statusCode int) {
+	l := plugin.Plugins().Instance(AUDITLOG).(AuditLogger)
+	if sr, ok := l.(StatusRecorder); ok {
+		sr.RecordStatus(r, responseHeaders, statusCode)
+		return
+	}
+	l.Record(r, responseHeaders)
+}
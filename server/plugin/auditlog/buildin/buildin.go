@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/config"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+)
+
+const (
+	defaultRetention = 30 * 24 * time.Hour
+	// the records are saved to datasource in batches, asynchronously
+	queueSize     = 10000
+	batchSize     = 100
+	flushInterval = time.Second
+)
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{Kind: auditlog.AUDITLOG, Name: "buildin", New: New})
+}
+
+func New() mgr.Instance {
+	if !auditlog.Enabled() {
+		// records nothing, and does not create the audit file
+		return &Logger{}
+	}
+	logger := log.NewLogger(log.Config{
+		LoggerFile:     os.ExpandEnv(config.GetString("audit.file", "./audit.log")),
+		LogFormatText:  true,
+		LogRotateSize:  int(config.GetLog().LogRotateSize),
+		LogBackupCount: int(config.GetLog().LogBackupCount),
+		NoCaller:       true,
+		NoTime:         true,
+		NoLevel:        true,
+	})
+	l := &Logger{
+		logger:    logger,
+		persist:   config.GetBool("audit.persist", false),
+		retention: config.GetDuration("audit.retention", defaultRetention),
+	}
+	if l.persist {
+		l.queue = make(chan *audit.Record, queueSize)
+		gopool.Go(l.run)
+	}
+	return l
+}
+
+//Logger writes the audit records to a rotating file as json lines,
+//and saves them to datasource if persist is enabled, it records nothing if audit is disabled
+type Logger struct {
+	logger    *log.Logger
+	persist   bool
+	retention time.Duration
+	queue     chan *audit.Record
+}
+
+//Record records the request with the status in the response headers
+func (l *Logger) Record(r *http.Request, responseHeaders http.Header) {
+	status, _ := strconv.Atoi(responseHeaders.Get(rest.HeaderResponseStatus))
+	l.RecordStatus(r, responseHeaders, status)
+}
+
+func (l *Logger) RecordStatus(r *http.Request, _ http.Header, statusCode int) {
+	if l.logger == nil {
+		return
+	}
+	record := NewRecord(r, statusCode)
+	b, err := json.Marshal(record)
+	if err != nil {
+		log.Error("marshal audit record failed", err)
+		return
+	}
+	l.logger.Info(string(b))
+	if !l.persist {
+		return
+	}
+	select {
+	case l.queue <- record:
+	default:
+		log.Warnf("audit queue is full, drop record %s %s", record.Method, record.Pattern)
+	}
+}
+
+func (l *Logger) run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	records := make([]*audit.Record, 0, batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			l.save(records)
+			return
+		case r := <-l.queue:
+			records = append(records, r)
+			if len(records) < batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(records) == 0 {
+				continue
+			}
+		}
+		l.save(records)
+		records = records[:0]
+	}
+}
+
+func (l *Logger) save(records []*audit.Record) {
+	if len(records) == 0 {
+		return
+	}
+	if err := datasource.Instance().PutAuditRecords(context.Background(), records, l.retention); err != nil {
+		log.Errorf(err, "save %d audit records failed", len(records))
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	err := archaius.Init(archaius.WithMemorySource())
+	assert.NoError(t, err)
+
+	t.Run("audit is disabled, should record nothing", func(t *testing.T) {
+		l, ok := buildin.New().(*buildin.Logger)
+		assert.True(t, ok)
+		var _ auditlog.StatusRecorder = l
+		r := httptest.NewRequest(http.MethodPost, "/v4/default/registry/microservices", nil)
+		l.Record(r, http.Header{})
+		l.RecordStatus(r, http.Header{}, http.StatusOK)
+		_, err := os.Stat("./audit.log")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
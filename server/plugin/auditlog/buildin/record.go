@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+//NewRecord returns the audit record of the handled request
+func NewRecord(r *http.Request, statusCode int) *audit.Record {
+	ctx := r.Context()
+	pattern, ok := ctx.Value(rest.CtxMatchPattern).(string)
+	if !ok {
+		pattern = r.URL.Path
+	}
+	record := &audit.Record{
+		ID:         util.GenerateUUID(),
+		Domain:     util.ParseDomain(ctx),
+		Project:    util.ParseProject(ctx),
+		RemoteIP:   util.GetRealIP(r),
+		Method:     r.Method,
+		Pattern:    pattern,
+		Resource:   rbacframe.GetResource(pattern),
+		Verb:       verb(r.Method),
+		Targets:    targets(r.URL.Query()),
+		StatusCode: statusCode,
+		Timestamp:  time.Now().Unix(),
+	}
+	if account, err := rbacframe.AccountFromContext(ctx); err == nil {
+		record.Account, record.Roles = account.Name, account.Roles
+	}
+	return record
+}
+
+//verb returns the rbac verb of the method, or the lower case method if no verb maps to it
+func verb(method string) string {
+	if v, ok := rbac.MethodToVerbs[method]; ok {
+		return v
+	}
+	return strings.ToLower(method)
+}
+
+//targets returns the path parameters except project
+func targets(query url.Values) map[string]string {
+	m := make(map[string]string)
+	for key := range query {
+		if !strings.HasPrefix(key, ":") || key == ":project" {
+			continue
+		}
+		m[strings.TrimPrefix(key, ":")] = query.Get(key)
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
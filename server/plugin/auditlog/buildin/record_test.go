@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecord(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete,
+		"/v4/default/registry/microservices/s1?:project=default&:serviceId=s1", nil)
+	r.RemoteAddr = "127.0.0.1:30100"
+	ctx := context.WithValue(r.Context(), rest.CtxMatchPattern, "/v4/:project/registry/microservices/:serviceId")
+	ctx = util.SetDomainProject(ctx, "default", "default")
+	ctx = rbacframe.NewContext(ctx, map[string]interface{}{
+		rbacframe.ClaimsUser:  "root",
+		rbacframe.ClaimsRoles: []interface{}{rbacframe.RoleAdmin},
+	})
+	record := buildin.NewRecord(r.WithContext(ctx), http.StatusNoContent)
+	assert.NotEmpty(t, record.ID)
+	assert.Equal(t, "default", record.Domain)
+	assert.Equal(t, "root", record.Account)
+	assert.Equal(t, []string{rbacframe.RoleAdmin}, record.Roles)
+	assert.Equal(t, "127.0.0.1", record.RemoteIP)
+	assert.Equal(t, "/v4/:project/registry/microservices/:serviceId", record.Pattern)
+	assert.Equal(t, "delete", record.Verb)
+	assert.Equal(t, map[string]string{"serviceId": "s1"}, record.Targets)
+	assert.Equal(t, http.StatusNoContent, record.StatusCode)
+}
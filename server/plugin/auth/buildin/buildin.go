@@ -23,13 +23,17 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
 	mgr "github.com/apache/servicecomb-service-center/server/plugin"
 	"github.com/apache/servicecomb-service-center/server/plugin/auth"
 	"github.com/apache/servicecomb-service-center/server/service/rbac"
+	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
 	"github.com/go-chassis/go-chassis/v2/server/restful"
 )
@@ -62,8 +66,14 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 		return rbacframe.ErrInvalidHeader
 	}
 	to := s[1]
+	var err error
 
-	claims, err := authr.Authenticate(req.Context(), to)
+	var claims interface{}
+	if rbacframe.IsAPIKey(to) {
+		claims, err = authenticateAPIKey(req, to)
+	} else {
+		claims, err = authr.Authenticate(req.Context(), to)
+	}
 	if err != nil {
 		log.Errorf(err, "authenticate request failed, %s %s", req.Method, req.RequestURI)
 		return err
@@ -73,7 +83,16 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 		log.Error("claims convert failed", rbacframe.ErrConvertErr)
 		return rbacframe.ErrConvertErr
 	}
-	roles := m[rbacframe.ClaimsRoles]
+	return Authorize(req, m)
+}
+
+//Authorize checks the permissions of the claims to the request,
+//then puts the claims and scope into the request context
+func Authorize(req *http.Request, claims map[string]interface{}) error {
+	if expired, _ := claims[rbacframe.ClaimsPasswordExpired].(bool); expired {
+		return checkPasswordChange(req, claims)
+	}
+	roles := claims[rbacframe.ClaimsRoles]
 	roleList, err := rbacframe.GetRolesList(roles)
 	if err != nil {
 		log.Error("role convert failed ", err)
@@ -91,30 +110,132 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 
 	project := req.URL.Query().Get(":project")
 	verbs := rbac.MethodToVerbs[req.Method]
-	err = checkPerm(roleList, project, apiPattern, verbs)
+	scope, err := checkPerm(roleList, project, apiPattern, verbs)
 	if err != nil {
 		return err
 	}
-	req2 := req.WithContext(rbacframe.NewContext(req.Context(), claims))
+	err = checkScope(req, apiPattern, scope)
+	if err != nil {
+		return err
+	}
+	ctx := rbacframe.NewContext(req.Context(), claims)
+	req2 := req.WithContext(rbacframe.WithScope(ctx, scope))
 	*req = *req2
 	return nil
 }
 
+//checkPasswordChange only allows the account with expired password to change its own password
+func checkPasswordChange(req *http.Request, claims map[string]interface{}) error {
+	pattern, _ := req.Context().Value(rest.CtxMatchPattern).(string)
+	user, _ := claims[rbacframe.ClaimsUser].(string)
+	if pattern != rbac.APIUserPassword || req.Method != http.MethodPost || req.URL.Query().Get(":name") != user {
+		return rbac.ErrPasswordExpired
+	}
+	*req = *req.WithContext(rbacframe.NewContext(req.Context(), claims))
+	return nil
+}
+
+//authenticateAPIKey returns the claims of the api key,
+//the key can only access the projects it is scoped to
+func authenticateAPIKey(req *http.Request, plain string) (interface{}, error) {
+	key, err := rbac.AuthenticateAPIKey(req.Context(), plain)
+	if err != nil {
+		return nil, err
+	}
+	if !key.MatchProject(req.URL.Query().Get(":project")) {
+		return nil, errors.New(errorsEx.MsgNoPerm)
+	}
+	roles := make([]interface{}, 0, len(key.Roles))
+	for _, r := range key.Roles {
+		roles = append(roles, r)
+	}
+	return map[string]interface{}{
+		rbacframe.ClaimsUser:  key.Account,
+		rbacframe.ClaimsRoles: roles,
+	}, nil
+}
+
 //this method decouple business code and perm checks
-func checkPerm(roleList []string, project, apiPattern, verbs string) error {
+func checkPerm(roleList []string, project, apiPattern, verbs string) (*rbacframe.Scope, error) {
 	resource := rbacframe.GetResource(apiPattern)
 	if resource == "" {
 		//fast fail, no need to access role storage
-		return errors.New(errorsEx.MsgNoPerm)
+		return nil, errors.New(errorsEx.MsgNoPerm)
 	}
-	//TODO add verbs,project
-	allow, err := rbac.Allow(context.TODO(), roleList, project, resource, verbs)
+	scope, allow, err := rbac.GetScope(context.TODO(), roleList, project, resource, verbs)
 	if err != nil {
 		log.Error("", err)
-		return errors.New(errorsEx.MsgRolePerm)
+		return nil, errors.New(errorsEx.MsgRolePerm)
 	}
 	if !allow {
+		return nil, errors.New(errorsEx.MsgNoPerm)
+	}
+	return scope, nil
+}
+
+//unscopedAPIs take the services in request body, or aggregate the services
+//without filtering by labels, so they are denied to the scoped requests
+var unscopedAPIs = map[string]struct{}{
+	http.MethodPost + " /v4/:project/registry/instances/action": {},
+	http.MethodDelete + " /v4/:project/registry/microservices":  {},
+	http.MethodPut + " /v4/:project/registry/heartbeats":        {},
+	http.MethodPost + " /v4/:project/registry/dependencies":     {},
+	http.MethodPut + " /v4/:project/registry/dependencies":      {},
+	http.MethodGet + " /v4/:project/govern/relations":           {},
+	http.MethodGet + " /v4/:project/govern/apps":                {},
+	http.MethodGet + " /v4/:project/govern/statistics":          {},
+}
+
+//checkScope checks the labels of the services requested by path or query,
+//the list apis will filter the results by scope in service layer
+func checkScope(req *http.Request, apiPattern string, scope *rbacframe.Scope) error {
+	if scope == nil {
+		return nil
+	}
+	if _, ok := unscopedAPIs[req.Method+" "+apiPattern]; ok {
 		return errors.New(errorsEx.MsgNoPerm)
 	}
+	query := req.URL.Query()
+	if appID, serviceName := query.Get("appId"), query.Get("serviceName"); len(serviceName) > 0 {
+		labels := map[string]string{
+			rbacframe.LabelAppID:       appID,
+			rbacframe.LabelEnvironment: query.Get("env"),
+			rbacframe.LabelServiceName: serviceName,
+		}
+		if !scope.Match(labels) {
+			return errors.New(errorsEx.MsgNoPerm)
+		}
+	}
+	ctx := domainProjectContext(req)
+	for _, key := range []string{":serviceId", ":providerId", ":consumerId", "serviceId"} {
+		serviceID := query.Get(key)
+		if len(serviceID) == 0 {
+			continue
+		}
+		resp, err := datasource.Instance().GetService(ctx, &pb.GetServiceRequest{ServiceId: serviceID})
+		if err != nil {
+			log.Error("get service failed", err)
+			return errors.New(errorsEx.MsgRolePerm)
+		}
+		if resp.Service == nil {
+			// let the api return the not exist error
+			continue
+		}
+		if !scope.MatchService(resp.Service) {
+			return errors.New(errorsEx.MsgNoPerm)
+		}
+	}
 	return nil
 }
+
+func domainProjectContext(req *http.Request) context.Context {
+	domain := req.Header.Get("X-Domain-Name")
+	if len(domain) == 0 {
+		domain = core.RegistryDomain
+	}
+	project := req.URL.Query().Get(":project")
+	if len(project) == 0 {
+		project = core.RegistryProject
+	}
+	return util.SetDomainProject(req.Context(), domain, project)
+}
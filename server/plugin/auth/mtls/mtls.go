@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtls
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/config"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth/buildin"
+	"github.com/apache/servicecomb-service-center/server/service/rbac"
+	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
+)
+
+var ErrNoCertIdentity = errors.New("client certificate matches no identity")
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{Kind: auth.AUTH, Name: "mtls", New: New})
+}
+
+func New() mgr.Instance {
+	c := config.GetMTLS()
+	if c == nil {
+		c = &config.MTLS{}
+	}
+	return &CertAuthenticator{Config: c}
+}
+
+//CertAuthenticator identifies the requests by the client certificates,
+//and falls back to the bearer token if AllowToken is enabled
+type CertAuthenticator struct {
+	buildin.TokenAuthenticator
+	Config *config.MTLS
+}
+
+func (ca *CertAuthenticator) Identify(req *http.Request) error {
+	if !rbac.Enabled() {
+		return nil
+	}
+	pattern, ok := req.Context().Value(rest.CtxMatchPattern).(string)
+	if ok && !rbacframe.MustAuth(pattern) {
+		return nil
+	}
+	if cert := peerCertificate(req); cert != nil {
+		if rule := MatchRule(ca.Config.Rules, cert); rule != nil {
+			claims, err := ca.claims(req.Context(), cert, rule)
+			if err != nil {
+				log.Errorf(err, "identify client certificate [%s] failed", Identity(cert))
+				return err
+			}
+			return buildin.Authorize(req, claims)
+		}
+		log.Warnf("client certificate [%s] matches no rule, %s %s", Identity(cert), req.Method, req.RequestURI)
+	}
+	if !ca.Config.AllowToken {
+		return ErrNoCertIdentity
+	}
+	return ca.TokenAuthenticator.Identify(req)
+}
+
+//claims returns the claims of the account the rule maps to,
+//or the claims of the certificate identity with the rule roles
+func (ca *CertAuthenticator) claims(ctx context.Context, cert *x509.Certificate, rule *config.CertRule) (map[string]interface{}, error) {
+	name, roles := Identity(cert), rule.Roles
+	if rule.Account != "" {
+		account, err := dao.GetAccount(ctx, rule.Account)
+		if err != nil {
+			return nil, err
+		}
+		name, roles = account.Name, account.Roles
+	}
+	list := make([]interface{}, 0, len(roles))
+	for _, r := range roles {
+		list = append(list, r)
+	}
+	return map[string]interface{}{
+		rbacframe.ClaimsUser:  name,
+		rbacframe.ClaimsRoles: list,
+	}, nil
+}
+
+func peerCertificate(req *http.Request) *x509.Certificate {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return req.TLS.PeerCertificates[0]
+}
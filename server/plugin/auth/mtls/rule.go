@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtls
+
+import (
+	"crypto/x509"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+//MatchRule returns the first rule matching the certificate, nil if no one matches
+func MatchRule(rules []config.CertRule, cert *x509.Certificate) *config.CertRule {
+	for i := range rules {
+		if matchCert(&rules[i], cert) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+//Identity returns the first SPIFFE URI of the certificate, or the subject common name
+func Identity(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
+
+//matchCert requires all the non-empty fields of the rule to match,
+//a rule without any field never matches
+func matchCert(rule *config.CertRule, cert *x509.Certificate) bool {
+	if rule.URI == "" && rule.DNSName == "" && rule.CommonName == "" {
+		return false
+	}
+	if rule.URI != "" {
+		uris := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+		}
+		if !matchAny(rule.URI, uris) {
+			return false
+		}
+	}
+	if rule.DNSName != "" && !matchAny(rule.DNSName, cert.DNSNames) {
+		return false
+	}
+	if rule.CommonName != "" && rule.CommonName != cert.Subject.CommonName {
+		return false
+	}
+	return true
+}
+
+func matchAny(pattern string, values []string) bool {
+	for _, v := range values {
+		if matchPattern(pattern, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPattern(pattern, value string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == value
+}
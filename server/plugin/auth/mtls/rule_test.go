@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtls_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth/mtls"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCert(cn, uri string, dnsNames ...string) *x509.Certificate {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}, DNSNames: dnsNames}
+	if uri != "" {
+		u, _ := url.Parse(uri)
+		cert.URIs = []*url.URL{u}
+	}
+	return cert
+}
+
+func TestMatchRule(t *testing.T) {
+	rules := []config.CertRule{
+		{},
+		{URI: "spiffe://cluster.local/ns/default/sa/*", Roles: []string{"developer"}},
+		{DNSName: "*", CommonName: "admin", Account: "root"},
+		{DNSName: "sc.", CommonName: "sc"},
+	}
+	t.Run("spiffe uri wildcard should match", func(t *testing.T) {
+		cert := newCert("", "spiffe://cluster.local/ns/default/sa/order")
+		rule := mtls.MatchRule(rules, cert)
+		assert.Equal(t, &rules[1], rule)
+		assert.Equal(t, "spiffe://cluster.local/ns/default/sa/order", mtls.Identity(cert))
+	})
+	t.Run("all the fields should match", func(t *testing.T) {
+		assert.Equal(t, &rules[2], mtls.MatchRule(rules, newCert("admin", "", "a.svc")))
+		assert.Nil(t, mtls.MatchRule(rules, newCert("admin", "")))
+		assert.Nil(t, mtls.MatchRule(rules, newCert("sc", "", "sc.svc")))
+		assert.Equal(t, &rules[3], mtls.MatchRule(rules, newCert("sc", "", "sc.")))
+	})
+	t.Run("empty rule should not match", func(t *testing.T) {
+		cert := newCert("order", "")
+		assert.Nil(t, mtls.MatchRule(rules, cert))
+		assert.Equal(t, "order", mtls.Identity(cert))
+	})
+}
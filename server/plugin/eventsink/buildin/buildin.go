@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink"
+)
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{Kind: eventsink.EVENTSINK, Name: "buildin", New: New})
+}
+
+func New() mgr.Instance {
+	return &Sink{}
+}
+
+// Sink discards all the records, it means exporting is disabled
+type Sink struct {
+}
+
+func (s *Sink) Send(ctx context.Context, records []*eventsink.Record) error {
+	return nil
+}
+
+func (s *Sink) Close() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	"github.com/apache/servicecomb-service-center/server/plugin"
+)
+
+const EVENTSINK plugin.Kind = "eventsink"
+
+var exporter *Exporter
+
+// Record is the message sent to sink, Value is the json encoded Event
+type Record struct {
+	// Key is used to keep the order of events of the same service
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Event is the exported service, instance, schema, rule or alarm event
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Action     string      `json:"action"`
+	Domain     string      `json:"domain,omitempty"`
+	Project    string      `json:"project,omitempty"`
+	ServiceID  string      `json:"serviceId,omitempty"`
+	ResourceID string      `json:"resourceId,omitempty"`
+	Timestamp  int64       `json:"timestamp"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// Sink is the destination of events, e.g. message bus or file
+type Sink interface {
+	// Send returns nil only if all the records are persisted by sink,
+	// otherwise the records will be spooled and sent again later
+	Send(ctx context.Context, records []*Record) error
+	Close() error
+}
+
+// Enabled returns true if an event sink other than buildin is configured
+func Enabled() bool {
+	return config.GetString(EVENTSINK.String()+".kind", plugin.Buildin) != plugin.Buildin
+}
+
+func Instance() Sink {
+	return plugin.Plugins().Instance(EVENTSINK).(Sink)
+}
+
+// Init subscribes the events and starts exporting them to sink
+func Init() {
+	if !Enabled() || exporter != nil {
+		return
+	}
+	e, err := NewExporter(Instance(), Options{
+		SpoolDir:      config.GetString("eventsink.spoolDir", "./data/eventsink"),
+		MaxSpoolFiles: config.GetInt("eventsink.maxSpoolFiles", DefaultMaxSpoolFiles),
+		BatchSize:     config.GetInt("eventsink.batchSize", DefaultBatchSize),
+		Interval:      config.GetDuration("eventsink.interval", DefaultInterval),
+		Timeout:       config.GetDuration("eventsink.timeout", DefaultTimeout),
+		QueueSize:     config.GetInt("eventsink.queueSize", DefaultQueueSize),
+	})
+	if err != nil {
+		log.Error("init event sink exporter failed", err)
+		return
+	}
+	if err := notify.Center().AddSubscriber(NewResourceSubscriber(e)); err != nil {
+		log.Error("subscribe resource events failed", err)
+		return
+	}
+	if err := notify.Center().AddSubscriber(NewAlarmSubscriber(e)); err != nil {
+		log.Error("subscribe alarm events failed", err)
+		return
+	}
+	e.Start()
+	exporter = e
+	log.Info("event sink exporter started")
+}
+
+// Stop spools the pending events and closes the sink
+func Stop() {
+	if exporter != nil {
+		exporter.Stop()
+	}
+}
@@ -0,0 +1,287 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventsink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	nf "github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+const (
+	Group = "__EVENTSINK_GROUP__"
+
+	TypeAlarm = "alarm"
+
+	DefaultBatchSize     = 100
+	DefaultInterval      = time.Second
+	DefaultTimeout       = 10 * time.Second
+	DefaultQueueSize     = 10000
+	DefaultMaxSpoolFiles = 10000
+)
+
+type Options struct {
+	SpoolDir      string
+	MaxSpoolFiles int
+	// BatchSize is the max number of records sent to sink at once
+	BatchSize int
+	// Interval is the max time of records waiting for sending,
+	// and the interval of resending the spooled records
+	Interval  time.Duration
+	Timeout   time.Duration
+	QueueSize int
+}
+
+// Exporter sends the events to sink with at-least-once semantics,
+// the records are spooled in local directory if sink is unavailable,
+// and are resent in order after sink recovered
+type Exporter struct {
+	opts      Options
+	sink      Sink
+	spool     *Spool
+	queue     chan *Record
+	goroutine *gopool.Pool
+}
+
+// Export puts the event in queue, it never blocks the caller
+func (e *Exporter) Export(evt *Event) {
+	value, err := json.Marshal(evt)
+	if err != nil {
+		log.Errorf(err, "marshal %s event[%s] failed", evt.Type, evt.ID)
+		return
+	}
+	key := evt.ServiceID
+	if len(key) == 0 {
+		key = evt.ResourceID
+	}
+	r := &Record{Key: key, Value: value}
+	select {
+	case e.queue <- r:
+	default:
+		// queue is full, do not lose it
+		if err := e.spool.Append([]*Record{r}); err != nil {
+			log.Errorf(err, "spool %s event[%s] failed", evt.Type, evt.ID)
+		}
+	}
+}
+
+func (e *Exporter) send(records []*Record) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.opts.Timeout)
+	defer cancel()
+	return e.sink.Send(ctx, records)
+}
+
+// Flush sends the batch, or spools it if sink is unavailable
+// or there are records spooled before it
+func (e *Exporter) Flush(batch []*Record) {
+	if len(batch) == 0 {
+		return
+	}
+	if e.spool.Empty() {
+		err := e.send(batch)
+		if err == nil {
+			return
+		}
+		log.Errorf(err, "send %d record(s) to event sink failed, spool them", len(batch))
+	}
+	if err := e.spool.Append(batch); err != nil {
+		log.Errorf(err, "spool %d record(s) failed", len(batch))
+	}
+}
+
+// Drain resends the spooled records from the oldest,
+// it stops at the first failure to keep the order
+func (e *Exporter) Drain() error {
+	files, err := e.spool.Files()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		records, err := e.spool.Read(file)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			if err := e.send(records); err != nil {
+				return err
+			}
+		}
+		if err := e.spool.Remove(file); err != nil {
+			return err
+		}
+		log.Infof("resend %d spooled record(s) in %s", len(records), file)
+	}
+	return nil
+}
+
+func (e *Exporter) loop(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	batch := make([]*Record, 0, e.opts.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// spool the pending records, they will be sent after restarted
+			for len(e.queue) > 0 {
+				batch = append(batch, <-e.queue)
+			}
+			if err := e.spool.Append(batch); err != nil {
+				log.Errorf(err, "spool %d record(s) failed", len(batch))
+			}
+			return
+		case r := <-e.queue:
+			batch = append(batch, r)
+			if len(batch) < e.opts.BatchSize {
+				continue
+			}
+			e.Flush(batch)
+			batch = make([]*Record, 0, e.opts.BatchSize)
+		case <-ticker.C:
+			if err := e.Drain(); err != nil {
+				log.Errorf(err, "resend spooled records failed")
+			}
+			e.Flush(batch)
+			batch = make([]*Record, 0, e.opts.BatchSize)
+		}
+	}
+}
+
+func (e *Exporter) Start() {
+	e.goroutine.Do(e.loop)
+}
+
+func (e *Exporter) Stop() {
+	e.goroutine.Close(true)
+	if err := e.sink.Close(); err != nil {
+		log.Error("close event sink failed", err)
+	}
+}
+
+// ResourceSubscriber exports the service, instance, schema and rule events
+type ResourceSubscriber struct {
+	nf.Subscriber
+	exporter *Exporter
+}
+
+func (s *ResourceSubscriber) OnMessage(evt nf.Event) {
+	re, ok := evt.(*notify.ResourceEvent)
+	if !ok {
+		return
+	}
+	s.exporter.Export(NewResourceEvent(re))
+}
+
+// NewResourceEvent converts the resource event, every replica receives and
+// exports the same resource event, so the id is derived from the event content
+// rather than generated randomly, consumers use it to drop the duplicates
+func NewResourceEvent(re *notify.ResourceEvent) *Event {
+	domain, project := util.FromDomainProject(re.DomainProject)
+	e := &Event{
+		Type:       re.Resource,
+		Action:     re.Action,
+		Domain:     domain,
+		Project:    project,
+		ResourceID: re.ResourceID,
+		Timestamp:  re.CreateAt().Unix(),
+		Data:       re.Value,
+	}
+	if re.Service != nil {
+		e.ServiceID = re.Service.ServiceId
+	}
+	h := sha256.New()
+	_, _ = h.Write([]byte(util.StringJoin([]string{re.Resource, re.Action, re.DomainProject, re.ResourceID}, "/")))
+	if data, err := json.Marshal(re.Value); err == nil {
+		_, _ = h.Write(data)
+	}
+	e.ID = hex.EncodeToString(h.Sum(nil))
+	return e
+}
+
+// AlarmSubscriber exports the alarm events
+type AlarmSubscriber struct {
+	nf.Subscriber
+	exporter *Exporter
+}
+
+func (s *AlarmSubscriber) OnMessage(evt nf.Event) {
+	ae, ok := evt.(*model.AlarmEvent)
+	if !ok {
+		return
+	}
+	s.exporter.Export(&Event{
+		ID:         util.GenerateUUID(),
+		Type:       TypeAlarm,
+		Action:     string(ae.Status),
+		ResourceID: string(ae.ID),
+		Timestamp:  ae.CreateAt().Unix(),
+		Data:       ae.Fields,
+	})
+}
+
+func NewResourceSubscriber(e *Exporter) *ResourceSubscriber {
+	return &ResourceSubscriber{
+		Subscriber: nf.NewSubscriber(notify.RESOURCE, notify.ResourceSubject, Group),
+		exporter:   e,
+	}
+}
+
+func NewAlarmSubscriber(e *Exporter) *AlarmSubscriber {
+	return &AlarmSubscriber{
+		Subscriber: nf.NewSubscriber(alarm.ALARM, alarm.Subject, Group),
+		exporter:   e,
+	}
+}
+
+func NewExporter(sink Sink, opts Options) (*Exporter, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.MaxSpoolFiles <= 0 {
+		opts.MaxSpoolFiles = DefaultMaxSpoolFiles
+	}
+	spool, err := NewSpool(opts.SpoolDir, opts.MaxSpoolFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		opts:      opts,
+		sink:      sink,
+		spool:     spool,
+		queue:     make(chan *Record, opts.QueueSize),
+		goroutine: gopool.New(context.Background()),
+	}, nil
+}
@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventsink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/server/notify"
+	. "github.com/apache/servicecomb-service-center/server/plugin/eventsink"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSink struct {
+	lock    sync.Mutex
+	err     error
+	records []*Record
+}
+
+func (s *mockSink) Send(_ context.Context, records []*Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *mockSink) Close() error {
+	return nil
+}
+
+func (s *mockSink) ids(t *testing.T) (ids []string) {
+	for _, r := range s.records {
+		evt := &Event{}
+		assert.NoError(t, json.Unmarshal(r.Value, evt))
+		ids = append(ids, evt.ID)
+	}
+	return
+}
+
+func record(id string) []*Record {
+	b, _ := json.Marshal(&Event{ID: id})
+	return []*Record{{Key: id, Value: b}}
+}
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventsink")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sink := &mockSink{}
+	e, err := NewExporter(sink, Options{SpoolDir: dir})
+	assert.NoError(t, err)
+
+	t.Run("sink is available, should send directly", func(t *testing.T) {
+		e.Flush(record("1"))
+		assert.Equal(t, []string{"1"}, sink.ids(t))
+	})
+
+	t.Run("sink is unavailable, should spool", func(t *testing.T) {
+		sink.err = errors.New("unavailable")
+		e.Flush(record("2"))
+		assert.Error(t, e.Drain())
+		files, err := ioutil.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(files))
+	})
+
+	t.Run("sink recovered, should keep the order", func(t *testing.T) {
+		sink.err = nil
+		// spooled records exist, new one should be spooled too
+		e.Flush(record("3"))
+		assert.Equal(t, []string{"1"}, sink.ids(t))
+
+		assert.NoError(t, e.Drain())
+		assert.Equal(t, []string{"1", "2", "3"}, sink.ids(t))
+		files, err := ioutil.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(files))
+	})
+}
+
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	spool, err := NewSpool(dir, 2)
+	assert.NoError(t, err)
+	assert.True(t, spool.Empty())
+	assert.NoError(t, spool.Append(record("1")))
+	assert.NoError(t, spool.Append(record("2")))
+	assert.NoError(t, spool.Append(record("3")))
+
+	// the oldest one is discarded
+	files, err := spool.Files()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(files))
+	records, err := spool.Read(files[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "2", records[0].Key)
+
+	assert.NoError(t, spool.Remove(files[0]))
+	assert.NoError(t, spool.Remove(files[1]))
+	assert.True(t, spool.Empty())
+}
+
+func TestNewResourceEvent(t *testing.T) {
+	value := map[string]string{"status": "UP"}
+	a := NewResourceEvent(notify.NewResourceEvent(notify.ResourceInstance, "UPDATE", "default/default", "1", nil, value))
+	b := NewResourceEvent(notify.NewResourceEvent(notify.ResourceInstance, "UPDATE", "default/default", "1", nil, value))
+	assert.Equal(t, a.ID, b.ID, "the same event received by replicas should have the same id")
+
+	c := NewResourceEvent(notify.NewResourceEvent(notify.ResourceInstance, "DELETE", "default/default", "1", nil, value))
+	assert.NotEqual(t, a.ID, c.ID)
+
+	d := NewResourceEvent(notify.NewResourceEvent(notify.ResourceInstance, "UPDATE", "default/default", "1", nil,
+		map[string]string{"status": "DOWN"}))
+	assert.NotEqual(t, a.ID, d.ID)
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"sync"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink"
+)
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{Kind: eventsink.EVENTSINK, Name: "file", New: New})
+}
+
+func New() mgr.Instance {
+	return NewSink(config.GetString("eventsink.file.path", "./data/events.log"))
+}
+
+// Sink appends the events to a line-delimited json file
+type Sink struct {
+	Path string
+	lock sync.Mutex
+}
+
+func (s *Sink) Send(ctx context.Context, records []*eventsink.Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	fd, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	w := bufio.NewWriter(fd)
+	for _, r := range records {
+		if _, err := w.Write(r.Value); err != nil {
+			return err
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	// records are persisted only after synced
+	return fd.Sync()
+}
+
+func (s *Sink) Close() error {
+	return nil
+}
+
+func NewSink(path string) *Sink {
+	log.Infof("export events to file %s", path)
+	return &Sink{Path: path}
+}
@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSink_Send(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventsink")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sink := file.NewSink(filepath.Join(dir, "events.log"))
+	err = sink.Send(context.Background(), []*eventsink.Record{{Value: []byte(`{"id":"1"}`)}})
+	assert.NoError(t, err)
+	err = sink.Send(context.Background(), []*eventsink.Record{{Value: []byte(`{"id":"2"}`)}})
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadFile(sink.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"1\"}\n{\"id\":\"2\"}\n", string(b))
+	assert.NoError(t, sink.Close())
+}
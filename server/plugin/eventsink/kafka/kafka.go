@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafka
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	mgr "github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink"
+)
+
+const (
+	DefaultTopic    = "servicecomb-service-center-events"
+	DefaultClientID = "servicecomb-service-center"
+)
+
+func init() {
+	mgr.RegisterPlugin(mgr.Plugin{Kind: eventsink.EVENTSINK, Name: "kafka", New: New})
+}
+
+func New() mgr.Instance {
+	brokers := strings.Split(config.GetString("eventsink.kafka.brokers", "127.0.0.1:9092"), ",")
+	return NewSink(brokers, config.GetString("eventsink.kafka.topic", DefaultTopic), nil)
+}
+
+// Sink produces the events to kafka topic,
+// it also works with the kafka compatible brokers
+type Sink struct {
+	Brokers []string
+	Topic   string
+	Config  *sarama.Config
+
+	lock     sync.Mutex
+	producer sarama.SyncProducer
+}
+
+func (s *Sink) resetProducer() {
+	if s.producer == nil {
+		return
+	}
+	closeProducer(s.producer)
+	s.producer = nil
+}
+
+func closeProducer(producer sarama.SyncProducer) {
+	if err := producer.Close(); err != nil {
+		log.Error("close kafka producer failed", err)
+	}
+}
+
+type sendResult struct {
+	producer sarama.SyncProducer
+	err      error
+}
+
+// Send produces the records in another goroutine and returns ctx.Err() once ctx is done,
+// so a stalled broker does not block the shutdown, the producer in use is closed after it returns
+func (s *Sink) Send(ctx context.Context, records []*eventsink.Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	msgs := make([]*sarama.ProducerMessage, 0, len(records))
+	for _, r := range records {
+		msg := &sarama.ProducerMessage{
+			Topic: s.Topic,
+			Value: sarama.ByteEncoder(r.Value),
+		}
+		if len(r.Key) > 0 {
+			msg.Key = sarama.StringEncoder(r.Key)
+		}
+		msgs = append(msgs, msg)
+	}
+	done := make(chan sendResult, 1)
+	go func(producer sarama.SyncProducer) {
+		// the producer is created lazily, so that the server can start
+		// before kafka is available, records are spooled at that time
+		if producer == nil {
+			var err error
+			producer, err = sarama.NewSyncProducer(s.Brokers, s.Config)
+			if err != nil {
+				done <- sendResult{err: err}
+				return
+			}
+		}
+		done <- sendResult{producer: producer, err: producer.SendMessages(msgs)}
+	}(s.producer)
+
+	select {
+	case r := <-done:
+		s.producer = r.producer
+		if r.err != nil {
+			// reconnect next time
+			s.resetProducer()
+			return r.err
+		}
+		return nil
+	case <-ctx.Done():
+		// reconnect next time
+		s.producer = nil
+		go func() {
+			if r := <-done; r.producer != nil {
+				closeProducer(r.producer)
+			}
+		}()
+		return ctx.Err()
+	}
+}
+
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.resetProducer()
+	return nil
+}
+
+// NewSink returns the kafka sink, cfg is nil means using the default config,
+// which waits for all in-sync replicas to commit the messages
+func NewSink(brokers []string, topic string, cfg *sarama.Config) *Sink {
+	if cfg == nil {
+		cfg = sarama.NewConfig()
+		cfg.ClientID = DefaultClientID
+		cfg.Producer.RequiredAcks = sarama.WaitForAll
+		cfg.Producer.Retry.Max = 3
+	}
+	// required by sync producer
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.Return.Errors = true
+	return &Sink{Brokers: brokers, Topic: topic, Config: cfg}
+}
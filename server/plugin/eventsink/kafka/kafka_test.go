@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafka_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+const topic = "events"
+
+func newBroker(t *testing.T) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		// the produce request version of kafka 0.11+ is 3
+		"ProduceRequest": sarama.NewMockProduceResponse(t).SetVersion(3),
+	})
+	return broker
+}
+
+func TestSink_Send(t *testing.T) {
+	broker := newBroker(t)
+
+	cfg := sarama.NewConfig()
+	cfg.Version = sarama.V1_0_0_0
+	cfg.Producer.Retry.Max = 0
+	cfg.Metadata.Retry.Max = 0
+	sink := kafka.NewSink([]string{broker.Addr()}, topic, cfg)
+	records := []*eventsink.Record{
+		{Key: "svc1", Value: []byte(`{"id":"1"}`)},
+		{Value: []byte(`{"id":"2"}`)},
+	}
+
+	t.Run("broker is available, should be produced", func(t *testing.T) {
+		err := sink.Send(context.Background(), records)
+		assert.NoError(t, err)
+
+		var produced int
+		for _, rr := range broker.History() {
+			if req, ok := rr.Request.(*sarama.ProduceRequest); ok {
+				produced++
+				assert.NotNil(t, req)
+			}
+		}
+		assert.True(t, produced > 0)
+	})
+
+	t.Run("broker is unavailable, should return error", func(t *testing.T) {
+		broker.Close()
+		err := sink.Send(context.Background(), records)
+		assert.Error(t, err)
+	})
+
+	t.Run("broker recovered, should reconnect", func(t *testing.T) {
+		broker = newBroker(t)
+		defer broker.Close()
+		sink.Brokers = []string{broker.Addr()}
+		err := sink.Send(context.Background(), records)
+		assert.NoError(t, err)
+	})
+
+	t.Run("broker is stalled, should return once ctx is done", func(t *testing.T) {
+		broker = newBroker(t)
+		defer broker.Close()
+		broker.SetLatency(time.Second)
+		sink := kafka.NewSink([]string{broker.Addr()}, topic, cfg)
+		defer sink.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := sink.Send(ctx, records)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.True(t, time.Since(start) < time.Second)
+	})
+
+	assert.NoError(t, sink.Close())
+}
@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventsink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+const spoolFileSuffix = ".jsonl"
+
+// Spool saves the records failed to send in local directory,
+// every batch is a line-delimited json file named by the spooled time
+type Spool struct {
+	Dir string
+	// MaxFiles is the max number of spooled batches,
+	// the oldest ones are discarded if exceeded
+	MaxFiles int
+
+	lock sync.Mutex
+	seq  int64
+}
+
+// Append saves the batch as a new file
+func (s *Spool) Append(records []*Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var b strings.Builder
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+
+	// ensure the names are unique and sortable
+	seq := time.Now().UnixNano()
+	if seq <= s.seq {
+		seq = s.seq + 1
+	}
+	s.seq = seq
+	name := filepath.Join(s.Dir, fmt.Sprintf("%020d%s", seq, spoolFileSuffix))
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return err
+	}
+	s.discard()
+	return nil
+}
+
+func (s *Spool) discard() {
+	if s.MaxFiles <= 0 {
+		return
+	}
+	files, err := s.files()
+	if err != nil {
+		log.Error("list spool files failed", err)
+		return
+	}
+	for i := 0; i < len(files)-s.MaxFiles; i++ {
+		log.Errorf(nil, "event sink spool is full, discard %s", files[i])
+		_ = os.Remove(files[i])
+	}
+}
+
+func (s *Spool) files() ([]string, error) {
+	infos, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), spoolFileSuffix) {
+			continue
+		}
+		files = append(files, filepath.Join(s.Dir, info.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Files returns the spooled files from the oldest to the newest
+func (s *Spool) Files() ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.files()
+}
+
+// Empty returns true if there is no spooled file
+func (s *Spool) Empty() bool {
+	files, err := s.Files()
+	return err == nil && len(files) == 0
+}
+
+// Read returns the records in the spooled file
+func (s *Spool) Read(file string) ([]*Record, error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		r := &Record{}
+		if err := json.Unmarshal(line, r); err != nil {
+			log.Errorf(err, "invalid record in spool file %s", file)
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Remove deletes the spooled file after it is sent
+func (s *Spool) Remove(file string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return os.Remove(file)
+}
+
+func NewSpool(dir string, maxFiles int) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	return &Spool{Dir: dir, MaxFiles: maxFiles}, nil
+}
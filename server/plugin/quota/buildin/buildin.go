@@ -19,41 +19,87 @@ package buildin
 
 import (
 	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	pkgquota "github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
 	mgr "github.com/apache/servicecomb-service-center/server/plugin"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
 )
 
+const defaultRefreshInterval = 30 * time.Second
+
 func init() {
 	mgr.RegisterPlugin(mgr.Plugin{Kind: quota.QUOTA, Name: "buildin", New: New})
 }
 
 func New() mgr.Instance {
 	quota.Init()
-	log.Infof("quota init, service: %d, instance: %d, schema: %d/service, tag: %d/service, rule: %d/service",
-		quota.DefaultServiceQuota, quota.DefaultInstanceQuota,
-		quota.DefaultSchemaQuota, quota.DefaultTagQuota, quota.DefaultRuleQuota)
-	return &Quota{}
+	log.Infof("quota init, service: %d, instance: %d, instance: %d/service, schema: %d/service, tag: %d/service, "+
+		"rule: %d/service, dependency: %d/service, account: %d, role: %d",
+		quota.DefaultServiceQuota, quota.DefaultInstanceQuota, quota.DefaultInstancePerServiceQuota,
+		quota.DefaultSchemaQuota, quota.DefaultTagQuota, quota.DefaultRuleQuota,
+		quota.DefaultDependencyQuota, quota.DefaultAccountQuota, quota.DefaultRoleQuota)
+	return &Quota{
+		interval: config.GetDuration("quota.refreshInterval", defaultRefreshInterval),
+	}
 }
 
+//Quota returns the limits overridden in datasource, or the default limits,
+//the overrides are cached and reloaded every interval
 type Quota struct {
+	interval time.Duration
+
+	lock      sync.RWMutex
+	overrides map[string]*pkgquota.Limits
+	loadTime  time.Time
 }
 
 func (q *Quota) GetQuota(ctx context.Context, t quota.ResourceType) int64 {
-	switch t {
-	case quota.TypeInstance:
-		return int64(quota.DefaultInstanceQuota)
-	case quota.TypeService:
-		return int64(quota.DefaultServiceQuota)
-	case quota.TypeRule:
-		return int64(quota.DefaultRuleQuota)
-	case quota.TypeSchema:
-		return int64(quota.DefaultSchemaQuota)
-	case quota.TypeTag:
-		return int64(quota.DefaultTagQuota)
-	default:
-		return 0
+	if t.Global() {
+		return quota.DefaultQuota(t)
+	}
+	if limit, ok := q.override(ctx).Get(t.Name()); ok {
+		return limit
+	}
+	return quota.DefaultQuota(t)
+}
+
+func (q *Quota) override(ctx context.Context) *pkgquota.Limits {
+	q.lock.RLock()
+	stale := time.Since(q.loadTime) > q.interval
+	q.lock.RUnlock()
+	if stale {
+		q.Refresh(ctx)
+	}
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.overrides[util.ParseDomainProject(ctx)]
+}
+
+//Refresh reloads the overrides from datasource,
+//the cached overrides are kept until next interval if it fails
+func (q *Quota) Refresh(ctx context.Context) {
+	list, err := datasource.Instance().ListQuotaLimits(ctx)
+	if err != nil {
+		log.Error("reload quota limits failed", err)
+		q.lock.Lock()
+		q.loadTime = time.Now()
+		q.lock.Unlock()
+		return
+	}
+	overrides := make(map[string]*pkgquota.Limits, len(list))
+	for _, l := range list {
+		overrides[util.StringJoin([]string{l.Domain, l.Project}, "/")] = l
 	}
+	q.lock.Lock()
+	q.overrides = overrides
+	q.loadTime = time.Now()
+	q.lock.Unlock()
 }
 
 //向配额中心上报配额使用量
@@ -23,9 +23,10 @@ import (
 	"fmt"
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	pkgquota "github.com/apache/servicecomb-service-center/pkg/quota"
 	"github.com/apache/servicecomb-service-center/pkg/util"
-	"github.com/apache/servicecomb-service-center/server/metrics"
 	"strconv"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/server/config"
 	"github.com/apache/servicecomb-service-center/server/plugin"
@@ -40,6 +41,11 @@ const (
 	defaultSchemaLimit   = 100
 	defaultRuleLimit     = 100
 	defaultTagLimit      = 100
+
+	defaultInstancePerServiceLimit = defaultInstanceLimit
+	defaultDependencyLimit         = 1000
+	defaultAccountLimit            = 10000
+	defaultRoleLimit               = 1000
 )
 
 const (
@@ -48,14 +54,27 @@ const (
 	TypeTag
 	TypeService
 	TypeInstance
+	TypeInstancePerService
+	TypeDependency
+	TypeAccount
+	TypeRole
 )
 
+// Types are all the resource types limited by quota
+var Types = []ResourceType{TypeService, TypeInstance, TypeInstancePerService,
+	TypeSchema, TypeTag, TypeRule, TypeDependency, TypeAccount, TypeRole}
+
 var (
 	DefaultServiceQuota  = defaultServiceLimit
 	DefaultInstanceQuota = defaultInstanceLimit
 	DefaultSchemaQuota   = defaultSchemaLimit
 	DefaultTagQuota      = defaultTagLimit
 	DefaultRuleQuota     = defaultRuleLimit
+
+	DefaultInstancePerServiceQuota = defaultInstancePerServiceLimit
+	DefaultDependencyQuota         = defaultDependencyLimit
+	DefaultAccountQuota            = defaultAccountLimit
+	DefaultRoleQuota               = defaultRoleLimit
 )
 
 func Init() {
@@ -64,6 +83,10 @@ func Init() {
 	DefaultSchemaQuota = config.GetInt("quota.cap.schema.limit", defaultSchemaLimit, config.WithStandby("QUOTA_SCHEMA"))
 	DefaultTagQuota = config.GetInt("quota.cap.tag.limit", defaultTagLimit, config.WithStandby("QUOTA_TAG"))
 	DefaultRuleQuota = config.GetInt("quota.cap.rule.limit", defaultRuleLimit, config.WithStandby("QUOTA_RULE"))
+	DefaultInstancePerServiceQuota = config.GetInt("quota.cap.instance_per_service.limit", defaultInstancePerServiceLimit)
+	DefaultDependencyQuota = config.GetInt("quota.cap.dependency.limit", defaultDependencyLimit)
+	DefaultAccountQuota = config.GetInt("quota.cap.account.limit", defaultAccountLimit)
+	DefaultRoleQuota = config.GetInt("quota.cap.role.limit", defaultRoleLimit)
 }
 
 type ApplyQuotaResource struct {
@@ -71,14 +94,17 @@ type ApplyQuotaResource struct {
 	DomainProject string
 	ServiceID     string
 	QuotaSize     int64
+	// Overwrite means the resources are replaced as a whole, e.g. the tags of service,
+	// then QuotaSize is checked without the current usage
+	Overwrite bool
 }
 
 func NewApplyQuotaResource(quotaType ResourceType, domainProject, serviceID string, quotaSize int64) *ApplyQuotaResource {
 	return &ApplyQuotaResource{
-		quotaType,
-		domainProject,
-		serviceID,
-		quotaSize,
+		QuotaType:     quotaType,
+		DomainProject: domainProject,
+		ServiceID:     serviceID,
+		QuotaSize:     quotaSize,
 	}
 }
 
@@ -87,6 +113,11 @@ type Manager interface {
 	GetQuota(ctx context.Context, t ResourceType) int64
 }
 
+// Refresher is implemented by the Manager caching the quota limits overrides
+type Refresher interface {
+	Refresh(ctx context.Context)
+}
+
 type ResourceType int
 
 func (r ResourceType) String() string {
@@ -101,24 +132,130 @@ func (r ResourceType) String() string {
 		return "SERVICE"
 	case TypeInstance:
 		return "INSTANCE"
+	case TypeInstancePerService:
+		return "INSTANCE_PER_SERVICE"
+	case TypeDependency:
+		return "DEPENDENCY"
+	case TypeAccount:
+		return "ACCOUNT"
+	case TypeRole:
+		return "ROLE"
 	default:
 		return "RESOURCE" + strconv.Itoa(int(r))
 	}
 }
 
+// Name is the lower case type name, used as the key of quota limits overrides
+func (r ResourceType) Name() string {
+	return strings.ToLower(r.String())
+}
+
+// PerService returns true if the limit applies to each service
+func (r ResourceType) PerService() bool {
+	return r == TypeSchema || r == TypeTag || r == TypeRule ||
+		r == TypeInstancePerService || r == TypeDependency
+}
+
+// Global returns true if the resource is counted over the cluster,
+// the limit can not be overridden by domain project
+func (r ResourceType) Global() bool {
+	return r == TypeAccount || r == TypeRole
+}
+
+// ParseResourceType returns the resource type of the name, false if unknown
+func ParseResourceType(name string) (ResourceType, bool) {
+	for _, t := range Types {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// DefaultQuota returns the configured limit of the resource type
+func DefaultQuota(t ResourceType) int64 {
+	switch t {
+	case TypeInstance:
+		return int64(DefaultInstanceQuota)
+	case TypeService:
+		return int64(DefaultServiceQuota)
+	case TypeRule:
+		return int64(DefaultRuleQuota)
+	case TypeSchema:
+		return int64(DefaultSchemaQuota)
+	case TypeTag:
+		return int64(DefaultTagQuota)
+	case TypeInstancePerService:
+		return int64(DefaultInstancePerServiceQuota)
+	case TypeDependency:
+		return int64(DefaultDependencyQuota)
+	case TypeAccount:
+		return int64(DefaultAccountQuota)
+	case TypeRole:
+		return int64(DefaultRoleQuota)
+	default:
+		return 0
+	}
+}
+
+// GetQuota returns the limit of the resource type in the domain project of ctx
+func GetQuota(ctx context.Context, t ResourceType) int64 {
+	return plugin.Plugins().Instance(QUOTA).(Manager).GetQuota(ctx, t)
+}
+
+// Refresh reloads the quota limits overrides if the Manager caches them
+func Refresh(ctx context.Context) {
+	if r, ok := plugin.Plugins().Instance(QUOTA).(Refresher); ok {
+		r.Refresh(ctx)
+	}
+}
+
+// Usage returns the limit and consumption of every resource type in the domain project of ctx,
+// the per service types are counted for the serviceID, or 0 if it is empty
+func Usage(ctx context.Context, serviceID string) ([]*pkgquota.Usage, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	usage := make([]*pkgquota.Usage, 0, len(Types))
+	for _, t := range Types {
+		u := &pkgquota.Usage{
+			Resource:   t.Name(),
+			Limit:      GetQuota(ctx, t),
+			PerService: t.PerService(),
+		}
+		if !t.PerService() || len(serviceID) > 0 {
+			used, err := GetResourceUsage(ctx, NewApplyQuotaResource(t, domainProject, serviceID, 0))
+			if err != nil {
+				return nil, err
+			}
+			u.Used = used
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
 //申请配额sourceType serviceinstance servicetype
 func Apply(ctx context.Context, res *ApplyQuotaResource) *pb.Error {
+	return apply(ctx, res, 0)
+}
+
+// apply checks the quota with the usage reserved by the others
+func apply(ctx context.Context, res *ApplyQuotaResource, reserved int64) *pb.Error {
 	if res == nil {
 		err := errors.New("invalid parameters")
 		log.Errorf(err, "quota check failed")
 		return pb.NewError(pb.ErrInternal, err.Error())
 	}
 
-	limitQuota := plugin.Plugins().Instance(QUOTA).(Manager).GetQuota(ctx, res.QuotaType)
-	curNum, err := GetResourceUsage(ctx, res)
-	if err != nil {
-		log.Errorf(err, "%s quota check failed", res.QuotaType)
-		return pb.NewError(pb.ErrInternal, err.Error())
+	limitQuota := GetQuota(ctx, res.QuotaType)
+	var curNum int64
+	if !res.Overwrite {
+		var err error
+		curNum, err = GetResourceUsage(ctx, res)
+		if err != nil {
+			log.Errorf(err, "%s quota check failed", res.QuotaType)
+			return pb.NewError(pb.ErrInternal, err.Error())
+		}
+		curNum += reserved
 	}
 	if curNum+res.QuotaSize > limitQuota {
 		mes := fmt.Sprintf("no quota to create %s, max num is %d, curNum is %d, apply num is %d",
@@ -132,39 +269,11 @@ func Apply(ctx context.Context, res *ApplyQuotaResource) *pb.Error {
 func Remand(ctx context.Context, quotaType ResourceType) {
 	plugin.Plugins().Instance(QUOTA).(Manager).RemandQuotas(ctx, quotaType)
 }
+
+// GetResourceUsage counts the resource in the domain project of ctx from datasource
 func GetResourceUsage(ctx context.Context, res *ApplyQuotaResource) (int64, error) {
-	serviceID := res.ServiceID
-	switch res.QuotaType {
-	case TypeService:
-		return metrics.GetTotalService(util.ParseDomain(ctx)), nil
-	case TypeInstance:
-		usage := metrics.GetTotalInstance(util.ParseDomain(ctx))
-		return usage, nil
-	case TypeRule:
-		{
-			resp, err := datasource.Instance().GetRules(ctx, &pb.GetServiceRulesRequest{
-				ServiceId: serviceID,
-			})
-			if err != nil {
-				return 0, err
-			}
-			return int64(len(resp.Rules)), nil
-		}
-	case TypeSchema:
-		{
-			resp, err := datasource.Instance().GetAllSchemas(ctx, &pb.GetAllSchemaRequest{
-				ServiceId:  serviceID,
-				WithSchema: false,
-			})
-			if err != nil {
-				return 0, err
-			}
-			return int64(len(resp.Schemas)), nil
-		}
-	case TypeTag:
-		// always re-create the service old tags
-		return 0, nil
-	default:
-		return 0, fmt.Errorf("not define quota type '%s'", res.QuotaType)
+	if res.QuotaType.PerService() && len(res.ServiceID) == 0 {
+		return 0, fmt.Errorf("serviceID is required to count %s", res.QuotaType)
 	}
+	return datasource.Instance().CountQuotaUsage(ctx, res.QuotaType.Name(), res.ServiceID)
 }
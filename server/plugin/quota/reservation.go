@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	pkgquota "github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const (
+	// reserveRetries is the max times of reserving when the reservations change concurrently
+	reserveRetries = 5
+	// reservationTTL limits the reservations leaked by the crashed replicas
+	reservationTTL = time.Minute
+)
+
+// ReservationKey returns the key the reservations of the resource are under,
+// the per service resources are reserved for each service
+func ReservationKey(res *ApplyQuotaResource) string {
+	keys := []string{res.QuotaType.Name()}
+	if !res.QuotaType.Global() {
+		keys = append(keys, res.DomainProject)
+	}
+	if res.QuotaType.PerService() {
+		keys = append(keys, res.ServiceID)
+	}
+	return util.StringJoin(keys, "/")
+}
+
+// Acquire applies the quota like Apply and reserves it until release is called,
+// the caller creates the resource before releasing, so the concurrent applies can not overshoot the limit.
+// The reservation is added only if no other one is added since listed, so nobody waits for a lock.
+// The release is never nil, it does nothing if the apply fails
+func Acquire(ctx context.Context, res *ApplyQuotaResource) (release func(), err *pb.Error) {
+	release = func() {}
+	if res == nil {
+		return release, Apply(ctx, res)
+	}
+
+	key := ReservationKey(res)
+	for i := 0; i < reserveRetries; i++ {
+		rs, rev, errList := datasource.Instance().ListQuotaReservation(ctx, key)
+		if errList != nil {
+			log.Errorf(errList, "list quota reservations of %s failed", key)
+			return release, pb.NewError(pb.ErrUnavailableBackend, errList.Error())
+		}
+		var reserved int64
+		for _, r := range rs {
+			reserved += r.Size
+		}
+		if err = apply(ctx, res, reserved); err != nil {
+			return release, err
+		}
+
+		r := &pkgquota.Reservation{
+			ID:         util.GenerateUUID(),
+			Size:       res.QuotaSize,
+			ExpireTime: time.Now().Add(reservationTTL).Unix(),
+		}
+		errPut := datasource.Instance().PutQuotaReservation(ctx, key, r, rev)
+		if errPut == datasource.ErrQuotaReservationConflict {
+			continue
+		}
+		if errPut != nil {
+			log.Errorf(errPut, "reserve quota %s failed", key)
+			return release, pb.NewError(pb.ErrUnavailableBackend, errPut.Error())
+		}
+		return func() {
+			if err := datasource.Instance().DeleteQuotaReservation(context.Background(), key, r.ID); err != nil {
+				log.Errorf(err, "delete quota reservation %s/%s failed", key, r.ID)
+			}
+		}, nil
+	}
+	log.Warnf("reserve quota %s conflicted %d times", key, reserveRetries)
+	return release, pb.NewError(pb.ErrUnavailableQuota, "too many concurrent applies of "+key)
+}
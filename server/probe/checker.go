@@ -0,0 +1,451 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"hash/crc32"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/core"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const (
+	// LeaderPrefix is the prefix of the shard elections
+	LeaderPrefix = "probe/"
+
+	DefaultShards      = 16
+	DefaultInterval    = 10 * time.Second
+	DefaultTimeout     = 3 * time.Second
+	DefaultConcurrency = 50
+	DefaultThreshold   = 3
+
+	// PropertyMarkedDown is the instance property marks the instance DOWN by checker,
+	// it is kept in datasource, so the replica owning the shard later recovers the instance
+	PropertyMarkedDown = "probeMarkedDown"
+)
+
+type Options struct {
+	// Shards is the number of shards which are distributed across replicas
+	Shards int
+	// Interval is the interval of probing rounds, an instance is probed
+	// every max(Interval, healthCheck.interval)
+	Interval    time.Duration
+	Timeout     time.Duration
+	Concurrency int
+	// Threshold is the failures to mark instance DOWN if healthCheck.times is not set
+	Threshold int
+}
+
+// Item is an instance to probe
+type Item struct {
+	DomainProject string
+	Instance      *pb.MicroServiceInstance
+}
+
+type state struct {
+	lastProbe  time.Time
+	failures   int
+	markedDown bool
+}
+
+// UpdateStatusFunc updates the instance status in datasource
+type UpdateStatusFunc func(ctx context.Context, item *Item, status string) error
+
+// UpdatePropertiesFunc replaces the instance properties in datasource
+type UpdatePropertiesFunc func(ctx context.Context, item *Item, properties map[string]string) error
+
+// Checker probes the instances with 'pull' health check mode,
+// instances are sharded by id, and the shards are distributed across
+// replicas via the datasource elections
+type Checker struct {
+	opts             Options
+	UpdateStatus     UpdateStatusFunc
+	UpdateProperties UpdatePropertiesFunc
+
+	lock   sync.Mutex
+	states map[string]*state
+	owned  map[int]struct{}
+
+	goroutine *gopool.Pool
+}
+
+// Shard returns the shard of instance
+func (c *Checker) Shard(instanceID string) int {
+	return int(crc32.ChecksumIEEE([]byte(instanceID)) % uint32(c.opts.Shards))
+}
+
+func leaderID(shard int) string {
+	return LeaderPrefix + strconv.Itoa(shard)
+}
+
+// campaign holds or renews the shard lease, it lasts several rounds,
+// so the owner keeps the shard even if one renewal failed
+func (c *Checker) campaign(ctx context.Context, shard int) bool {
+	err := datasource.Instance().Campaign(ctx, &datasource.CampaignRequest{
+		ID:  leaderID(shard),
+		TTL: int64(3*c.opts.Interval/time.Second) + 1,
+	})
+	if err != nil && err != datasource.ErrNotLeader {
+		log.Errorf(err, "campaign probe shard %d failed", shard)
+	}
+	return err == nil
+}
+
+func (c *Checker) resign(ctx context.Context, shard int) {
+	if err := datasource.Instance().Resign(ctx, &datasource.ResignRequest{ID: leaderID(shard)}); err != nil {
+		log.Errorf(err, "resign probe shard %d failed", shard)
+	}
+}
+
+// Claim holds at most ceil(shards/replicas) shards, the shards owned before
+// are kept if possible, so that the failures are counted by the same replica
+func (c *Checker) Claim(ctx context.Context, replicas int) map[int]struct{} {
+	if replicas < 1 {
+		replicas = 1
+	}
+	quota := (c.opts.Shards + replicas - 1) / replicas
+	owned := make(map[int]struct{}, quota)
+	for shard := range c.owned {
+		if len(owned) >= quota {
+			// release the extra shards to the new replicas
+			c.resign(ctx, shard)
+			continue
+		}
+		if c.campaign(ctx, shard) {
+			owned[shard] = struct{}{}
+		}
+	}
+	for _, shard := range rand.Perm(c.opts.Shards) {
+		if len(owned) >= quota {
+			break
+		}
+		if _, ok := owned[shard]; ok {
+			continue
+		}
+		if c.campaign(ctx, shard) {
+			owned[shard] = struct{}{}
+		}
+	}
+	c.owned = owned
+	return owned
+}
+
+// Check probes the due items concurrently and waits for all done
+func (c *Checker) Check(ctx context.Context, items []*Item) {
+	now := time.Now()
+	pool := gopool.New(ctx, gopool.Configure().Workers(c.opts.Concurrency))
+	exists := make(map[string]struct{}, len(items))
+	c.lock.Lock()
+	for _, item := range items {
+		id := item.Instance.InstanceId
+		exists[id] = struct{}{}
+		s, ok := c.states[id]
+		if !ok {
+			s = &state{markedDown: markedDown(item.Instance)}
+			c.states[id] = s
+		}
+		if now.Sub(s.lastProbe) < c.interval(item.Instance) {
+			continue
+		}
+		s.lastProbe = now
+		it := item
+		pool.Do(func(ctx context.Context) {
+			c.check(ctx, it)
+		})
+	}
+	// forget the instances which are deleted or moved to other replicas
+	for id := range c.states {
+		if _, ok := exists[id]; !ok {
+			delete(c.states, id)
+		}
+	}
+	c.lock.Unlock()
+	pool.Done()
+	c.alarm()
+}
+
+func (c *Checker) interval(instance *pb.MicroServiceInstance) time.Duration {
+	d := time.Duration(instance.HealthCheck.Interval) * time.Second
+	if d < c.opts.Interval {
+		return c.opts.Interval
+	}
+	return d
+}
+
+func (c *Checker) threshold(instance *pb.MicroServiceInstance) int {
+	if instance.HealthCheck.Times > 0 {
+		return int(instance.HealthCheck.Times)
+	}
+	return c.opts.Threshold
+}
+
+func (c *Checker) check(ctx context.Context, item *Item) {
+	instance := item.Instance
+	target, err := NewTarget(instance)
+	if err != nil {
+		log.Warnf("skip probing instance[%s/%s], %s", instance.ServiceId, instance.InstanceId, err.Error())
+		return
+	}
+	pctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	err = Probe(pctx, target)
+	cancel()
+
+	c.lock.Lock()
+	s, ok := c.states[instance.InstanceId]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+	if err == nil {
+		s.failures = 0
+	} else {
+		s.failures++
+	}
+	failures, marked := s.failures, s.markedDown || markedDown(instance)
+	c.lock.Unlock()
+
+	switch {
+	case err != nil && failures >= c.threshold(instance) && instance.Status != pb.MSI_DOWN:
+		log.Errorf(err, "probe instance[%s/%s] %s failed %d times, mark it DOWN",
+			instance.ServiceId, instance.InstanceId, target.Address, failures)
+		// mark before updating the status, so the instance is recovered even if this replica is gone
+		if uerr := c.mark(ctx, item, true); uerr != nil {
+			log.Errorf(uerr, "mark instance[%s/%s] DOWN by checker failed", instance.ServiceId, instance.InstanceId)
+			return
+		}
+		if uerr := c.UpdateStatus(ctx, item, pb.MSI_DOWN); uerr != nil {
+			log.Errorf(uerr, "mark instance[%s/%s] DOWN failed", instance.ServiceId, instance.InstanceId)
+			return
+		}
+		c.setMarkedDown(instance.InstanceId, true)
+		if aerr := alarm.Raise(alarm.IDInstanceProbeFailed,
+			alarm.FieldString(alarm.FieldDomainProject, item.DomainProject),
+			alarm.AdditionalContext("instance[%s/%s] %s is unhealthy: %s",
+				instance.ServiceId, instance.InstanceId, target.Address, err.Error())); aerr != nil {
+			log.Error("", aerr)
+		}
+	case err != nil:
+		log.Warnf("probe instance[%s/%s] %s failed %d times: %s",
+			instance.ServiceId, instance.InstanceId, target.Address, failures, err.Error())
+	case marked:
+		// only recover the instances marked DOWN by checker
+		log.Infof("instance[%s/%s] %s recovered, mark it UP",
+			instance.ServiceId, instance.InstanceId, target.Address)
+		if uerr := c.UpdateStatus(ctx, item, pb.MSI_UP); uerr != nil {
+			log.Errorf(uerr, "mark instance[%s/%s] UP failed", instance.ServiceId, instance.InstanceId)
+			return
+		}
+		// the instance is UP, the marker is removed again next round if failed
+		if uerr := c.mark(ctx, item, false); uerr != nil {
+			log.Errorf(uerr, "unmark instance[%s/%s] by checker failed", instance.ServiceId, instance.InstanceId)
+			return
+		}
+		c.setMarkedDown(instance.InstanceId, false)
+	}
+}
+
+func markedDown(instance *pb.MicroServiceInstance) bool {
+	_, ok := instance.Properties[PropertyMarkedDown]
+	return ok
+}
+
+// mark adds or removes PropertyMarkedDown of the instance, the other properties are kept
+func (c *Checker) mark(ctx context.Context, item *Item, marked bool) error {
+	if markedDown(item.Instance) == marked {
+		return nil
+	}
+	properties := make(map[string]string, len(item.Instance.Properties)+1)
+	for k, v := range item.Instance.Properties {
+		properties[k] = v
+	}
+	if marked {
+		properties[PropertyMarkedDown] = "true"
+	} else {
+		delete(properties, PropertyMarkedDown)
+	}
+	return c.UpdateProperties(ctx, item, properties)
+}
+
+func (c *Checker) setMarkedDown(id string, b bool) {
+	c.lock.Lock()
+	if s, ok := c.states[id]; ok {
+		s.markedDown = b
+	}
+	c.lock.Unlock()
+}
+
+// alarm clears the alarm if no instance is marked DOWN by checker
+func (c *Checker) alarm() {
+	c.lock.Lock()
+	for _, s := range c.states {
+		if s.markedDown {
+			c.lock.Unlock()
+			return
+		}
+	}
+	c.lock.Unlock()
+	for _, a := range alarm.ListAll() {
+		if a.ID == alarm.IDInstanceProbeFailed && a.Status != alarm.Cleared {
+			if err := alarm.Clear(alarm.IDInstanceProbeFailed); err != nil {
+				log.Error("", err)
+			}
+			return
+		}
+	}
+}
+
+// Collect returns the instances to probe and the number of service center replicas
+func Collect(cache *dump.Cache) (items []*Item, replicas int) {
+	if cache == nil {
+		return
+	}
+	cache.Instances.ForEach(func(_ int, kv *dump.KV) bool {
+		instance, ok := kv.Value.(*pb.MicroServiceInstance)
+		if !ok {
+			return true
+		}
+		if core.Service != nil && len(core.Service.ServiceId) > 0 && instance.ServiceId == core.Service.ServiceId {
+			replicas++
+			return true
+		}
+		if instance.HealthCheck == nil || instance.HealthCheck.Mode != pb.CHECK_BY_PLATFORM {
+			return true
+		}
+		items = append(items, &Item{DomainProject: domainProject(kv.Key), Instance: instance})
+		return true
+	})
+	return
+}
+
+// domainProject parses the domain project from key
+// /cse-sr/inst/files/{domain}/{project}/{serviceId}/{instanceId}
+func domainProject(key string) string {
+	arr := strings.Split(strings.TrimPrefix(key, datasource.InstanceKeyPrefix+datasource.SPLIT), datasource.SPLIT)
+	if len(arr) < 2 {
+		return ""
+	}
+	return util.StringJoin(arr[:2], datasource.SPLIT)
+}
+
+// Round claims the shards and probes the instances in them
+func (c *Checker) Round(ctx context.Context) {
+	items, replicas := Collect(datasource.Instance().DumpCache(ctx))
+	owned := c.Claim(ctx, replicas)
+	mine := items[:0]
+	for _, item := range items {
+		if _, ok := owned[c.Shard(item.Instance.InstanceId)]; ok {
+			mine = append(mine, item)
+		}
+	}
+	c.Check(ctx, mine)
+}
+
+func (c *Checker) loop(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Round(ctx)
+		}
+	}
+}
+
+func (c *Checker) Start() {
+	c.goroutine.Do(c.loop)
+}
+
+func (c *Checker) Stop() {
+	c.goroutine.Close(true)
+	for shard := range c.owned {
+		c.resign(context.Background(), shard)
+	}
+}
+
+func updateStatus(ctx context.Context, item *Item, status string) error {
+	domain, project := util.FromDomainProject(item.DomainProject)
+	ctx = util.SetDomainProject(ctx, domain, project)
+	resp, err := datasource.Instance().UpdateInstanceStatus(ctx, &pb.UpdateInstanceStatusRequest{
+		ServiceId:  item.Instance.ServiceId,
+		InstanceId: item.Instance.InstanceId,
+		Status:     status,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Response.GetCode() != pb.ResponseSuccess {
+		return pb.NewError(resp.Response.GetCode(), resp.Response.GetMessage())
+	}
+	return nil
+}
+
+func updateProperties(ctx context.Context, item *Item, properties map[string]string) error {
+	domain, project := util.FromDomainProject(item.DomainProject)
+	ctx = util.SetDomainProject(ctx, domain, project)
+	resp, err := datasource.Instance().UpdateInstanceProperties(ctx, &pb.UpdateInstancePropsRequest{
+		ServiceId:  item.Instance.ServiceId,
+		InstanceId: item.Instance.InstanceId,
+		Properties: properties,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Response.GetCode() != pb.ResponseSuccess {
+		return pb.NewError(resp.Response.GetCode(), resp.Response.GetMessage())
+	}
+	return nil
+}
+
+func NewChecker(opts Options) *Checker {
+	if opts.Shards <= 0 {
+		opts.Shards = DefaultShards
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	return &Checker{
+		opts:             opts,
+		UpdateStatus:     updateStatus,
+		UpdateProperties: updateProperties,
+		states:           make(map[string]*state),
+		goroutine:        gopool.New(context.Background()),
+	}
+}
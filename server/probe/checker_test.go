@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/server/probe"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Check(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var (
+		lock     sync.Mutex
+		statuses []string
+	)
+	item := &probe.Item{
+		DomainProject: "default/default",
+		Instance: &pb.MicroServiceInstance{
+			ServiceId:   "s1",
+			InstanceId:  "i1",
+			Status:      pb.MSI_UP,
+			HealthCheck: &pb.HealthCheck{Mode: pb.CHECK_BY_PLATFORM, Url: server.URL, Times: 2},
+		},
+	}
+	newChecker := func() *probe.Checker {
+		c := probe.NewChecker(probe.Options{Interval: 1})
+		c.UpdateStatus = func(ctx context.Context, item *probe.Item, status string) error {
+			lock.Lock()
+			statuses = append(statuses, status)
+			lock.Unlock()
+			item.Instance.Status = status
+			return nil
+		}
+		c.UpdateProperties = func(ctx context.Context, item *probe.Item, properties map[string]string) error {
+			item.Instance.Properties = properties
+			return nil
+		}
+		return c
+	}
+	c := newChecker()
+	ctx := context.Background()
+
+	c.Check(ctx, []*probe.Item{item})
+	assert.Empty(t, statuses)
+
+	healthy = false
+	c.Check(ctx, []*probe.Item{item})
+	assert.Empty(t, statuses)
+	c.Check(ctx, []*probe.Item{item})
+	assert.Equal(t, []string{pb.MSI_DOWN}, statuses)
+	assert.Contains(t, item.Instance.Properties, probe.PropertyMarkedDown)
+	c.Check(ctx, []*probe.Item{item})
+	assert.Equal(t, []string{pb.MSI_DOWN}, statuses)
+
+	// the replica owning the shard after restarting recovers the instance by the marker
+	c = newChecker()
+	healthy = true
+	c.Check(ctx, []*probe.Item{item})
+	assert.Equal(t, []string{pb.MSI_DOWN, pb.MSI_UP}, statuses)
+	assert.NotContains(t, item.Instance.Properties, probe.PropertyMarkedDown)
+
+	// do not recover the instances not marked DOWN by checker
+	item.Instance.Status = pb.MSI_DOWN
+	c.Check(ctx, []*probe.Item{item})
+	assert.Equal(t, []string{pb.MSI_DOWN, pb.MSI_UP}, statuses)
+}
+
+func TestChecker_Shard(t *testing.T) {
+	c := probe.NewChecker(probe.Options{Shards: 4})
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		shard := c.Shard(id)
+		assert.True(t, shard >= 0 && shard < 4)
+		assert.Equal(t, shard, c.Shard(id))
+	}
+}
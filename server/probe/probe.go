@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"sync"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+var (
+	checker *Checker
+	once    sync.Once
+)
+
+// Enabled returns true if the active health checking is enabled
+func Enabled() bool {
+	return config.GetBool("probe.enable", false)
+}
+
+// Init starts the checker if probe is enabled
+func Init() {
+	if !Enabled() {
+		return
+	}
+	once.Do(func() {
+		checker = NewChecker(Options{
+			Shards:      config.GetInt("probe.shards", DefaultShards),
+			Interval:    config.GetDuration("probe.interval", DefaultInterval),
+			Timeout:     config.GetDuration("probe.timeout", DefaultTimeout),
+			Concurrency: config.GetInt("probe.concurrency", DefaultConcurrency),
+			Threshold:   config.GetInt("probe.threshold", DefaultThreshold),
+		})
+		checker.Start()
+		log.Info("instance probe checker started")
+	})
+}
+
+// Stop stops the checker if started
+func Stop() {
+	if checker != nil {
+		checker.Stop()
+	}
+}
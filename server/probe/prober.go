@@ -0,0 +1,174 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolTCP  = "tcp"
+	ProtocolGRPC = "grpc"
+)
+
+var ErrNoProbe = errors.New("no probe url or port")
+
+// Target is where to probe an instance
+type Target struct {
+	Protocol string
+	// Address is the url of http, or the host:port of tcp and grpc
+	Address string
+	// Service is the service name of grpc health protocol
+	Service string
+}
+
+// NewTarget resolves the probe target from instance health check settings:
+// 1. url is 'http(s)://host:port/path', probe it by http
+// 2. url is '/path', probe it by http, the host and port are from the first endpoint
+// 3. url is 'grpc://host:port/service', probe it by grpc health protocol,
+// the host and port are from the first endpoint if empty
+// 4. url is 'tcp://host:port', or empty but port is set, probe it by tcp
+func NewTarget(instance *pb.MicroServiceInstance) (*Target, error) {
+	hc := instance.HealthCheck
+	if hc == nil || (len(hc.Url) == 0 && hc.Port <= 0) {
+		return nil, ErrNoProbe
+	}
+	host, port := endpointHost(instance.Endpoints)
+	if hc.Port > 0 {
+		port = strconv.Itoa(int(hc.Port))
+	}
+	defaultAddr := net.JoinHostPort(host, port)
+
+	if len(hc.Url) == 0 {
+		return &Target{Protocol: ProtocolTCP, Address: defaultAddr}, nil
+	}
+	if strings.HasPrefix(hc.Url, "/") {
+		return &Target{Protocol: ProtocolHTTP, Address: "http://" + defaultAddr + hc.Url}, nil
+	}
+	u, err := url.Parse(hc.Url)
+	if err != nil {
+		return nil, err
+	}
+	addr := u.Host
+	if len(addr) == 0 {
+		addr = defaultAddr
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &Target{Protocol: ProtocolHTTP, Address: hc.Url}, nil
+	case ProtocolGRPC:
+		return &Target{Protocol: ProtocolGRPC, Address: addr, Service: strings.TrimPrefix(u.Path, "/")}, nil
+	case ProtocolTCP:
+		return &Target{Protocol: ProtocolTCP, Address: addr}, nil
+	default:
+		return nil, fmt.Errorf("unsupported probe url %s", hc.Url)
+	}
+}
+
+func endpointHost(endpoints []string) (host, port string) {
+	for _, ep := range endpoints {
+		u, err := url.Parse(ep)
+		if err != nil || len(u.Host) == 0 {
+			continue
+		}
+		return u.Hostname(), u.Port()
+	}
+	return
+}
+
+// Probe returns nil if the target is healthy
+func Probe(ctx context.Context, t *Target) error {
+	switch t.Protocol {
+	case ProtocolHTTP:
+		return probeHTTP(ctx, t)
+	case ProtocolTCP:
+		return probeTCP(ctx, t)
+	case ProtocolGRPC:
+		return probeGRPC(ctx, t)
+	default:
+		return fmt.Errorf("unsupported probe protocol %s", t.Protocol)
+	}
+}
+
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		// the instances usually use self-signed certificates
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func probeHTTP(ctx context.Context, t *Target) error {
+	req, err := http.NewRequest(http.MethodGet, t.Address, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unhealthy status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func probeTCP(ctx context.Context, t *Target) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.Address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func probeGRPC(ctx context.Context, t *Target) error {
+	conn, err := grpc.DialContext(ctx, t.Address, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: t.Service})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("unhealthy grpc status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/server/probe"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTarget(t *testing.T) {
+	inst := func(hc *pb.HealthCheck) *pb.MicroServiceInstance {
+		return &pb.MicroServiceInstance{
+			Endpoints:   []string{"rest://127.0.0.1:8080?sslEnabled=false"},
+			HealthCheck: hc,
+		}
+	}
+	_, err := probe.NewTarget(inst(nil))
+	assert.Equal(t, probe.ErrNoProbe, err)
+	_, err = probe.NewTarget(inst(&pb.HealthCheck{Mode: pb.CHECK_BY_PLATFORM}))
+	assert.Equal(t, probe.ErrNoProbe, err)
+
+	target, err := probe.NewTarget(inst(&pb.HealthCheck{Url: "/health"}))
+	assert.NoError(t, err)
+	assert.Equal(t, &probe.Target{Protocol: probe.ProtocolHTTP, Address: "http://127.0.0.1:8080/health"}, target)
+
+	target, err = probe.NewTarget(inst(&pb.HealthCheck{Url: "/health", Port: 9090}))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:9090/health", target.Address)
+
+	target, err = probe.NewTarget(inst(&pb.HealthCheck{Url: "https://10.0.0.1/health"}))
+	assert.NoError(t, err)
+	assert.Equal(t, &probe.Target{Protocol: probe.ProtocolHTTP, Address: "https://10.0.0.1/health"}, target)
+
+	target, err = probe.NewTarget(inst(&pb.HealthCheck{Port: 9090}))
+	assert.NoError(t, err)
+	assert.Equal(t, &probe.Target{Protocol: probe.ProtocolTCP, Address: "127.0.0.1:9090"}, target)
+
+	target, err = probe.NewTarget(inst(&pb.HealthCheck{Url: "grpc://10.0.0.1:50051/foo"}))
+	assert.NoError(t, err)
+	assert.Equal(t, &probe.Target{Protocol: probe.ProtocolGRPC, Address: "10.0.0.1:50051", Service: "foo"}, target)
+
+	_, err = probe.NewTarget(inst(&pb.HealthCheck{Url: "udp://10.0.0.1:53"}))
+	assert.Error(t, err)
+}
+
+func TestProbe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	t.Run("http", func(t *testing.T) {
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		defer server.Close()
+		target := &probe.Target{Protocol: probe.ProtocolHTTP, Address: server.URL + "/health"}
+		assert.NoError(t, probe.Probe(ctx, target))
+		status = http.StatusServiceUnavailable
+		assert.Error(t, probe.Probe(ctx, target))
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		addr := l.Addr().String()
+		assert.NoError(t, probe.Probe(ctx, &probe.Target{Protocol: probe.ProtocolTCP, Address: addr}))
+		l.Close()
+		assert.Error(t, probe.Probe(ctx, &probe.Target{Protocol: probe.ProtocolTCP, Address: addr}))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Error(t, probe.Probe(ctx, &probe.Target{Protocol: "udp"}))
+	})
+}
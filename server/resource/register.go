@@ -19,9 +19,11 @@ package v4
 
 import (
 	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
 	v1 "github.com/apache/servicecomb-service-center/server/resource/v1"
 	v4 "github.com/apache/servicecomb-service-center/server/resource/v4"
 	"github.com/apache/servicecomb-service-center/server/service/rbac"
+	"github.com/apache/servicecomb-service-center/server/webhook"
 )
 
 func init() {
@@ -33,5 +35,12 @@ func initRouter() {
 		roa.RegisterServant(&v4.AuthResource{})
 		roa.RegisterServant(&v4.RoleResource{})
 	}
+	if webhook.Enabled() {
+		roa.RegisterServant(&v4.WebhookResource{})
+	}
+	if auditlog.Enabled() {
+		roa.RegisterServant(&v4.AuditResource{})
+	}
+	roa.RegisterServant(&v4.QuotaResource{})
 	roa.RegisterServant(&v1.Governance{})
 }
@@ -18,12 +18,20 @@
 package v1
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
 
+	"github.com/apache/servicecomb-service-center/datasource"
+	govmodel "github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/rest/controller"
 	"github.com/apache/servicecomb-service-center/server/service/gov"
@@ -40,8 +48,16 @@ const (
 	ProjectKey     = ":project"
 	IDKey          = ":id"
 	DisplayKey     = "display"
+	DistributorKey = "distributors"
+	RevisionKey    = "revision"
+	WaitKey        = "wait"
+	InstanceKey    = "instanceId"
+	HeaderRevision = "X-Gov-Revision"
 )
 
+//MaxWatchWait is the upper limit of wait, the longer waits are shortened to it
+const MaxWatchWait = time.Minute
+
 //Create gov config
 func (t *Governance) Create(w http.ResponseWriter, req *http.Request) {
 	kind := req.URL.Query().Get(KindKey)
@@ -52,13 +68,17 @@ func (t *Governance) Create(w http.ResponseWriter, req *http.Request) {
 		controller.WriteError(w, discovery.ErrInternal, err.Error())
 		return
 	}
-	id, err := gov.Create(kind, project, body)
+	id, err := gov.Create(req.Context(), kind, project, body)
 	if err != nil {
 		if _, ok := err.(*kie.ErrIllegalItem); ok {
 			log.Error("", err)
 			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
 			return
 		}
+		if err == gov.ErrPolicyExist {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
 		processError(w, err, "create gov data err")
 		return
 	}
@@ -81,7 +101,7 @@ func (t *Governance) Put(w http.ResponseWriter, req *http.Request) {
 		controller.WriteError(w, discovery.ErrInternal, err.Error())
 		return
 	}
-	err = gov.Update(id, kind, project, body)
+	err = gov.Update(req.Context(), id, kind, project, body)
 	if err != nil {
 		if _, ok := err.(*kie.ErrIllegalItem); ok {
 			log.Error("", err)
@@ -100,12 +120,31 @@ func (t *Governance) ListOrDisPlay(w http.ResponseWriter, req *http.Request) {
 	project := req.URL.Query().Get(ProjectKey)
 	app := req.URL.Query().Get(AppKey)
 	environment := req.URL.Query().Get(EnvironmentKey)
+	instanceID := req.URL.Query().Get(InstanceKey)
+	if kind == DistributorKey {
+		controller.WriteResponse(w, req, nil, gov.Status())
+		return
+	}
+	rev, wait, err := watchParams(req)
+	if err != nil {
+		log.Error("invalid watch params", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	rev, err = watch(req.Context(), project, rev, wait)
+	switch err {
+	case nil:
+		w.Header().Set(HeaderRevision, strconv.FormatInt(rev, 10))
+	case gov.ErrWatchNotSupported:
+	default:
+		processError(w, err, "watch gov err")
+		return
+	}
 	var body []byte
-	var err error
 	if kind == DisplayKey {
-		body, err = gov.Display(project, app, environment)
+		body, err = gov.DisplayApplied(req.Context(), project, app, environment, instanceID)
 	} else {
-		body, err = gov.List(kind, project, app, environment)
+		body, err = gov.ListApplied(req.Context(), kind, project, app, environment, instanceID)
 	}
 	if err != nil {
 		processError(w, err, "list gov err")
@@ -120,12 +159,43 @@ func (t *Governance) ListOrDisPlay(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set(rest.HeaderContentType, rest.ContentTypeJSON)
 }
 
+func watchParams(req *http.Request) (rev int64, wait time.Duration, err error) {
+	if revision := req.URL.Query().Get(RevisionKey); len(revision) > 0 {
+		rev, err = strconv.ParseInt(revision, 10, 64)
+		if err != nil {
+			return
+		}
+	}
+	if timeout := req.URL.Query().Get(WaitKey); len(timeout) > 0 {
+		wait, err = time.ParseDuration(timeout)
+		if err != nil {
+			return
+		}
+		if wait < 0 {
+			err = fmt.Errorf("invalid wait %s", timeout)
+			return
+		}
+		if wait > MaxWatchWait {
+			wait = MaxWatchWait
+		}
+	}
+	return
+}
+
+//watch blocks until the policies of project changed after rev or wait timeout,
+//it returns the latest revision immediately if wait is 0
+func watch(ctx context.Context, project string, rev int64, wait time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	return gov.Watch(ctx, project, rev)
+}
+
 //Get gov config
 func (t *Governance) Get(w http.ResponseWriter, req *http.Request) {
 	kind := req.URL.Query().Get(KindKey)
 	id := req.URL.Query().Get(IDKey)
 	project := req.URL.Query().Get(ProjectKey)
-	body, err := gov.Get(kind, id, project)
+	body, err := gov.Get(req.Context(), kind, id, project)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		log.Error("get gov err", err)
@@ -141,11 +211,149 @@ func (t *Governance) Get(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set(rest.HeaderContentType, rest.ContentTypeJSON)
 }
 
+//History returns the revision history of gov config
+func (t *Governance) History(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get(IDKey)
+	project := req.URL.Query().Get(ProjectKey)
+	histories, err := gov.History(req.Context(), project, id)
+	if err != nil {
+		processError(w, err, "list gov history err")
+		return
+	}
+	controller.WriteResponse(w, req, nil, histories)
+}
+
+//Rollback reverts gov config to a revision in history
+func (t *Governance) Rollback(w http.ResponseWriter, req *http.Request) {
+	kind := req.URL.Query().Get(KindKey)
+	id := req.URL.Query().Get(IDKey)
+	project := req.URL.Query().Get(ProjectKey)
+	rev, err := strconv.ParseInt(req.URL.Query().Get(RevisionKey), 10, 64)
+	if err != nil {
+		log.Error("invalid revision", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	err = gov.Rollback(req.Context(), kind, project, id, rev)
+	if err != nil {
+		if err == gov.ErrHistoryNotExist || err == gov.ErrRollbackToDeleted || err == gov.ErrHistoryKindMismatch {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		if _, ok := err.(*kie.ErrIllegalItem); ok {
+			log.Error("", err)
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "rollback gov err")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//Rollout starts the staged rollout of gov config
+func (t *Governance) Rollout(w http.ResponseWriter, req *http.Request) {
+	kind := req.URL.Query().Get(KindKey)
+	id := req.URL.Query().Get(IDKey)
+	project := req.URL.Query().Get(ProjectKey)
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	r := &govmodel.Rollout{}
+	if err = json.Unmarshal(body, r); err == nil {
+		err = r.Validate()
+	}
+	if err != nil {
+		log.Error("invalid rollout", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	err = gov.StartRollout(req.Context(), kind, project, id, r)
+	if err != nil {
+		if err == gov.ErrRolloutNotHonored || err == datasource.ErrGovRolloutConflict {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "start gov rollout err")
+		return
+	}
+	controller.WriteResponse(w, req, nil, r)
+}
+
+//Promote moves the rollout of gov config to the next stage
+func (t *Governance) Promote(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get(IDKey)
+	project := req.URL.Query().Get(ProjectKey)
+	r, err := gov.Promote(req.Context(), project, id)
+	if err != nil {
+		if err == datasource.ErrGovRolloutNotExist || err == gov.ErrRolloutAborted ||
+			err == datasource.ErrGovRolloutConflict {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "promote gov rollout err")
+		return
+	}
+	if r == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	controller.WriteResponse(w, req, nil, r)
+}
+
+//Abort stops the rollout of gov config, the config applies to no instance
+func (t *Governance) Abort(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get(IDKey)
+	project := req.URL.Query().Get(ProjectKey)
+	err := gov.Abort(req.Context(), project, id, "aborted by "+rbacframe.OwnerFromContext(req.Context()))
+	if err != nil {
+		if err == datasource.ErrGovRolloutNotExist {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "abort gov rollout err")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//Simulate evaluates gov config for a sample request
+func (t *Governance) Simulate(w http.ResponseWriter, req *http.Request) {
+	project := req.URL.Query().Get(ProjectKey)
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	r := &govmodel.SimulateRequest{}
+	err = json.Unmarshal(body, r)
+	if err != nil {
+		log.Error("invalid simulate request", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if len(r.App) == 0 || len(r.Method) == 0 {
+		controller.WriteError(w, discovery.ErrInvalidParams, "app and method can not be empty")
+		return
+	}
+	result, err := gov.Simulate(req.Context(), project, r)
+	if err != nil {
+		processError(w, err, "simulate gov err")
+		return
+	}
+	controller.WriteResponse(w, req, nil, result)
+}
+
 //Delete delete gov config
 func (t *Governance) Delete(w http.ResponseWriter, req *http.Request) {
+	kind := req.URL.Query().Get(KindKey)
 	id := req.URL.Query().Get(IDKey)
 	project := req.URL.Query().Get(ProjectKey)
-	err := gov.Delete(id, project)
+	err := gov.Delete(req.Context(), kind, id, project)
 	if err != nil {
 		processError(w, err, "delete gov err")
 		return
@@ -164,10 +372,17 @@ func (t *Governance) URLPatterns() []rest.Route {
 		//servicecomb.marker.{name}
 		//servicecomb.rateLimiter.{name}
 		//....
+		// simulate is registered before creating, or it is taken as a kind
+		{Method: http.MethodPost, Path: "/v1/:project/gov/simulate", Func: t.Simulate},
 		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey, Func: t.Create},
 		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey, Func: t.ListOrDisPlay},
 		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Get},
 		{Method: http.MethodPut, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Put},
 		{Method: http.MethodDelete, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Delete},
+		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/history", Func: t.History},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/rollback", Func: t.Rollback},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/rollout", Func: t.Rollout},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/promote", Func: t.Promote},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/abort", Func: t.Abort},
 	}
 }
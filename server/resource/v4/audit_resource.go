@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v4
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/go-chassis/cari/discovery"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditResource struct {
+}
+
+//URLPatterns define http pattern
+func (r *AuditResource) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodGet, Path: "/v4/admin/audit", Func: r.ListAudit},
+	}
+}
+
+//ListAudit lists the latest audit records matching the query
+func (r *AuditResource) ListAudit(w http.ResponseWriter, req *http.Request) {
+	q, err := parseAuditQuery(req.URL.Query())
+	if err != nil {
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	records, err := datasource.Instance().ListAuditRecord(req.Context(), q)
+	if err != nil {
+		log.Error("list audit records failed", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, req, nil, &audit.ListResponse{
+		Total:   int64(len(records)),
+		Records: records,
+	})
+}
+
+func parseAuditQuery(query url.Values) (*audit.Query, error) {
+	q := &audit.Query{
+		Domain:   query.Get("domain"),
+		Project:  query.Get("project"),
+		Account:  query.Get("account"),
+		Resource: query.Get("resource"),
+		Verb:     query.Get("verb"),
+		Target:   query.Get("target"),
+		Limit:    defaultAuditLimit,
+	}
+	for name, v := range map[string]*int64{"start": &q.Start, "end": &q.End} {
+		s := query.Get(name)
+		if len(s) == 0 {
+			continue
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, errInvalidQuery(name)
+		}
+		*v = i
+	}
+	for name, v := range map[string]*int{"status": &q.StatusCode, "limit": &q.Limit} {
+		s := query.Get(name)
+		if len(s) == 0 {
+			continue
+		}
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 0 {
+			return nil, errInvalidQuery(name)
+		}
+		*v = i
+	}
+	if q.Limit == 0 || q.Limit > maxAuditLimit {
+		q.Limit = maxAuditLimit
+	}
+	return q, nil
+}
+
+func errInvalidQuery(name string) error {
+	return fmt.Errorf("invalid query parameter %s", name)
+}
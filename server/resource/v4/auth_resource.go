@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
@@ -37,6 +38,7 @@ import (
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/rbac"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/go-chassis/go-chassis/v2/server/restful"
 )
 
 type AuthResource struct {
@@ -46,11 +48,20 @@ type AuthResource struct {
 func (r *AuthResource) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{Method: http.MethodPost, Path: "/v4/token", Func: r.Login},
+		{Method: http.MethodDelete, Path: "/v4/token", Func: r.Logout},
+		{Method: http.MethodPost, Path: "/v4/token/oidc", Func: r.LoginWithIDToken},
+		{Method: http.MethodGet, Path: "/v4/.well-known/jwks.json", Func: r.JWKS},
 		{Method: http.MethodPost, Path: "/v4/account", Func: r.CreateAccount},
 		{Method: http.MethodGet, Path: "/v4/account", Func: r.ListAccount},
 		{Method: http.MethodGet, Path: "/v4/account/:name", Func: r.GetAccount},
 		{Method: http.MethodDelete, Path: "/v4/account/:name", Func: r.DeleteAccount},
 		{Method: http.MethodPost, Path: "/v4/account/:name/password", Func: r.ChangePassword},
+		{Method: http.MethodDelete, Path: "/v4/account/:name/sessions", Func: r.RevokeSessions},
+		{Method: http.MethodPost, Path: "/v4/account/:name/keys", Func: r.CreateAPIKey},
+		{Method: http.MethodGet, Path: "/v4/account/:name/keys", Func: r.ListAPIKey},
+		{Method: http.MethodDelete, Path: "/v4/account/:name/keys/:id", Func: r.RevokeAPIKey},
+		{Method: http.MethodGet, Path: "/v4/admin/banned", Func: r.ListBanned},
+		{Method: http.MethodDelete, Path: "/v4/admin/banned", Func: r.ReleaseBanned},
 	}
 }
 func (r *AuthResource) CreateAccount(w http.ResponseWriter, req *http.Request) {
@@ -74,24 +85,32 @@ func (r *AuthResource) CreateAccount(w http.ResponseWriter, req *http.Request) {
 		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
 		return
 	}
-	err = dao.CreateAccount(context.TODO(), a)
+	err = rbacsvc.CreateAccount(context.TODO(), a)
 	if err != nil {
 		if err == datasource.ErrAccountDuplicated {
 			controller.WriteError(w, discovery.ErrConflictAccount, "")
 			return
 		}
+		if errQuota, ok := err.(*discovery.Error); ok {
+			controller.WriteError(w, errQuota.Code, errQuota.Detail)
+			return
+		}
 		log.Error(errorsEx.MsgOperateAccountFailed, err)
 		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgOperateAccountFailed)
 		return
 	}
 }
 func (r *AuthResource) DeleteAccount(w http.ResponseWriter, req *http.Request) {
-	_, err := dao.DeleteAccount(context.TODO(), req.URL.Query().Get(":name"))
+	name := req.URL.Query().Get(":name")
+	_, err := dao.DeleteAccount(context.TODO(), name)
 	if err != nil {
 		log.Error(errorsEx.MsgOperateAccountFailed, err)
 		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgOperateAccountFailed)
 		return
 	}
+	if err = rbacsvc.RevokeAccountSessions(context.TODO(), name); err != nil {
+		log.Errorf(err, "revoke sessions of deleted account [%s] failed", name)
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 func (r *AuthResource) ListAccount(w http.ResponseWriter, req *http.Request) {
@@ -131,8 +150,9 @@ func (r *AuthResource) GetAccount(w http.ResponseWriter, req *http.Request) {
 }
 func (r *AuthResource) ChangePassword(w http.ResponseWriter, req *http.Request) {
 	ip := util.GetRealIP(req)
-	if rbacsvc.IsBanned(ip) {
-		log.Warn("ip is banned:" + ip)
+	name := req.URL.Query().Get(":name")
+	if rbacsvc.IsBanned(req.Context(), ip, name) {
+		log.Warnf("ip [%s] or account [%s] is banned", ip, name)
 		controller.WriteError(w, discovery.ErrForbidden, "")
 		return
 	}
@@ -148,7 +168,7 @@ func (r *AuthResource) ChangePassword(w http.ResponseWriter, req *http.Request)
 		controller.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
 		return
 	}
-	a.Name = req.URL.Query().Get(":name")
+	a.Name = name
 	err = service.ValidateChangePWD(a)
 	if err != nil {
 		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
@@ -162,13 +182,14 @@ func (r *AuthResource) ChangePassword(w http.ResponseWriter, req *http.Request)
 	err = rbacsvc.ChangePassword(context.TODO(), changer.Roles, changer.Name, a)
 	if err != nil {
 		if err == rbacsvc.ErrSamePassword ||
+			err == rbacsvc.ErrPasswordReused ||
 			err == rbacsvc.ErrEmptyCurrentPassword ||
 			err == rbacsvc.ErrNoPermChangeAccount {
 			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
 			return
 		}
 		if err == rbacsvc.ErrWrongPassword {
-			rbacsvc.CountFailure(ip)
+			rbacsvc.CountFailure(req.Context(), ip, a.Name)
 			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
 			return
 		}
@@ -176,14 +197,12 @@ func (r *AuthResource) ChangePassword(w http.ResponseWriter, req *http.Request)
 		controller.WriteError(w, discovery.ErrInternal, err.Error())
 		return
 	}
+	if err = rbacsvc.RevokeAccountSessions(context.TODO(), a.Name); err != nil {
+		log.Errorf(err, "revoke sessions of account [%s] failed", a.Name)
+	}
 }
 func (r *AuthResource) Login(w http.ResponseWriter, req *http.Request) {
 	ip := util.GetRealIP(req)
-	if rbacsvc.IsBanned(ip) {
-		log.Warn("ip is banned:" + ip)
-		controller.WriteError(w, discovery.ErrForbidden, "")
-		return
-	}
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		log.Error("read body err", err)
@@ -196,6 +215,11 @@ func (r *AuthResource) Login(w http.ResponseWriter, req *http.Request) {
 		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
 		return
 	}
+	if rbacsvc.IsBanned(req.Context(), ip, a.Name) {
+		log.Warnf("ip [%s] or account [%s] is banned", ip, a.Name)
+		controller.WriteError(w, discovery.ErrForbidden, "")
+		return
+	}
 	if a.TokenExpirationTime == "" {
 		a.TokenExpirationTime = "30m"
 	}
@@ -209,7 +233,7 @@ func (r *AuthResource) Login(w http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		if err == rbacsvc.ErrUnauthorized {
 			log.Error("not authorized", err)
-			rbacsvc.CountFailure(ip)
+			rbacsvc.CountFailure(req.Context(), ip, a.Name)
 			controller.WriteError(w, discovery.ErrUnauthorized, err.Error())
 			return
 		}
@@ -217,7 +241,7 @@ func (r *AuthResource) Login(w http.ResponseWriter, req *http.Request) {
 		controller.WriteError(w, discovery.ErrInternal, err.Error())
 		return
 	}
-	to := &rbac.Token{TokenStr: t}
+	to := &rbacframe.TokenResponse{TokenStr: t, PasswordExpired: rbacsvc.PasswordExpired(req.Context(), a.Name)}
 	b, err := json.Marshal(to)
 	if err != nil {
 		log.Error("json err", err)
@@ -226,3 +250,234 @@ func (r *AuthResource) Login(w http.ResponseWriter, req *http.Request) {
 	}
 	controller.WriteJSON(w, b)
 }
+
+//Logout revokes the token carried by the request
+func (r *AuthResource) Logout(w http.ResponseWriter, req *http.Request) {
+	s := strings.Split(req.Header.Get(restful.HeaderAuth), " ")
+	if len(s) != 2 || rbacframe.IsAPIKey(s[1]) {
+		controller.WriteError(w, discovery.ErrUnauthorized, rbacframe.ErrInvalidHeader.Error())
+		return
+	}
+	claims, err := authr.Authenticate(req.Context(), s[1])
+	if err != nil {
+		controller.WriteError(w, discovery.ErrUnauthorized, err.Error())
+		return
+	}
+	m, ok := claims.(map[string]interface{})
+	if !ok {
+		controller.WriteError(w, discovery.ErrUnauthorized, rbacframe.ErrInvalidHeader.Error())
+		return
+	}
+	err = rbacsvc.Logout(context.TODO(), m)
+	if err != nil {
+		if err == rbacsvc.ErrNoJTI {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		log.Error("logout failed", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//RevokeSessions revokes all the tokens issued to the account
+func (r *AuthResource) RevokeSessions(w http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get(":name")
+	exist, err := dao.AccountExist(context.TODO(), name)
+	if err != nil {
+		log.Error(errorsEx.MsgGetAccountFailed, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetAccountFailed)
+		return
+	}
+	if !exist {
+		controller.WriteError(w, discovery.ErrInvalidParams, rbacsvc.ErrAccountNotExist.Error())
+		return
+	}
+	if err = rbacsvc.RevokeAccountSessions(context.TODO(), name); err != nil {
+		log.Error("revoke sessions failed", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//CreateAPIKey generates an api key of the account, the plain key is only returned once
+func (r *AuthResource) CreateAPIKey(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	key := &rbacframe.APIKey{}
+	if err = json.Unmarshal(body, key); err != nil {
+		log.Error("json err", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	created, err := rbacsvc.CreateAPIKey(context.TODO(), req.URL.Query().Get(":name"), key)
+	if err != nil {
+		switch err {
+		case rbacsvc.ErrEmptyAPIKeyName, rbacsvc.ErrInvalidExpiration, rbacsvc.ErrAPIKeyRoleExceed,
+			rbacsvc.ErrAccountNotExist:
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		default:
+			log.Error("create api key failed", err)
+			controller.WriteError(w, discovery.ErrInternal, err.Error())
+		}
+		return
+	}
+	b, err := json.Marshal(created)
+	if err != nil {
+		log.Error(errorsEx.MsgJSON, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgJSON)
+		return
+	}
+	controller.WriteJSON(w, b)
+}
+
+func (r *AuthResource) ListAPIKey(w http.ResponseWriter, req *http.Request) {
+	keys, err := rbacsvc.ListAPIKey(context.TODO(), req.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("list api keys failed", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	b, err := json.Marshal(&rbacframe.APIKeyResponse{Total: int64(len(keys)), Keys: keys})
+	if err != nil {
+		log.Error(errorsEx.MsgJSON, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgJSON)
+		return
+	}
+	controller.WriteJSON(w, b)
+}
+
+func (r *AuthResource) RevokeAPIKey(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	err := rbacsvc.RevokeAPIKey(context.TODO(), query.Get(":name"), query.Get(":id"))
+	if err != nil {
+		if err == datasource.ErrAPIKeyNotExist {
+			controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		log.Error("revoke api key failed", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//ListBanned lists the client ips and accounts banned for too many login failures
+func (r *AuthResource) ListBanned(w http.ResponseWriter, req *http.Request) {
+	banned, err := rbacsvc.BannedList(req.Context())
+	if err != nil {
+		log.Error("list banned clients failed", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	b, err := json.Marshal(&rbacframe.BannedResponse{Total: int64(len(banned)), Banned: banned})
+	if err != nil {
+		log.Error(errorsEx.MsgJSON, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgJSON)
+		return
+	}
+	controller.WriteJSON(w, b)
+}
+
+//ReleaseBanned lifts the ban of the client ip or account in query
+func (r *AuthResource) ReleaseBanned(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	ip, account := query.Get(rbacframe.BlockIP), query.Get(rbacframe.BlockAccount)
+	if len(ip) == 0 && len(account) == 0 {
+		controller.WriteError(w, discovery.ErrInvalidParams, "ip or account is required")
+		return
+	}
+	for typ, value := range map[string]string{rbacframe.BlockIP: ip, rbacframe.BlockAccount: account} {
+		if len(value) == 0 {
+			continue
+		}
+		if err := rbacsvc.Release(req.Context(), typ, value); err != nil {
+			log.Errorf(err, "release %s [%s] failed", typ, value)
+			controller.WriteError(w, discovery.ErrInternal, err.Error())
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//JWKS publishes the public keys verifying the service center tokens
+func (r *AuthResource) JWKS(w http.ResponseWriter, req *http.Request) {
+	keys := rbacsvc.Keys()
+	if keys == nil {
+		controller.WriteError(w, discovery.ErrForbidden, "rbac is disabled")
+		return
+	}
+	b, err := json.Marshal(keys.JWKS())
+	if err != nil {
+		log.Error(errorsEx.MsgJSON, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgJSON)
+		return
+	}
+	controller.WriteJSON(w, b)
+}
+
+//IDTokenLogin is the request body of the identity provider login
+type IDTokenLogin struct {
+	IDToken             string `json:"idToken"`
+	TokenExpirationTime string `json:"tokenExpirationTime,omitempty"`
+}
+
+//LoginWithIDToken exchanges an OIDC ID token for a service center token
+func (r *AuthResource) LoginWithIDToken(w http.ResponseWriter, req *http.Request) {
+	idp := rbacsvc.IdP()
+	if idp == nil {
+		controller.WriteError(w, discovery.ErrForbidden, rbacsvc.ErrIdPDisabled.Error())
+		return
+	}
+	ip := util.GetRealIP(req)
+	if rbacsvc.IsBanned(req.Context(), ip, "") {
+		log.Warn("ip is banned:" + ip)
+		controller.WriteError(w, discovery.ErrForbidden, "")
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	l := &IDTokenLogin{}
+	if err = json.Unmarshal(body, l); err != nil || len(l.IDToken) == 0 {
+		log.Error("json err", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	if l.TokenExpirationTime == "" {
+		l.TokenExpirationTime = "30m"
+	}
+	err = service.ValidateAccountLogin(&rbac.Account{TokenExpirationTime: l.TokenExpirationTime})
+	if err != nil {
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	t, err := idp.Login(context.TODO(), l.IDToken, l.TokenExpirationTime)
+	if err != nil {
+		if err == rbacsvc.ErrInvalidIDToken || err == rbacsvc.ErrNoRoleMapped {
+			log.Error("not authorized", err)
+			rbacsvc.CountFailure(req.Context(), ip, "")
+			controller.WriteError(w, discovery.ErrUnauthorized, err.Error())
+			return
+		}
+		log.Error("can not sign token", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	b, err := json.Marshal(&rbac.Token{TokenStr: t})
+	if err != nil {
+		log.Error("json err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteJSON(w, b)
+}
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v4
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	pkgquota "github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/apache/servicecomb-service-center/server/service"
+	"github.com/go-chassis/cari/discovery"
+)
+
+type QuotaResource struct {
+}
+
+//URLPatterns define http pattern
+func (r *QuotaResource) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodGet, Path: "/v4/admin/quotas/:domain/:project", Func: r.GetQuotaLimits},
+		{Method: http.MethodPut, Path: "/v4/admin/quotas/:domain/:project", Func: r.PutQuotaLimits},
+		{Method: http.MethodDelete, Path: "/v4/admin/quotas/:domain/:project", Func: r.DeleteQuotaLimits},
+		{Method: http.MethodGet, Path: "/v4/admin/quotas/:domain/:project/usage", Func: r.GetQuotaUsage},
+	}
+}
+
+//GetQuotaLimits returns the effective limits of every resource type in the domain project
+func (r *QuotaResource) GetQuotaLimits(w http.ResponseWriter, req *http.Request) {
+	domain, project := r.domainProject(req)
+	ctx := util.SetDomainProject(req.Context(), domain, project)
+	limits := make(map[string]int64, len(quota.Types))
+	for _, t := range quota.Types {
+		limits[t.Name()] = quota.GetQuota(ctx, t)
+	}
+	controller.WriteResponse(w, req, nil, &pkgquota.Limits{
+		Domain:  domain,
+		Project: project,
+		Limits:  limits,
+	})
+}
+
+//PutQuotaLimits overrides the limits of the domain project,
+//the resources absent in body use the default limits
+func (r *QuotaResource) PutQuotaLimits(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	l := &pkgquota.Limits{}
+	if err = json.Unmarshal(body, l); err != nil {
+		log.Error("json err", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	if err = service.ValidateQuotaLimits(l); err != nil {
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	l.Domain, l.Project = r.domainProject(req)
+	l.UpdateTime = strconv.FormatInt(time.Now().Unix(), 10)
+	if err = datasource.Instance().PutQuotaLimits(req.Context(), l); err != nil {
+		log.Errorf(err, "put quota limits of %s/%s failed", l.Domain, l.Project)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	quota.Refresh(req.Context())
+	controller.WriteResponse(w, req, nil, l)
+}
+
+//DeleteQuotaLimits resets the limits of the domain project to the default limits
+func (r *QuotaResource) DeleteQuotaLimits(w http.ResponseWriter, req *http.Request) {
+	domain, project := r.domainProject(req)
+	if err := datasource.Instance().DeleteQuotaLimits(req.Context(), domain, project); err != nil {
+		log.Errorf(err, "delete quota limits of %s/%s failed", domain, project)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	quota.Refresh(req.Context())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//GetQuotaUsage reports the limit and consumption of every resource type in the domain project,
+//the per service resources are counted for the serviceId in query
+func (r *QuotaResource) GetQuotaUsage(w http.ResponseWriter, req *http.Request) {
+	domain, project := r.domainProject(req)
+	ctx := util.SetDomainProject(req.Context(), domain, project)
+	usage, err := quota.Usage(ctx, req.URL.Query().Get("serviceId"))
+	if err != nil {
+		log.Errorf(err, "get quota usage of %s/%s failed", domain, project)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	controller.WriteResponse(w, req, nil, &pkgquota.UsageResponse{
+		Domain:  domain,
+		Project: project,
+		Usage:   usage,
+	})
+}
+
+func (r *QuotaResource) domainProject(req *http.Request) (string, string) {
+	query := req.URL.Query()
+	return query.Get(":domain"), query.Get(":project")
+}
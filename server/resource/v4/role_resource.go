@@ -20,7 +20,7 @@ package v4
 import (
 	"context"
 	"encoding/json"
-	"github.com/go-chassis/cari/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"io/ioutil"
 	"net/http"
 
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
 	"github.com/go-chassis/cari/discovery"
 )
@@ -57,7 +58,7 @@ func (r *RoleResource) GetRolePermission(w http.ResponseWriter, req *http.Reques
 		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetRoleFailed)
 		return
 	}
-	resp := &rbac.RoleResponse{
+	resp := &rbacframe.RoleResponse{
 		Roles: rs,
 	}
 	b, err := json.Marshal(resp)
@@ -70,14 +71,20 @@ func (r *RoleResource) GetRolePermission(w http.ResponseWriter, req *http.Reques
 }
 
 //roleParse parse the role info from the request body
-func (r *RoleResource) roleParse(body []byte) (*rbac.Role, error) {
-	role := &rbac.Role{}
+func (r *RoleResource) roleParse(body []byte) (*rbacframe.Role, error) {
+	role := &rbacframe.Role{}
 	err := json.Unmarshal(body, role)
 	if err != nil {
 		log.Error("json err", err)
 		return nil, err
 	}
 	// TODO: validate role
+	for _, perm := range role.Perms {
+		if err := perm.CheckLabels(); err != nil {
+			log.Error("role perms err", err)
+			return nil, err
+		}
+	}
 	return role, nil
 }
 
@@ -94,12 +101,16 @@ func (r *RoleResource) CreateRolePermission(w http.ResponseWriter, req *http.Req
 		controller.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
 		return
 	}
-	err = dao.CreateRole(context.TODO(), role)
+	err = rbacsvc.CreateRole(context.TODO(), role)
 	if err != nil {
 		if err == datasource.ErrRoleDuplicated {
 			controller.WriteError(w, ErrConflictRole, "")
 			return
 		}
+		if errQuota, ok := err.(*discovery.Error); ok {
+			controller.WriteError(w, errQuota.Code, errQuota.Detail)
+			return
+		}
 		log.Error(errorsEx.MsgOperateRoleFailed, err)
 		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgOperateRoleFailed)
 		return
@@ -125,6 +136,12 @@ func (r *RoleResource) UpdateRolePermission(w http.ResponseWriter, req *http.Req
 		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgOperateRoleFailed)
 		return
 	}
+	if err = rbacsvc.RevokeRoleSessions(context.TODO(), role.Name); err != nil {
+		log.Errorf(err, "revoke sessions of role [%s] failed", role.Name)
+	}
+	if err = rbacsvc.RevokeRoleAPIKeys(context.TODO(), role.Name); err != nil {
+		log.Errorf(err, "revoke api keys of role [%s] failed", role.Name)
+	}
 }
 
 //GetRole get the role info according to role name
@@ -145,10 +162,17 @@ func (r *RoleResource) GetRole(w http.ResponseWriter, req *http.Request) {
 
 //DeleteRole delete the role info by role name
 func (r *RoleResource) DeleteRole(w http.ResponseWriter, req *http.Request) {
-	_, err := dao.DeleteRole(context.TODO(), req.URL.Query().Get(":roleName"))
+	name := req.URL.Query().Get(":roleName")
+	_, err := dao.DeleteRole(context.TODO(), name)
 	if err != nil {
 		log.Error(errorsEx.MsgJSON, err)
 		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgJSON)
 		return
 	}
+	if err = rbacsvc.RevokeRoleSessions(context.TODO(), name); err != nil {
+		log.Errorf(err, "revoke sessions of role [%s] failed", name)
+	}
+	if err = rbacsvc.RevokeRoleAPIKeys(context.TODO(), name); err != nil {
+		log.Errorf(err, "revoke api keys of role [%s] failed", name)
+	}
 }
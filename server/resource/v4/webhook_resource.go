@@ -0,0 +1,179 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v4
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+	"github.com/apache/servicecomb-service-center/server/rest/controller"
+	"github.com/apache/servicecomb-service-center/server/service"
+	webhooksvc "github.com/apache/servicecomb-service-center/server/webhook"
+	"github.com/go-chassis/cari/discovery"
+)
+
+type WebhookResource struct {
+}
+
+//URLPatterns define http pattern
+func (r *WebhookResource) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodPost, Path: "/v4/:project/webhooks", Func: r.CreateWebhook},
+		{Method: http.MethodGet, Path: "/v4/:project/webhooks", Func: r.ListWebhook},
+		{Method: http.MethodGet, Path: "/v4/:project/webhooks/:id", Func: r.GetWebhook},
+		{Method: http.MethodDelete, Path: "/v4/:project/webhooks/:id", Func: r.DeleteWebhook},
+		{Method: http.MethodGet, Path: "/v4/:project/webhooks/:id/deadletters", Func: r.ListDeadLetter},
+		{Method: http.MethodDelete, Path: "/v4/:project/webhooks/:id/deadletters/:letterId", Func: r.DeleteDeadLetter},
+	}
+}
+
+//CreateWebhook register a new webhook in current project
+func (r *WebhookResource) CreateWebhook(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		controller.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	hook := &webhook.Webhook{}
+	if err = json.Unmarshal(body, hook); err != nil {
+		log.Error("json err", err)
+		controller.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	if err = service.ValidateCreateWebhook(hook); err != nil {
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	ctx := req.Context()
+	hook.ID = util.GenerateUUID()
+	hook.Domain = util.ParseDomain(ctx)
+	hook.Project = util.ParseProject(ctx)
+	hook.CreateTime = strconv.FormatInt(time.Now().Unix(), 10)
+	err = datasource.Instance().CreateWebhook(ctx, hook)
+	if err != nil {
+		log.Error(errorsEx.MsgOperateWebhookFailed, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgOperateWebhookFailed)
+		return
+	}
+	r.refresh(req)
+	controller.WriteResponse(w, req, nil, &webhook.Webhook{ID: hook.ID})
+}
+
+//ListWebhook list the webhooks of current project, secrets are hidden
+func (r *WebhookResource) ListWebhook(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	hooks, err := datasource.Instance().ListWebhook(ctx, util.ParseDomainProject(ctx))
+	if err != nil {
+		log.Error(errorsEx.MsgGetWebhookFailed, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetWebhookFailed)
+		return
+	}
+	for _, hook := range hooks {
+		hook.Secret = ""
+	}
+	controller.WriteResponse(w, req, nil, &webhook.ListResponse{
+		Total:    int64(len(hooks)),
+		Webhooks: hooks,
+	})
+}
+
+//GetWebhook get the webhook by id, secret is hidden
+func (r *WebhookResource) GetWebhook(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	hook, err := datasource.Instance().GetWebhook(ctx, util.ParseDomainProject(ctx), req.URL.Query().Get(":id"))
+	if err != nil {
+		r.writeError(w, err, errorsEx.MsgGetWebhookFailed)
+		return
+	}
+	hook.Secret = ""
+	controller.WriteResponse(w, req, nil, hook)
+}
+
+//DeleteWebhook delete the webhook and it's dead letters
+func (r *WebhookResource) DeleteWebhook(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	err := datasource.Instance().DeleteWebhook(ctx, util.ParseDomainProject(ctx), req.URL.Query().Get(":id"))
+	if err != nil {
+		r.writeError(w, err, errorsEx.MsgOperateWebhookFailed)
+		return
+	}
+	r.refresh(req)
+	controller.WriteResponse(w, req, nil, nil)
+}
+
+//ListDeadLetter list the payloads failed to deliver to the webhook
+func (r *WebhookResource) ListDeadLetter(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	domainProject := util.ParseDomainProject(ctx)
+	id := req.URL.Query().Get(":id")
+	if _, err := datasource.Instance().GetWebhook(ctx, domainProject, id); err != nil {
+		r.writeError(w, err, errorsEx.MsgGetWebhookFailed)
+		return
+	}
+	letters, err := datasource.Instance().ListDeadLetter(ctx, domainProject, id)
+	if err != nil {
+		log.Error(errorsEx.MsgGetWebhookFailed, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetWebhookFailed)
+		return
+	}
+	controller.WriteResponse(w, req, nil, &webhook.DeadLetterResponse{
+		Total:       int64(len(letters)),
+		DeadLetters: letters,
+	})
+}
+
+//DeleteDeadLetter delete the dead letter after it is handled
+func (r *WebhookResource) DeleteDeadLetter(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	query := req.URL.Query()
+	err := datasource.Instance().DeleteDeadLetter(ctx, util.ParseDomainProject(ctx), query.Get(":id"), query.Get(":letterId"))
+	if err != nil {
+		log.Error(errorsEx.MsgOperateWebhookFailed, err)
+		controller.WriteError(w, discovery.ErrInternal, errorsEx.MsgOperateWebhookFailed)
+		return
+	}
+	controller.WriteResponse(w, req, nil, nil)
+}
+
+func (r *WebhookResource) writeError(w http.ResponseWriter, err error, msg string) {
+	if err == datasource.ErrWebhookNotExist {
+		controller.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	log.Error(msg, err)
+	controller.WriteError(w, discovery.ErrInternal, msg)
+}
+
+//refresh make the changes take effect immediately in current server
+func (r *WebhookResource) refresh(req *http.Request) {
+	if d := webhooksvc.Instance(); d != nil {
+		if err := d.Refresh(req.Context()); err != nil {
+			log.Error("refresh webhooks failed", err)
+		}
+	}
+}
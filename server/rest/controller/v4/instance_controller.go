@@ -80,7 +80,7 @@ func (s *MicroServiceInstanceService) RegisterInstance(w http.ResponseWriter, r
 	controller.WriteResponse(w, r, respInternal, resp)
 }
 
-//TODO 什么样的服务允许更新服务心跳，只能是本服务才可以更新自己，如何屏蔽其他服务伪造的心跳更新？
+//Heartbeat renews the instance, only the account registered it or an admin is allowed if rbac is enabled
 func (s *MicroServiceInstanceService) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	request := &pb.HeartbeatRequest{
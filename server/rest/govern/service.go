@@ -22,6 +22,7 @@ import (
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/proto"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/service"
 	pb "github.com/go-chassis/cari/discovery"
@@ -34,7 +35,22 @@ type Service struct {
 
 func (governService *Service) GetServicesInfo(ctx context.Context, in *pb.GetServicesInfoRequest) (*pb.GetServicesInfoResponse, error) {
 	ctx = util.WithCacheOnly(ctx)
-	return datasource.Instance().GetServicesInfo(ctx, in)
+	resp, err := datasource.Instance().GetServicesInfo(ctx, in)
+	if err != nil {
+		return resp, err
+	}
+	scope := rbacframe.ScopeFromContext(ctx)
+	if scope == nil {
+		return resp, nil
+	}
+	details := make([]*pb.ServiceDetail, 0, len(resp.AllServicesDetail))
+	for _, detail := range resp.AllServicesDetail {
+		if scope.MatchService(detail.MicroService) {
+			details = append(details, detail)
+		}
+	}
+	resp.AllServicesDetail = details
+	return resp, nil
 }
 
 func (governService *Service) GetServiceDetail(ctx context.Context, in *pb.GetServiceRequest) (*pb.GetServiceDetailResponse, error) {
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	roa "github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/health"
+	"github.com/apache/servicecomb-service-center/server/rest"
+)
+
+const (
+	APILive  = "/health/live"
+	APIReady = "/health/ready"
+)
+
+func init() {
+	rest.RegisterServerHandleFunc(APILive, Live)
+	rest.RegisterServerHandleFunc(APIReady, Ready)
+}
+
+// Live responds 200 if all the liveness checks are up, otherwise 503
+func Live(w http.ResponseWriter, r *http.Request) {
+	write(w, health.Live(r.Context()))
+}
+
+// Ready responds 200 if all the readiness checks are up, otherwise 503
+func Ready(w http.ResponseWriter, r *http.Request) {
+	write(w, health.Ready(r.Context()))
+}
+
+func write(w http.ResponseWriter, report *health.Report) {
+	code := http.StatusOK
+	if report.Status != health.StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(roa.HeaderContentType, roa.ContentTypeJSON)
+	w.WriteHeader(code)
+	if _, err := w.Write(b); err != nil {
+		log.Error("write health report failed", err)
+	}
+}
@@ -33,12 +33,16 @@ import (
 	"github.com/apache/servicecomb-service-center/server/command"
 	"github.com/apache/servicecomb-service-center/server/config"
 	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/heartbeat"
 	"github.com/apache/servicecomb-service-center/server/notify"
 	"github.com/apache/servicecomb-service-center/server/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/eventsink"
 	"github.com/apache/servicecomb-service-center/server/plugin/security/tlsconf"
+	"github.com/apache/servicecomb-service-center/server/probe"
 	"github.com/apache/servicecomb-service-center/server/service/gov"
 	"github.com/apache/servicecomb-service-center/server/service/rbac"
 	snf "github.com/apache/servicecomb-service-center/server/syncernotify"
+	"github.com/apache/servicecomb-service-center/server/webhook"
 )
 
 const defaultCollectPeriod = 30 * time.Second
@@ -184,10 +188,18 @@ func (s *ServiceCenterServer) startServices() {
 
 	// load server plugins
 	plugin.LoadPlugins()
+	// export events to sink
+	eventsink.Init()
 	rbac.Init()
 	if err := gov.Init(); err != nil {
 		log.Fatal("init gov failed", err)
 	}
+	// outbound webhooks
+	webhook.Init()
+	// active health checking of instances
+	probe.Init()
+	// coalesce the instance heartbeats
+	heartbeat.Init()
 	// check version
 	if config.GetRegistry().SelfRegister {
 		if err := datasource.Instance().UpgradeVersion(context.Background()); err != nil {
@@ -210,6 +222,10 @@ func (s *ServiceCenterServer) Stop() {
 		s.apiService.Stop()
 	}
 
+	if a := heartbeat.Instance(); a != nil {
+		a.Stop()
+	}
+
 	if s.notifyService != nil {
 		s.notifyService.Stop()
 	}
@@ -218,6 +234,22 @@ func (s *ServiceCenterServer) Stop() {
 		s.syncerNotifyService.Stop()
 	}
 
+	if d := webhook.Instance(); d != nil {
+		d.Stop()
+	}
+
+	if r := rbac.Revocations(); r != nil {
+		r.Stop()
+	}
+
+	if k := rbac.Keys(); k != nil {
+		k.Stop()
+	}
+
+	probe.Stop()
+
+	eventsink.Stop()
+
 	gopool.CloseAndWait()
 
 	log.Warnf("service center stopped")
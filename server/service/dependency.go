@@ -22,6 +22,8 @@ import (
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -33,6 +35,16 @@ func (s *MicroServiceService) AddDependenciesForMicroServices(ctx context.Contex
 		}, nil
 	}
 
+	release, errQuota := checkDependencyQuota(ctx, in.Dependencies, false)
+	defer release()
+	if errQuota != nil {
+		resp := &pb.AddDependenciesResponse{Response: pb.CreateResponseWithSCErr(errQuota)}
+		if errQuota.InternalError() {
+			return resp, errQuota
+		}
+		return resp, nil
+	}
+
 	resp, err := datasource.Instance().AddOrUpdateDependencies(ctx, in.Dependencies, false)
 	return &pb.AddDependenciesResponse{Response: resp}, err
 }
@@ -45,6 +57,16 @@ func (s *MicroServiceService) CreateDependenciesForMicroServices(ctx context.Con
 		}, nil
 	}
 
+	release, errQuota := checkDependencyQuota(ctx, in.Dependencies, true)
+	defer release()
+	if errQuota != nil {
+		resp := &pb.CreateDependenciesResponse{Response: pb.CreateResponseWithSCErr(errQuota)}
+		if errQuota.InternalError() {
+			return resp, errQuota
+		}
+		return resp, nil
+	}
+
 	resp, err := datasource.Instance().AddOrUpdateDependencies(ctx, in.Dependencies, true)
 	return &pb.CreateDependenciesResponse{Response: resp}, err
 }
@@ -73,3 +95,49 @@ func (s *MicroServiceService) GetConsumerDependencies(ctx context.Context, in *p
 
 	return datasource.Instance().SearchConsumerDependency(ctx, in)
 }
+
+// checkDependencyQuota checks the providers count of each consumer,
+// the override replaces the providers, otherwise they are appended and
+// kept reserved until release is called, the release is never nil
+func checkDependencyQuota(ctx context.Context, deps []*pb.ConsumerDependency, override bool) (release func(), err *pb.Error) {
+	var releases []func()
+	release = func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+	domainProject := util.ParseDomainProject(ctx)
+	for _, dep := range deps {
+		consumer := dep.Consumer
+		resp, errExist := datasource.Instance().ExistService(ctx, &pb.GetExistenceRequest{
+			Type:        pb.ExistenceMicroservice,
+			Environment: consumer.Environment,
+			AppId:       consumer.AppId,
+			ServiceName: consumer.ServiceName,
+			Version:     consumer.Version,
+		})
+		if errExist != nil {
+			log.Errorf(errExist, "check consumer[%s/%s/%s] dependency quota failed",
+				consumer.AppId, consumer.ServiceName, consumer.Version)
+			return release, pb.NewError(pb.ErrInternal, errExist.Error())
+		}
+		if len(resp.ServiceId) == 0 {
+			// the consumer does not exist, it is reported by datasource
+			continue
+		}
+		res := quota.NewApplyQuotaResource(quota.TypeDependency, domainProject, resp.ServiceId, int64(len(dep.Providers)))
+		if override {
+			res.Overwrite = true
+			if err = quota.Apply(ctx, res); err != nil {
+				return release, err
+			}
+			continue
+		}
+		r, errQuota := quota.Acquire(ctx, res)
+		releases = append(releases, r)
+		if errQuota != nil {
+			return release, errQuota
+		}
+	}
+	return release, nil
+}
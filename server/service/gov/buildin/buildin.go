@@ -0,0 +1,224 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
+)
+
+var rule = kie.Validator{}
+
+//Distributor persists the policies in the datasource of service center,
+//so governance works without any config server
+type Distributor struct {
+	name     string
+	notifier *notifier
+}
+
+func (d *Distributor) Create(kind, project string, spec []byte) ([]byte, error) {
+	p, err := svc.ParsePolicy(spec)
+	if err != nil {
+		return nil, err
+	}
+	err = rule.Validate(kind, p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	policies, err := datasource.Instance().ListGovPolicy(ctx, project, svc.ToSnake(kind))
+	if err != nil {
+		log.Error("list governance policies failed", err)
+		return nil, err
+	}
+	if kind == svc.KindMatchGroup && p.Name == "" {
+		p.Name = generateName(policies)
+	}
+	for _, exist := range policies {
+		if exist.Name == p.Name && exist.Selector == p.Selector {
+			return nil, svc.ErrPolicyExist
+		}
+	}
+	now := time.Now().Unix()
+	p.ID = uuid.NewV4().String()
+	p.Kind = svc.ToSnake(kind)
+	p.CreatTime = now
+	p.UpdateTime = now
+	if p.Status == "" {
+		p.Status = svc.EnableStatus
+	}
+	err = d.put(ctx, project, p)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("create %s policy %s/%s", kind, project, p.Name))
+	b, _ := json.MarshalIndent(p.ID, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Update(id, kind, project string, spec []byte) error {
+	p, err := svc.ParsePolicy(spec)
+	if err != nil {
+		return err
+	}
+	err = rule.Validate(kind, p.Spec)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	old, err := getPolicy(ctx, kind, id, project)
+	if err != nil {
+		return err
+	}
+	// like kie, only the spec and status can be changed
+	old.Spec = p.Spec
+	if p.Status != "" {
+		old.Status = p.Status
+	}
+	old.UpdateTime = time.Now().Unix()
+	err = d.put(ctx, project, old)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("update %s policy %s/%s", kind, project, old.Name))
+	return nil
+}
+
+func (d *Distributor) Delete(id, project string) error {
+	_, err := datasource.Instance().DeleteGovPolicy(context.Background(), project, id)
+	if err != nil {
+		log.Error("delete governance policy failed", err)
+		return err
+	}
+	d.notifier.Notify(project)
+	return nil
+}
+
+func (d *Distributor) Display(project, app, env string) ([]byte, error) {
+	policies, err := datasource.Instance().ListGovPolicy(context.Background(), project, "")
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(svc.GroupPolicies(policies, app, env), "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) List(kind, project, app, env string) ([]byte, error) {
+	policies, err := datasource.Instance().ListGovPolicy(context.Background(), project, svc.ToSnake(kind))
+	if err != nil {
+		return nil, err
+	}
+	r := make([]*gov.Policy, 0, len(policies))
+	for _, p := range policies {
+		if !svc.MatchPolicy(p, app, env) {
+			continue
+		}
+		p.Kind = kind
+		r = append(r, p)
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Get(kind, id, project string) ([]byte, error) {
+	p, err := getPolicy(context.Background(), kind, id, project)
+	if err != nil {
+		return nil, err
+	}
+	p.Kind = kind
+	b, _ := json.MarshalIndent(p, "", "  ")
+	return b, nil
+}
+
+//Watch waits for the revision of project to change, the revision is only re-read when
+//this instance or the datasource watch notifies a change
+func (d *Distributor) Watch(ctx context.Context, project string, revision int64) (int64, error) {
+	return d.notifier.Wait(ctx, project, revision, func() (int64, error) {
+		return datasource.Instance().GetGovRevision(context.Background(), project)
+	})
+}
+
+func (d *Distributor) Type() string {
+	return svc.ConfigDistributorBuildin
+}
+func (d *Distributor) Name() string {
+	return d.name
+}
+
+func (d *Distributor) put(ctx context.Context, project string, p *gov.Policy) error {
+	_, err := datasource.Instance().PutGovPolicy(ctx, project, p)
+	if err != nil {
+		log.Error("save governance policy failed", err)
+		return err
+	}
+	d.notifier.Notify(project)
+	return nil
+}
+
+//getPolicy returns datasource.ErrGovPolicyNotExist if the policy of id is not the kind
+func getPolicy(ctx context.Context, kind, id, project string) (*gov.Policy, error) {
+	p, err := datasource.Instance().GetGovPolicy(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Kind != svc.ToSnake(kind) {
+		return nil, datasource.ErrGovPolicyNotExist
+	}
+	return p, nil
+}
+
+func generateName(policies []*gov.Policy) string {
+	for {
+		name := kie.BusinessPrefix + uuid.NewV4().String()[:4]
+		repeat := false
+		for _, p := range policies {
+			if p.Name == name {
+				repeat = true
+				break
+			}
+		}
+		if !repeat {
+			return name
+		}
+	}
+}
+
+func new(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
+	n := newNotifier()
+	if err := notify.Center().AddSubscriber(n); err != nil {
+		log.Error("subscribe governance revision events failed", err)
+		return nil, err
+	}
+	return &Distributor{name: opts.Name, notifier: n}, nil
+}
+
+func init() {
+	svc.InstallDistributor(svc.ConfigDistributorBuildin, new)
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+	"sync"
+
+	nf "github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+//notifier wakes up the watchers of a project when the policies change,
+//the changes made by other service center instances come from the datasource watch
+type notifier struct {
+	nf.Subscriber
+	mux     sync.Mutex
+	changes map[string]chan struct{}
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		Subscriber: nf.NewSubscriber(notify.GOVERNANCE, notify.GovernanceSubject, "__GOVERNANCE_GROUP__"),
+		changes:    make(map[string]chan struct{}),
+	}
+}
+
+//OnMessage wakes up the watchers of the changed project, or all watchers if the project is unknown
+func (n *notifier) OnMessage(evt nf.Event) {
+	e, ok := evt.(*notify.GovRevisionEvent)
+	if !ok {
+		return
+	}
+	if len(e.Project) == 0 {
+		n.notifyAll()
+		return
+	}
+	n.Notify(e.Project)
+}
+
+//Notify wakes up all the watchers of project
+func (n *notifier) Notify(project string) {
+	n.mux.Lock()
+	if ch, ok := n.changes[project]; ok {
+		close(ch)
+		delete(n.changes, project)
+	}
+	n.mux.Unlock()
+}
+
+func (n *notifier) notifyAll() {
+	n.mux.Lock()
+	for project, ch := range n.changes {
+		close(ch)
+		delete(n.changes, project)
+	}
+	n.mux.Unlock()
+}
+
+func (n *notifier) changed(project string) <-chan struct{} {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	ch, ok := n.changes[project]
+	if !ok {
+		ch = make(chan struct{})
+		n.changes[project] = ch
+	}
+	return ch
+}
+
+//Wait returns once the latest revision is greater than revision,
+//or returns the latest revision when ctx is done
+func (n *notifier) Wait(ctx context.Context, project string, revision int64, latest func() (int64, error)) (int64, error) {
+	for {
+		// subscribe before reading the revision, so the changes in between are not missed
+		ch := n.changed(project)
+		rev, err := latest()
+		if err != nil {
+			return 0, err
+		}
+		if rev > revision {
+			return rev, nil
+		}
+		select {
+		case <-ctx.Done():
+			return rev, nil
+		case <-ch:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+func TestNotifier_Wait(t *testing.T) {
+	n := newNotifier()
+	var rev int64 = 1
+	latest := func() (int64, error) {
+		return atomic.LoadInt64(&rev), nil
+	}
+
+	t.Run("return immediately if the revision is old", func(t *testing.T) {
+		r, err := n.Wait(context.Background(), "p", 0, latest)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), r)
+	})
+
+	t.Run("return the latest revision when timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		r, err := n.Wait(ctx, "p", 1, latest)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), r)
+	})
+
+	t.Run("wake up when notified", func(t *testing.T) {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			atomic.StoreInt64(&rev, 2)
+			n.Notify("other")
+			n.Notify("p")
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		r, err := n.Wait(ctx, "p", 1, latest)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), r)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("wake up when the datasource notifies", func(t *testing.T) {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			atomic.StoreInt64(&rev, 3)
+			n.OnMessage(notify.NewGovRevisionEvent("p", 3))
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		r, err := n.Wait(ctx, "p", 2, latest)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), r)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("wake up all watchers if the project is unknown", func(t *testing.T) {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			atomic.StoreInt64(&rev, 4)
+			n.OnMessage(notify.NewGovRevisionEvent("", 0))
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		r, err := n.Wait(ctx, "p", 3, latest)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), r)
+		assert.NoError(t, ctx.Err())
+	})
+}
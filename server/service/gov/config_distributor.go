@@ -18,19 +18,32 @@
 package gov
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/config"
 )
 
 const (
-	ConfigDistributorKie   = "kie"
-	ConfigDistributorIstio = "istio"
-	ConfigDistributorMock  = "mock"
+	ConfigDistributorKie     = "kie"
+	ConfigDistributorIstio   = "istio"
+	ConfigDistributorMock    = "mock"
+	ConfigDistributorBuildin = "buildin"
 )
 
+var ErrWatchNotSupported = errors.New("config distributor does not support watching")
+
 type NewDistributors func(opts config.DistributorOptions) (ConfigDistributor, error)
 
-var distributors = map[string]ConfigDistributor{}
+var (
+	// primary serves the reads, the writes succeed once the primary is written
+	primary ConfigDistributor
+	// targets are the other distributors, the writes are replicated to them
+	targets []*Target
+)
 var distributorPlugins = map[string]NewDistributors{}
 
 //ConfigDistributor persist and distribute Governance policy
@@ -48,71 +61,196 @@ type ConfigDistributor interface {
 	Name() string
 }
 
+//Watcher is implemented by the ConfigDistributor which can notify the changes of policies
+type Watcher interface {
+	//Watch blocks until the revision of project policies is greater than revision or ctx is done,
+	//it returns the latest revision
+	Watch(ctx context.Context, project string, revision int64) (int64, error)
+}
+
 //InstallDistributor install a plugin to distribute and persist config
 func InstallDistributor(t string, newDistributors NewDistributors) {
 	distributorPlugins[t] = newDistributors
 }
 
 //Init create distributors according to gov config.
-//it may creates multiple distributors. and distribute policy one by one
+//it may creates multiple distributors, the writes go to the primary one first,
+//then they are replicated to the others, which are reconciled with the primary periodically
 func Init() error {
+	primary, targets = nil, nil
+	var secondaries []ConfigDistributor
 	distOptions := config.GetGov().DistOptions
 	for _, opts := range distOptions {
 		if opts.Type == "" {
 			log.Warn("empty plugin, skip")
+			continue
 		}
 		f, ok := distributorPlugins[opts.Type]
 		if !ok {
 			log.Warn("unsupported plugin " + opts.Type)
+			continue
 		}
 		cd, err := f(opts)
 		if err != nil {
 			log.Error("can not init config distributor", err)
 			return err
 		}
-		distributors[opts.Name+"::"+opts.Type] = cd
+		if opts.Primary && primary == nil {
+			primary = cd
+			continue
+		}
+		secondaries = append(secondaries, cd)
+	}
+	if primary == nil && len(secondaries) > 0 {
+		primary, secondaries = secondaries[0], secondaries[1:]
+	}
+	if primary == nil {
+		return nil
 	}
+	subscribeAlarms()
+	log.Info(fmt.Sprintf("primary config distributor is %s::%s", primary.Name(), primary.Type()))
+	for _, cd := range secondaries {
+		targets = append(targets, NewTarget(cd, primary, NewSyncOptions()))
+	}
+	startSync(targets)
 	return nil
 }
 
-func Create(kind, project string, spec []byte) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.Create(kind, project, spec)
+func Create(ctx context.Context, kind, project string, spec []byte) ([]byte, error) {
+	if primary == nil {
+		return nil, nil
+	}
+	id, err := primary.Create(kind, project, spec)
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	p, err := getPolicy(primary, kind, DecodeID(id), project)
+	if err != nil {
+		log.Error("get the created policy failed, it is replicated in reconciliation and not recorded in history", err)
+		markDrift(project)
+		return id, nil
+	}
+	record(ctx, ActionCreate, kind, project, DecodeID(id), p)
+	publish(&Operation{Action: ActionCreate, Kind: kind, Project: project, ID: DecodeID(id), Key: policyKey(p), Spec: toSpec(p)})
+	return id, nil
 }
 
-func List(kind, project, app, env string) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.List(kind, project, app, env)
+//List returns the policies of kind, except the ones of the aborted rollouts
+func List(ctx context.Context, kind, project, app, env string) ([]byte, error) {
+	return ListApplied(ctx, kind, project, app, env, "")
+}
+
+//ListApplied is List, and the policies not applied to the instance are filtered out if instanceID is not empty
+func ListApplied(ctx context.Context, kind, project, app, env, instanceID string) ([]byte, error) {
+	if primary == nil {
+		return nil, nil
+	}
+	b, err := primary.List(kind, project, app, env)
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	var policies []*gov.Policy
+	return withRollouts(ctx, project, instanceID, b, &policies, func(applied func(p *gov.Policy) bool) {
+		policies = appliedPolicies(policies, applied)
+	}), nil
 }
 
-func Display(project, app, env string) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.Display(project, app, env)
+//Display returns the policies grouped by the match groups, except the ones of the aborted rollouts
+func Display(ctx context.Context, project, app, env string) ([]byte, error) {
+	return DisplayApplied(ctx, project, app, env, "")
+}
+
+//DisplayApplied is Display, and the policies not applied to the instance are filtered out if instanceID is not empty
+func DisplayApplied(ctx context.Context, project, app, env, instanceID string) ([]byte, error) {
+	if primary == nil {
+		return nil, nil
+	}
+	b, err := primary.Display(project, app, env)
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	var data []*gov.DisplayData
+	return withRollouts(ctx, project, instanceID, b, &data, func(applied func(p *gov.Policy) bool) {
+		r := data[:0]
+		for _, d := range data {
+			// the policies marked by a match group not applied are not applied either
+			if d == nil || !applied(d.MatchGroup) {
+				continue
+			}
+			d.Policies = appliedPolicies(d.Policies, applied)
+			r = append(r, d)
+		}
+		data = r
+	}), nil
 }
 
-func Get(kind, id, project string) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.Get(kind, id, project)
+func Get(ctx context.Context, kind, id, project string) ([]byte, error) {
+	if primary == nil {
+		return nil, nil
+	}
+	b, err := primary.Get(kind, id, project)
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	p := &gov.Policy{}
+	// the policy is returned even if it is not applied, its rollout tells why
+	return withRollouts(ctx, project, "", b, p, func(applied func(p *gov.Policy) bool) {
+		applied(p)
+	}), nil
 }
 
-func Delete(id, project string) error {
-	for _, cd := range distributors {
-		return cd.Delete(id, project)
+func Delete(ctx context.Context, kind, id, project string) error {
+	if primary == nil {
+		return nil
 	}
+	// the policy is identified by the key in the other distributors
+	p, err := getPolicy(primary, kind, id, project)
+	if err != nil {
+		return err
+	}
+	baseline(ctx, kind, project, id, p)
+	err = primary.Delete(id, project)
+	if err != nil {
+		return err
+	}
+	record(ctx, ActionDelete, kind, project, id, nil)
+	deleteRollout(ctx, project, id)
+	publish(&Operation{Action: ActionDelete, Kind: kind, Project: project, ID: id, Key: policyKey(p)})
 	return nil
 }
 
-func Update(id, kind, project string, spec []byte) error {
-	for _, cd := range distributors {
-		return cd.Update(id, kind, project, spec)
+func Update(ctx context.Context, id, kind, project string, spec []byte) error {
+	if primary == nil {
+		return nil
+	}
+	return update(ctx, ActionUpdate, id, kind, project, spec)
+}
+
+func update(ctx context.Context, action, id, kind, project string, spec []byte) error {
+	if before, err := getPolicy(primary, kind, id, project); err == nil {
+		baseline(ctx, kind, project, id, before)
+	}
+	err := primary.Update(id, kind, project, spec)
+	if err != nil {
+		return err
+	}
+	p, err := getPolicy(primary, kind, id, project)
+	if err != nil {
+		log.Error("get the updated policy failed, it is replicated in reconciliation and not recorded in history", err)
+		markDrift(project)
+		return nil
 	}
+	publish(&Operation{Action: ActionUpdate, Kind: kind, Project: project, ID: id, Key: policyKey(p), Spec: toSpec(p)})
+	record(ctx, action, kind, project, id, p)
 	return nil
 }
+
+//Watch waits for the changes of project policies, it returns ErrWatchNotSupported
+//if the distributor can not notify the changes
+func Watch(ctx context.Context, project string, revision int64) (int64, error) {
+	w, ok := primary.(Watcher)
+	if !ok {
+		return 0, ErrWatchNotSupported
+	}
+	return w.Watch(ctx, project, revision)
+}
@@ -18,6 +18,7 @@
 package gov_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -60,7 +61,7 @@ func TestCreate(t *testing.T) {
 		},
 		Spec: &gov.LBSpec{RetryNext: 3, MarkerName: "traffic2adminAPI"},
 	}, "", "  ")
-	res, err := svc.Create(MockKind, Project, b)
+	res, err := svc.Create(context.Background(), MockKind, Project, b)
 	id = string(res)
 	assert.NoError(t, err)
 }
@@ -72,7 +73,7 @@ func TestUpdate(t *testing.T) {
 		},
 		Spec: &gov.LBSpec{RetryNext: 3, MarkerName: "traffic2adminAPI"},
 	}, "", "  ")
-	err := svc.Update(id, MockKind, Project, b)
+	err := svc.Update(context.Background(), id, MockKind, Project, b)
 	assert.NoError(t, err)
 }
 
@@ -82,11 +83,11 @@ func TestDisplay(t *testing.T) {
 			Name: "Traffic2adminAPI",
 		},
 	}, "", "  ")
-	res, err := svc.Create(MatchGroup, Project, b)
+	res, err := svc.Create(context.Background(), MatchGroup, Project, b)
 	id = string(res)
 	assert.NoError(t, err)
 	policies := &[]*gov.DisplayData{}
-	res, err = svc.Display(Project, MockApp, MockEnv)
+	res, err = svc.Display(context.Background(), Project, MockApp, MockEnv)
 	assert.NoError(t, err)
 	err = json.Unmarshal(res, policies)
 	assert.NoError(t, err)
@@ -95,7 +96,7 @@ func TestDisplay(t *testing.T) {
 
 func TestList(t *testing.T) {
 	policies := &[]*gov.Policy{}
-	res, err := svc.List(MockKind, Project, MockApp, MockEnv)
+	res, err := svc.List(context.Background(), MockKind, Project, MockApp, MockEnv)
 	assert.NoError(t, err)
 	err = json.Unmarshal(res, policies)
 	assert.NoError(t, err)
@@ -104,7 +105,7 @@ func TestList(t *testing.T) {
 
 func TestGet(t *testing.T) {
 	policy := &gov.Policy{}
-	res, err := svc.Get(MockKind, id, Project)
+	res, err := svc.Get(context.Background(), MockKind, id, Project)
 	assert.NoError(t, err)
 	err = json.Unmarshal(res, policy)
 	assert.NoError(t, err)
@@ -112,8 +113,8 @@ func TestGet(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	err := svc.Delete(id, Project)
+	err := svc.Delete(context.Background(), MatchGroup, id, Project)
 	assert.NoError(t, err)
-	res, _ := svc.Get(MockKind, id, Project)
+	res, _ := svc.Get(context.Background(), MockKind, id, Project)
 	assert.Nil(t, res)
 }
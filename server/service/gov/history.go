@@ -0,0 +1,206 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+const (
+	ActionRollback = "rollback"
+	// ActionBaseline is the revision of the policy changed first since the histories are recorded
+	ActionBaseline = "baseline"
+	// recordRetries is the times to retry recording when the revision is taken by a concurrent change
+	recordRetries = 3
+)
+
+var (
+	ErrHistoryNotExist     = errors.New("the revision of the policy history does not exist")
+	ErrRollbackToDeleted   = errors.New("can not rollback to the revision the policy is deleted")
+	ErrHistoryNotSupported = errors.New("the policy history needs the datasource")
+	ErrHistoryKindMismatch = errors.New("the revision of the policy history is not the kind")
+)
+
+//History returns the histories of the policy in the order of revision
+func History(ctx context.Context, project, id string) ([]*gov.History, error) {
+	if datasource.Instance() == nil {
+		return nil, ErrHistoryNotSupported
+	}
+	return datasource.Instance().ListGovHistory(ctx, project, id)
+}
+
+//Rollback updates the policy to the one of the revision in history,
+//the rollback itself is recorded as a new revision
+func Rollback(ctx context.Context, kind, project, id string, revision int64) error {
+	if primary == nil {
+		return nil
+	}
+	histories, err := History(ctx, project, id)
+	if err != nil {
+		return err
+	}
+	for _, h := range histories {
+		if h.Revision != revision {
+			continue
+		}
+		if len(h.Kind) > 0 && h.Kind != kind {
+			return ErrHistoryKindMismatch
+		}
+		if h.Policy == nil {
+			return ErrRollbackToDeleted
+		}
+		return update(ctx, ActionRollback, id, kind, project, toSpec(h.Policy))
+	}
+	return ErrHistoryNotExist
+}
+
+//record appends the history of a policy change, p is the policy after the change, nil if it is deleted.
+//the change is done already, so recording is best effort, and it is skipped without the datasource
+func record(ctx context.Context, action, kind, project, id string, p *gov.Policy) {
+	if datasource.Instance() == nil {
+		return
+	}
+	for i := 0; i < recordRetries; i++ {
+		histories, err := History(ctx, project, id)
+		if err != nil {
+			log.Error(fmt.Sprintf("list the histories of policy %s failed", id), err)
+			return
+		}
+		h := &gov.History{
+			ID:        id,
+			Kind:      kind,
+			Revision:  1,
+			Action:    action,
+			Author:    rbacframe.OwnerFromContext(ctx),
+			Timestamp: time.Now().Unix(),
+			Policy:    p,
+		}
+		var last *gov.Policy
+		if n := len(histories); n > 0 {
+			h.Revision = histories[n-1].Revision + 1
+			last = histories[n-1].Policy
+			if len(h.Kind) == 0 {
+				h.Kind = histories[n-1].Kind
+			}
+		}
+		h.Diff = Diff(last, p)
+		err = datasource.Instance().AddGovHistory(ctx, project, h)
+		if err == nil {
+			return
+		}
+		if err != datasource.ErrGovHistoryConflict {
+			log.Error(fmt.Sprintf("record the %s history of policy %s failed", action, id), err)
+			return
+		}
+	}
+	log.Warn(fmt.Sprintf("record the %s history of policy %s failed, too many concurrent changes", action, id))
+}
+
+//baseline records p, the policy before a change, as the first revision if the policy has no history,
+//so the policies created before recording histories can be rolled back to the original
+func baseline(ctx context.Context, kind, project, id string, p *gov.Policy) {
+	if datasource.Instance() == nil {
+		return
+	}
+	histories, err := History(ctx, project, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("list the histories of policy %s failed", id), err)
+		return
+	}
+	if len(histories) > 0 {
+		return
+	}
+	timestamp := p.UpdateTime
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	err = datasource.Instance().AddGovHistory(ctx, project, &gov.History{
+		ID:        id,
+		Kind:      kind,
+		Revision:  1,
+		Action:    ActionBaseline,
+		Timestamp: timestamp,
+		Policy:    p,
+		Diff:      Diff(nil, p),
+	})
+	// the conflict means the baseline or the other change is recorded concurrently
+	if err != nil && err != datasource.ErrGovHistoryConflict {
+		log.Error(fmt.Sprintf("record the baseline history of policy %s failed", id), err)
+	}
+}
+
+//Diff returns the changed fields from before to after, the fields maintained by distributors,
+//like id and update time, are ignored
+func Diff(before, after *gov.Policy) []*gov.Change {
+	b, a := flatten(before), flatten(after)
+	var changes []*gov.Change
+	for path, v := range a {
+		if old, ok := b[path]; !ok || !reflect.DeepEqual(old, v) {
+			changes = append(changes, &gov.Change{Path: path, Old: b[path], New: v})
+		}
+	}
+	for path, v := range b {
+		if _, ok := a[path]; !ok {
+			changes = append(changes, &gov.Change{Path: path, Old: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+//flatten returns the fields of the policy keyed by json path, the arrays are compared as a whole
+func flatten(p *gov.Policy) map[string]interface{} {
+	m := make(map[string]interface{})
+	if p == nil || p.GovernancePolicy == nil {
+		return m
+	}
+	var v interface{}
+	if err := json.Unmarshal(toSpec(p), &v); err != nil {
+		return m
+	}
+	flattenValue("", v, m)
+	return m
+}
+
+func flattenValue(prefix string, v interface{}, m map[string]interface{}) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		m[prefix] = v
+		return
+	}
+	for k, child := range obj {
+		path := k
+		if len(prefix) > 0 {
+			path = prefix + "." + k
+		}
+		flattenValue(path, child, m)
+	}
+}
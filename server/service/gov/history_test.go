@@ -0,0 +1,152 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+//historyStore is a datasource keeps the histories in memory, the other methods are not implemented
+type historyStore struct {
+	datasource.DataSource
+	mux       sync.Mutex
+	histories []*gov.History
+}
+
+func (s *historyStore) AddGovHistory(ctx context.Context, project string, h *gov.History) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, old := range s.histories {
+		if old.ID == h.ID && old.Revision == h.Revision {
+			return datasource.ErrGovHistoryConflict
+		}
+	}
+	s.histories = append(s.histories, h)
+	return nil
+}
+
+func (s *historyStore) ListGovHistory(ctx context.Context, project, id string) ([]*gov.History, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var r []*gov.History
+	for _, h := range s.histories {
+		if h.ID == id {
+			r = append(r, h)
+		}
+	}
+	return r, nil
+}
+
+//withDatasource runs f with ds as the datasource instance and m as the primary
+func withDatasource(t *testing.T, ds datasource.DataSource, m ConfigDistributor, f func()) {
+	datasource.Install("gov-test", func(opts datasource.Options) (datasource.DataSource, error) {
+		return ds, nil
+	})
+	assert.NoError(t, datasource.Init(datasource.Options{Kind: "gov-test"}))
+	old := primary
+	primary = m
+	defer func() {
+		primary = old
+		ds = nil
+		assert.NoError(t, datasource.Init(datasource.Options{Kind: "gov-test"}))
+	}()
+	f()
+}
+
+func TestDiff(t *testing.T) {
+	newPolicy := func(rate, burst int, status string) *gov.Policy {
+		return &gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{ID: "id", Name: "limiter", Status: status, UpdateTime: int64(rate)},
+			Spec:             &gov.LimiterSpec{MarkerName: "scene", Rate: rate, Burst: burst},
+		}
+	}
+
+	t.Run("create should add all the fields", func(t *testing.T) {
+		changes := Diff(nil, newPolicy(10, 0, "enabled"))
+		var paths []string
+		for _, c := range changes {
+			assert.Nil(t, c.Old)
+			paths = append(paths, c.Path)
+		}
+		assert.Equal(t, []string{"name", "selector", "spec.burst", "spec.match", "spec.rate", "status"}, paths)
+	})
+
+	t.Run("update should return the changed fields only", func(t *testing.T) {
+		changes := Diff(newPolicy(10, 0, "enabled"), newPolicy(1, 20, "enabled"))
+		assert.Equal(t, []*gov.Change{
+			{Path: "spec.burst", Old: float64(0), New: float64(20)},
+			{Path: "spec.rate", Old: float64(10), New: float64(1)},
+		}, changes)
+	})
+
+	t.Run("delete should remove all the fields", func(t *testing.T) {
+		changes := Diff(newPolicy(10, 0, "enabled"), nil)
+		assert.Equal(t, 6, len(changes))
+		for _, c := range changes {
+			assert.Nil(t, c.New)
+		}
+	})
+}
+
+func TestRollback(t *testing.T) {
+	m := newMemory("primary")
+	ctx := context.Background()
+	// the policy is created before recording histories
+	b, err := m.Create("retry", "default", newSpec("a", 1))
+	assert.NoError(t, err)
+	id := DecodeID(b)
+	spec := func() interface{} {
+		p, err := getPolicy(m, "retry", id, "default")
+		assert.NoError(t, err)
+		return p.Spec
+	}
+
+	withDatasource(t, &historyStore{}, m, func() {
+		assert.NoError(t, Update(ctx, id, "retry", "default", newSpec("a", 2)))
+		histories, err := History(ctx, "default", id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(histories))
+		assert.Equal(t, ActionBaseline, histories[0].Action)
+		assert.Equal(t, ActionUpdate, histories[1].Action)
+
+		t.Run("rollback to the baseline", func(t *testing.T) {
+			assert.NoError(t, Rollback(ctx, "retry", "default", id, 1))
+			assert.Equal(t, map[string]interface{}{"retryNext": float64(1)}, spec())
+			histories, err := History(ctx, "default", id)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, len(histories))
+			assert.Equal(t, ActionRollback, histories[2].Action)
+			assert.Equal(t, []*gov.Change{{Path: "spec.retryNext", Old: float64(2), New: float64(1)}}, histories[2].Diff)
+		})
+
+		t.Run("reject the revision of the other kind", func(t *testing.T) {
+			assert.Equal(t, ErrHistoryKindMismatch, Rollback(ctx, "rate-limiting", "default", id, 2))
+			assert.Equal(t, map[string]interface{}{"retryNext": float64(1)}, spec())
+		})
+
+		t.Run("reject the revision not exist", func(t *testing.T) {
+			assert.Equal(t, ErrHistoryNotExist, Rollback(ctx, "retry", "default", id, 10))
+		})
+	})
+}
@@ -0,0 +1,369 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
+)
+
+const (
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	ManagedBy      = "servicecomb-service-center"
+	LabelKind      = "servicecomb.io/gov-kind"
+	DataPolicy     = "policy"
+)
+
+var rule = kie.Validator{}
+
+var kinds = map[schema.GroupVersionResource]string{
+	VirtualServices:  "VirtualService",
+	DestinationRules: "DestinationRule",
+	EnvoyFilters:     "EnvoyFilter",
+}
+
+//Distributor converts the policies to istio resources, the policies of a project
+//are kept in the ConfigMaps of the namespace named by the project
+type Distributor struct {
+	name   string
+	client dynamic.Interface
+	// reconciliations of the namespaces are serialized
+	mux sync.Mutex
+}
+
+func (d *Distributor) Create(kind, project string, spec []byte) ([]byte, error) {
+	p, err := svc.ParsePolicy(spec)
+	if err != nil {
+		return nil, err
+	}
+	err = rule.Validate(kind, p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	err = renderable(kind, p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if p.Selector.App == "" {
+		return nil, kie.NewErrIllegalItem("selector.app is required by istio", p.Selector)
+	}
+	policies, err := d.list(project, svc.ToSnake(kind))
+	if err != nil {
+		return nil, err
+	}
+	if kind == svc.KindMatchGroup && p.Name == "" {
+		p.Name = kie.BusinessPrefix + uuid.NewV4().String()[:8]
+	}
+	for _, exist := range policies {
+		if exist.Name == p.Name && exist.Selector == p.Selector {
+			return nil, svc.ErrPolicyExist
+		}
+	}
+	now := time.Now().Unix()
+	p.ID = uuid.NewV4().String()
+	p.Kind = svc.ToSnake(kind)
+	p.CreatTime = now
+	p.UpdateTime = now
+	if p.Status == "" {
+		p.Status = svc.EnableStatus
+	}
+	obj, err := newPolicyObject(project, p)
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.client.Resource(ConfigMaps).Namespace(project).Create(obj, metav1.CreateOptions{})
+	if err != nil {
+		log.Error("create governance policy failed", err)
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("create %s policy %s/%s", kind, project, p.Name))
+	err = d.Reconcile(project)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(p.ID, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Update(id, kind, project string, spec []byte) error {
+	p, err := svc.ParsePolicy(spec)
+	if err != nil {
+		return err
+	}
+	err = rule.Validate(kind, p.Spec)
+	if err != nil {
+		return err
+	}
+	err = renderable(kind, p.Spec)
+	if err != nil {
+		return err
+	}
+	old, resourceVersion, err := d.get(kind, project, id)
+	if err != nil {
+		return err
+	}
+	// like kie, only the spec and status can be changed
+	old.Spec = p.Spec
+	if p.Status != "" {
+		old.Status = p.Status
+	}
+	old.UpdateTime = time.Now().Unix()
+	obj, err := newPolicyObject(project, old)
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(resourceVersion)
+	_, err = d.client.Resource(ConfigMaps).Namespace(project).Update(obj, metav1.UpdateOptions{})
+	if err != nil {
+		log.Error("update governance policy failed", err)
+		return err
+	}
+	log.Info(fmt.Sprintf("update %s policy %s/%s", kind, project, old.Name))
+	return d.Reconcile(project)
+}
+
+func (d *Distributor) Delete(id, project string) error {
+	err := d.client.Resource(ConfigMaps).Namespace(project).Delete(policyObjectName(id), &metav1.DeleteOptions{})
+	if k8serrors.IsNotFound(err) {
+		return svc.ErrPolicyNotExist
+	}
+	if err != nil {
+		log.Error("delete governance policy failed", err)
+		return err
+	}
+	return d.Reconcile(project)
+}
+
+func (d *Distributor) Display(project, app, env string) ([]byte, error) {
+	policies, err := d.list(project, "")
+	if err != nil {
+		return nil, err
+	}
+	r := svc.GroupPolicies(policies, app, env)
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) List(kind, project, app, env string) ([]byte, error) {
+	policies, err := d.list(project, svc.ToSnake(kind))
+	if err != nil {
+		return nil, err
+	}
+	r := make([]*gov.Policy, 0, len(policies))
+	for _, p := range policies {
+		if !svc.MatchPolicy(p, app, env) {
+			continue
+		}
+		p.Kind = kind
+		r = append(r, p)
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Get(kind, id, project string) ([]byte, error) {
+	p, _, err := d.get(kind, project, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Kind = kind
+	b, _ := json.MarshalIndent(p, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Type() string {
+	return svc.ConfigDistributorIstio
+}
+func (d *Distributor) Name() string {
+	return d.name
+}
+
+//Supports returns false if istio has no equivalent of the kind, the policies of it are never replicated to istio
+func (d *Distributor) Supports(kind string) bool {
+	return !unsupportedKinds[kind]
+}
+
+//Reconcile makes the istio resources in the namespace of project consistent with the policies,
+//it creates the missing resources, updates the changed ones and deletes the ones of the removed policies
+func (d *Distributor) Reconcile(project string) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	policies, err := d.list(project, "")
+	if err != nil {
+		return err
+	}
+	desired := make(map[string][]*unstructured.Unstructured)
+	for _, obj := range Render(project, policies) {
+		desired[obj.GetKind()] = append(desired[obj.GetKind()], obj)
+	}
+	for _, gvr := range Resources {
+		err = d.apply(project, gvr, desired[kinds[gvr]])
+		if err != nil {
+			log.Error(fmt.Sprintf("reconcile %s of namespace %s failed", gvr.Resource, project), err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Distributor) apply(namespace string, gvr schema.GroupVersionResource, desired []*unstructured.Unstructured) error {
+	rc := d.client.Resource(gvr).Namespace(namespace)
+	list, err := rc.List(metav1.ListOptions{LabelSelector: LabelManagedBy + "=" + ManagedBy})
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		current[list.Items[i].GetName()] = &list.Items[i]
+	}
+	for _, obj := range desired {
+		old, ok := current[obj.GetName()]
+		delete(current, obj.GetName())
+		if !ok {
+			_, err = rc.Create(obj, metav1.CreateOptions{})
+		} else if !reflect.DeepEqual(old.Object["spec"], obj.Object["spec"]) {
+			obj.SetResourceVersion(old.GetResourceVersion())
+			_, err = rc.Update(obj, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for name := range current {
+		err = rc.Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+//get returns svc.ErrPolicyNotExist if the policy of id is not the kind
+func (d *Distributor) get(kind, project, id string) (*gov.Policy, string, error) {
+	obj, err := d.client.Resource(ConfigMaps).Namespace(project).Get(policyObjectName(id), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, "", svc.ErrPolicyNotExist
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	p, err := toPolicy(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	if p.Kind != svc.ToSnake(kind) {
+		return nil, "", svc.ErrPolicyNotExist
+	}
+	return p, obj.GetResourceVersion(), nil
+}
+
+func (d *Distributor) list(project, kind string) ([]*gov.Policy, error) {
+	selector := LabelManagedBy + "=" + ManagedBy + "," + LabelKind
+	if len(kind) > 0 {
+		selector += "=" + kind
+	}
+	list, err := d.client.Resource(ConfigMaps).Namespace(project).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		log.Error("list governance policies failed", err)
+		return nil, err
+	}
+	policies := make([]*gov.Policy, 0, len(list.Items))
+	for i := range list.Items {
+		p, err := toPolicy(&list.Items[i])
+		if err != nil {
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func newPolicyObject(namespace string, p *gov.Policy) (*unstructured.Unstructured, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace(namespace)
+	obj.SetName(policyObjectName(p.ID))
+	obj.SetLabels(map[string]string{LabelManagedBy: ManagedBy, LabelKind: p.Kind})
+	obj.Object["data"] = map[string]interface{}{DataPolicy: string(b)}
+	return obj, nil
+}
+
+func policyObjectName(id string) string {
+	return NamePrefix + "policy-" + id
+}
+
+func toPolicy(obj *unstructured.Unstructured) (*gov.Policy, error) {
+	data, _, _ := unstructured.NestedString(obj.Object, "data", DataPolicy)
+	p, err := svc.ParsePolicy([]byte(data))
+	if err != nil {
+		log.Error("governance policy "+obj.GetName()+" format invalid", err)
+		return nil, err
+	}
+	return p, nil
+}
+
+//NewDistributor returns the istio distributor writes the resources through client
+func NewDistributor(name string, client dynamic.Interface) *Distributor {
+	return &Distributor{name: name, client: client}
+}
+
+func new(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
+	// the endpoint is the kubeconfig path, service center must be deployed in the cluster if it is empty
+	kubeConfigPath := opts.Endpoint
+	if len(kubeConfigPath) == 0 {
+		kubeConfigPath = os.Getenv("KUBERNETES_CONFIG_PATH")
+	}
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	if err != nil {
+		log.Error("load kubernetes config failed", err)
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		log.Error("create kubernetes client failed", err)
+		return nil, err
+	}
+	return NewDistributor(opts.Name, client), nil
+}
+
+func init() {
+	svc.InstallDistributor(svc.ConfigDistributorIstio, new)
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/istio"
+	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+const project = "default"
+
+func newPolicy(name string, spec interface{}) []byte {
+	b, _ := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     name,
+			Selector: gov.Selector{App: "payment", Environment: "production"},
+		},
+		Spec: spec,
+	})
+	return b
+}
+
+func listNames(t *testing.T, client *fake.FakeDynamicClient, gvr schema.GroupVersionResource) []string {
+	list, err := client.Resource(gvr).Namespace(project).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return names
+}
+
+func getSpec(t *testing.T, client *fake.FakeDynamicClient, gvr schema.GroupVersionResource, name string) map[string]interface{} {
+	obj, err := client.Resource(gvr).Namespace(project).Get(name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	return spec
+}
+
+func TestDistributor(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	d := istio.NewDistributor("istio", client)
+	const name = "servicecomb-gov-payment"
+	var retryID string
+
+	t.Run("create policies should generate istio resources", func(t *testing.T) {
+		// the EnvoyFilter generated by the former versions
+		filter := &unstructured.Unstructured{}
+		filter.SetAPIVersion(istio.APIVersion)
+		filter.SetKind("EnvoyFilter")
+		filter.SetName("servicecomb-gov-payment-production-scene-pay")
+		filter.SetLabels(map[string]string{istio.LabelManagedBy: istio.ManagedBy})
+		_, err := client.Resource(istio.EnvoyFilters).Namespace(project).Create(filter, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		_, err = d.Create("match-group", project, newPolicy("scene-pay", map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{
+					"name":    "pay",
+					"apiPath": map[string]interface{}{"prefix": "/pay"},
+					"methods": []interface{}{"GET", "POST"},
+				},
+			},
+		}))
+		assert.NoError(t, err)
+		id, err := d.Create("retry", project, newPolicy("scene-pay", &gov.LBSpec{MarkerName: "scene-pay", RetrySame: 1, RetryNext: 2}))
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(id, &retryID))
+
+		assert.Equal(t, []string{name}, listNames(t, client, istio.VirtualServices))
+		assert.Equal(t, []string{name}, listNames(t, client, istio.DestinationRules))
+		assert.Empty(t, listNames(t, client, istio.EnvoyFilters))
+
+		spec := getSpec(t, client, istio.VirtualServices, name)
+		routes, _, _ := unstructured.NestedSlice(spec, "http")
+		assert.Equal(t, 2, len(routes))
+		matches, _, _ := unstructured.NestedSlice(routes[0].(map[string]interface{}), "match")
+		assert.Equal(t, 2, len(matches))
+		attempts, _, _ := unstructured.NestedInt64(routes[0].(map[string]interface{}), "retries", "attempts")
+		assert.Equal(t, int64(3), attempts)
+
+		_, err = d.Create("retry", project, newPolicy("scene-pay", &gov.LBSpec{MarkerName: "scene-pay"}))
+		assert.Equal(t, svc.ErrPolicyExist, err)
+	})
+
+	t.Run("list and display policies", func(t *testing.T) {
+		b, err := d.List("retry", project, "payment", "production")
+		assert.NoError(t, err)
+		var policies []*gov.Policy
+		assert.NoError(t, json.Unmarshal(b, &policies))
+		assert.Equal(t, 1, len(policies))
+		assert.Equal(t, retryID, policies[0].ID)
+
+		b, err = d.Display(project, "", "all")
+		assert.NoError(t, err)
+		var data []*gov.DisplayData
+		assert.NoError(t, json.Unmarshal(b, &data))
+		assert.Equal(t, 1, len(data))
+		assert.Equal(t, "scene-pay", data[0].MatchGroup.Name)
+		assert.Equal(t, 1, len(data[0].Policies))
+	})
+
+	t.Run("update policies should reconcile the resources", func(t *testing.T) {
+		err := d.Update(retryID, "retry", project, newPolicy("scene-pay", &gov.LBSpec{MarkerName: "scene-pay", RetrySame: 1}))
+		assert.NoError(t, err)
+		assert.Empty(t, listNames(t, client, istio.DestinationRules))
+		spec := getSpec(t, client, istio.VirtualServices, name)
+		routes, _, _ := unstructured.NestedSlice(spec, "http")
+		attempts, _, _ := unstructured.NestedInt64(routes[0].(map[string]interface{}), "retries", "attempts")
+		assert.Equal(t, int64(1), attempts)
+	})
+
+	t.Run("reject the policies can not be rendered", func(t *testing.T) {
+		_, err := d.Create("circuit-breaker", project, newPolicy("scene-pay", &gov.CircuitBreakerSpec{
+			MarkerName: "scene-pay", FailureRateThreshold: 50}))
+		assert.IsType(t, &kie.ErrIllegalItem{}, err)
+		_, err = d.Create("rate-limiting", project, newPolicy("scene-pay", &gov.LimiterSpec{MarkerName: "scene-pay", Rate: 10}))
+		assert.IsType(t, &kie.ErrIllegalItem{}, err)
+		_, err = d.Create("instance-isolation", project, newPolicy("scene-pay", &gov.InstanceIsolationSpec{
+			MarkerName: "scene-pay", ErrorRateThreshold: 50, IsolationDuration: "30s"}))
+		assert.IsType(t, &kie.ErrIllegalItem{}, err)
+		_, err = d.Create("bulkhead", project, []byte(`{"name":"scene-pay","spec":{"match":"scene-pay","maxConcurrentCalls":10}}`))
+		assert.IsType(t, &kie.ErrIllegalItem{}, err)
+		err = d.Update(retryID, "retry", project, newPolicy("scene-pay", &gov.LBSpec{
+			MarkerName: "scene-pay", RetrySame: 1, Bo: &gov.BackOffPolicy{InitialInterval: 10}}))
+		assert.IsType(t, &kie.ErrIllegalItem{}, err)
+
+		var _ svc.KindSupporter = d
+		assert.False(t, d.Supports("circuit-breaker"))
+		assert.True(t, d.Supports("retry"))
+	})
+
+	t.Run("delete policies should delete the resources", func(t *testing.T) {
+		b, err := d.List("match-group", project, "", "all")
+		assert.NoError(t, err)
+		var policies []*gov.Policy
+		assert.NoError(t, json.Unmarshal(b, &policies))
+		assert.Equal(t, 1, len(policies))
+		assert.NoError(t, d.Delete(policies[0].ID, project))
+		assert.Empty(t, listNames(t, client, istio.VirtualServices))
+		assert.Equal(t, svc.ErrPolicyNotExist, d.Delete(policies[0].ID, project))
+	})
+}
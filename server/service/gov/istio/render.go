@@ -0,0 +1,360 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	APIVersion = "networking.istio.io/v1alpha3"
+	NamePrefix = "servicecomb-gov-"
+	RetryOn    = "5xx,connect-failure,refused-stream"
+)
+
+var (
+	VirtualServices  = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1alpha3", Resource: "virtualservices"}
+	DestinationRules = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1alpha3", Resource: "destinationrules"}
+	EnvoyFilters     = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1alpha3", Resource: "envoyfilters"}
+	ConfigMaps       = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	// Resources are the istio resources generated by the distributor,
+	// no EnvoyFilter is generated now, the ones generated before are removed by the reconciliation
+	Resources = []schema.GroupVersionResource{VirtualServices, DestinationRules, EnvoyFilters}
+
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+	// unsupportedKinds are the kinds istio has no equivalent of,
+	// the local rate limit of envoy can not be scoped to the requests of a marker
+	unsupportedKinds = map[string]bool{"circuit-breaker": true, "loadbalancer": true, "rate-limiting": true}
+	// unsupportedFields are the spec fields istio has no equivalent of
+	unsupportedFields = map[string][]string{
+		"retry":              {"backoff"},
+		"bulkhead":           {"maxWaitDuration"},
+		"instance-isolation": {"errorRateThreshold", "minimumNumberOfCalls"},
+	}
+)
+
+type virtualService struct {
+	Hosts []string    `json:"hosts"`
+	HTTP  []httpRoute `json:"http"`
+}
+
+type httpRoute struct {
+	Match   []httpMatch  `json:"match,omitempty"`
+	Route   []routeDest  `json:"route"`
+	Retries *httpRetries `json:"retries,omitempty"`
+	Fault   *httpFault   `json:"fault,omitempty"`
+}
+
+type httpMatch struct {
+	URI     map[string]string            `json:"uri,omitempty"`
+	Method  map[string]string            `json:"method,omitempty"`
+	Headers map[string]map[string]string `json:"headers,omitempty"`
+}
+
+type routeDest struct {
+	Destination destination `json:"destination"`
+}
+
+type destination struct {
+	Host string `json:"host"`
+}
+
+type httpRetries struct {
+	Attempts int    `json:"attempts"`
+	RetryOn  string `json:"retryOn"`
+}
+
+type httpFault struct {
+	Delay *faultDelay `json:"delay,omitempty"`
+	Abort *faultAbort `json:"abort,omitempty"`
+}
+
+type faultDelay struct {
+	Percentage percent `json:"percentage"`
+	FixedDelay string  `json:"fixedDelay"`
+}
+
+type faultAbort struct {
+	Percentage percent `json:"percentage"`
+	HTTPStatus int     `json:"httpStatus"`
+}
+
+type percent struct {
+	Value float64 `json:"value"`
+}
+
+type destinationRule struct {
+	Host          string        `json:"host"`
+	TrafficPolicy trafficPolicy `json:"trafficPolicy"`
+}
+
+type trafficPolicy struct {
+	LoadBalancer     map[string]string `json:"loadBalancer,omitempty"`
+	ConnectionPool   *connectionPool   `json:"connectionPool,omitempty"`
+	OutlierDetection *outlierDetection `json:"outlierDetection,omitempty"`
+}
+
+type connectionPool struct {
+	HTTP map[string]int `json:"http"`
+}
+
+type outlierDetection struct {
+	Consecutive5xxErrors int    `json:"consecutive5xxErrors,omitempty"`
+	BaseEjectionTime     string `json:"baseEjectionTime,omitempty"`
+	MaxEjectionPercent   int    `json:"maxEjectionPercent,omitempty"`
+}
+
+//attachment is the policies attached to a match group
+type attachment struct {
+	retry     *gov.LBSpec
+	fault     *gov.FaultInjectionSpec
+	bulkhead  *gov.BulkheadSpec
+	isolation *gov.InstanceIsolationSpec
+}
+
+//renderable returns an error if the policy of kind passes the validation but can not be rendered,
+//so the policies are never dropped silently
+func renderable(kind string, spec interface{}) error {
+	if unsupportedKinds[kind] {
+		return kie.NewErrIllegalItem("kind is not supported by istio", kind)
+	}
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, field := range unsupportedFields[kind] {
+		if v := m[field]; v != nil && !reflect.ValueOf(v).IsZero() {
+			return kie.NewErrIllegalItem(field+" is not supported by istio", spec)
+		}
+	}
+	return nil
+}
+
+//Render converts the enabled policies of a project to the istio resources,
+//istio does not merge the VirtualServices or DestinationRules of a host,
+//so the match groups of app are the routes of one VirtualService of host app,
+//the retry and fault injection policies are the retries and faults of the routes of the match group,
+//the bulkhead and instance isolation policies are the connection pool and outlier detection of one DestinationRule,
+//and it is round robin if retry the next instance. The DestinationRule applies to all the requests of the host,
+//the first match group by name wins if the policies of the match groups conflict.
+//The circuit breaker and rate limiting policies are not supported by istio, they are rejected by renderable
+func Render(namespace string, policies []*gov.Policy) []*unstructured.Unstructured {
+	attachments := make(map[string]*attachment)
+	attach := func(p *gov.Policy, marker string) *attachment {
+		name := objectName(p.Selector, marker)
+		if attachments[name] == nil {
+			attachments[name] = &attachment{}
+		}
+		return attachments[name]
+	}
+	var groups []*gov.Policy
+	for _, p := range policies {
+		if !svc.Enabled(p) {
+			continue
+		}
+		switch p.Kind {
+		case svc.ToSnake(svc.KindMatchGroup):
+			groups = append(groups, p)
+		case svc.ToSnake("retry"):
+			spec := &gov.LBSpec{}
+			if decode(p, spec) {
+				attach(p, spec.MarkerName).retry = spec
+			}
+		case svc.ToSnake("fault-injection"):
+			spec := &gov.FaultInjectionSpec{}
+			if decode(p, spec) {
+				attach(p, spec.MarkerName).fault = spec
+			}
+		case svc.ToSnake("bulkhead"):
+			spec := &gov.BulkheadSpec{}
+			if decode(p, spec) {
+				attach(p, spec.MarkerName).bulkhead = spec
+			}
+		case svc.ToSnake("instance-isolation"):
+			spec := &gov.InstanceIsolationSpec{}
+			if decode(p, spec) {
+				attach(p, spec.MarkerName).isolation = spec
+			}
+		}
+	}
+	// the routes are matched in order, sort them so the resources are stable between reconciliations
+	sort.Slice(groups, func(i, j int) bool {
+		return objectName(groups[i].Selector, groups[i].Name) < objectName(groups[j].Selector, groups[j].Name)
+	})
+
+	var hosts []string
+	services := make(map[string]*virtualService)
+	rules := make(map[string]*destinationRule)
+	for _, p := range groups {
+		host := p.Selector.App
+		if host == "" {
+			log.Warnf("skip governance policy %s without app", p.Name)
+			continue
+		}
+		spec := &gov.MatchSpec{}
+		if !decode(p, spec) {
+			continue
+		}
+		a := attachments[objectName(p.Selector, p.Name)]
+		if a == nil {
+			a = &attachment{}
+		}
+		if services[host] == nil {
+			hosts = append(hosts, host)
+			services[host] = &virtualService{Hosts: []string{host}}
+			rules[host] = &destinationRule{Host: host}
+		}
+		services[host].HTTP = append(services[host].HTTP, renderRoutes(host, spec, a)...)
+		mergeTrafficPolicy(&rules[host].TrafficPolicy, a, p.Name)
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, host := range hosts {
+		name := objectName(gov.Selector{App: host}, "")
+		vs := services[host]
+		// the requests not marked are routed as usual
+		vs.HTTP = append(vs.HTTP, httpRoute{Route: []routeDest{{Destination: destination{Host: host}}}})
+		objs = append(objs, newObject(namespace, "VirtualService", name, vs))
+		if dr := rules[host]; !reflect.DeepEqual(dr.TrafficPolicy, trafficPolicy{}) {
+			objs = append(objs, newObject(namespace, "DestinationRule", name, dr))
+		}
+	}
+	return objs
+}
+
+func renderRoutes(host string, spec *gov.MatchSpec, a *attachment) []httpRoute {
+	var retries *httpRetries
+	if a.retry != nil && a.retry.RetrySame+a.retry.RetryNext > 0 {
+		retries = &httpRetries{Attempts: a.retry.RetrySame + a.retry.RetryNext, RetryOn: RetryOn}
+	}
+	var fault *httpFault
+	if a.fault != nil {
+		fault = &httpFault{}
+		if a.fault.Delay != nil {
+			fault.Delay = &faultDelay{Percentage: percent{Value: a.fault.Delay.Percentage}, FixedDelay: a.fault.Delay.FixedDelay}
+		}
+		if a.fault.Abort != nil {
+			fault.Abort = &faultAbort{Percentage: percent{Value: a.fault.Abort.Percentage}, HTTPStatus: a.fault.Abort.HTTPStatus}
+		}
+	}
+	route := []routeDest{{Destination: destination{Host: host}}}
+	var routes []httpRoute
+	for _, mp := range spec.MatchPolicies {
+		var matches []httpMatch
+		base := httpMatch{URI: mp.APIPaths, Headers: mp.Headers}
+		if len(mp.Methods) == 0 {
+			matches = append(matches, base)
+		}
+		// the methods are ORed, so every method is a match
+		for _, method := range mp.Methods {
+			m := base
+			m.Method = map[string]string{"exact": method}
+			matches = append(matches, m)
+		}
+		routes = append(routes, httpRoute{Match: matches, Route: route, Retries: retries, Fault: fault})
+	}
+	return routes
+}
+
+//mergeTrafficPolicy sets the traffic policy of the match group to tp,
+//the ones already set by the former match groups are kept
+func mergeTrafficPolicy(tp *trafficPolicy, a *attachment, group string) {
+	conflict := func(field string) {
+		log.Warnf("ignore the %s of match group %s, it conflicts with another match group of the host", field, group)
+	}
+	if a.retry != nil && a.retry.RetryNext > 0 {
+		// envoy retries on another host chosen by round robin
+		tp.LoadBalancer = map[string]string{"simple": "ROUND_ROBIN"}
+	}
+	if a.bulkhead != nil {
+		pool := &connectionPool{HTTP: map[string]int{"http2MaxRequests": a.bulkhead.MaxConcurrentCalls}}
+		if tp.ConnectionPool == nil {
+			tp.ConnectionPool = pool
+		} else if !reflect.DeepEqual(tp.ConnectionPool, pool) {
+			conflict("bulkhead")
+		}
+	}
+	if a.isolation != nil {
+		detection := &outlierDetection{
+			Consecutive5xxErrors: a.isolation.ConsecutiveErrors,
+			BaseEjectionTime:     a.isolation.IsolationDuration,
+			MaxEjectionPercent:   int(a.isolation.MaxIsolationPercent),
+		}
+		if tp.OutlierDetection == nil {
+			tp.OutlierDetection = detection
+		} else if !reflect.DeepEqual(tp.OutlierDetection, detection) {
+			conflict("instance isolation")
+		}
+	}
+}
+
+func newObject(namespace, kind, name string, spec interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(APIVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{LabelManagedBy: ManagedBy})
+	if spec != nil {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+		if err != nil {
+			log.Error("convert istio resource failed", err)
+		}
+		obj.Object["spec"] = m
+	}
+	return obj
+}
+
+//objectName is unique for the policies of a kind in a namespace,
+//it is converted to a valid kubernetes resource name
+func objectName(selector gov.Selector, name string) string {
+	var parts []string
+	for _, part := range []string{selector.App, selector.Environment, name} {
+		part = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(part), "-"), "-")
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	return NamePrefix + strings.Join(parts, "-")
+}
+
+func decode(p *gov.Policy, spec interface{}) bool {
+	b, err := json.Marshal(p.Spec)
+	if err == nil {
+		err = json.Unmarshal(b, spec)
+	}
+	if err != nil {
+		log.Error("governance policy "+p.Name+" spec is invalid", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio_test
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/istio"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func renderPolicy(kind, name string, spec interface{}) *gov.Policy {
+	return &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     name,
+			Selector: gov.Selector{App: "payment", Environment: "production"},
+		},
+		Kind: kind,
+		Spec: spec,
+	}
+}
+
+func TestRender(t *testing.T) {
+	objs := istio.Render(project, []*gov.Policy{
+		renderPolicy("matchGroup", "scene-pay", &gov.MatchSpec{MatchPolicies: []*gov.MatchPolicy{
+			{APIPaths: map[string]string{"prefix": "/pay"}},
+		}}),
+		renderPolicy("faultInjection", "scene-pay", &gov.FaultInjectionSpec{MarkerName: "scene-pay",
+			Delay: &gov.DelayFault{Percentage: 10, FixedDelay: "2s"},
+			Abort: &gov.AbortFault{Percentage: 5, HTTPStatus: 503},
+		}),
+		renderPolicy("bulkhead", "scene-pay", &gov.BulkheadSpec{MarkerName: "scene-pay", MaxConcurrentCalls: 100}),
+		renderPolicy("instanceIsolation", "scene-pay", &gov.InstanceIsolationSpec{MarkerName: "scene-pay",
+			ConsecutiveErrors: 5, IsolationDuration: "30s", MaxIsolationPercent: 50}),
+		renderPolicy("circuitBreaker", "scene-pay", &gov.CircuitBreakerSpec{MarkerName: "scene-pay", FailureRateThreshold: 50}),
+	})
+	assert.Equal(t, 2, len(objs))
+
+	vs := objs[0]
+	assert.Equal(t, "VirtualService", vs.GetKind())
+	routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+	assert.Equal(t, 2, len(routes))
+	route := routes[0].(map[string]interface{})
+	delay, _, _ := unstructured.NestedString(route, "fault", "delay", "fixedDelay")
+	assert.Equal(t, "2s", delay)
+	status, _, _ := unstructured.NestedInt64(route, "fault", "abort", "httpStatus")
+	assert.Equal(t, int64(503), status)
+
+	dr := objs[1]
+	assert.Equal(t, "DestinationRule", dr.GetKind())
+	_, found, _ := unstructured.NestedMap(dr.Object, "spec", "trafficPolicy", "loadBalancer")
+	assert.False(t, found)
+	maxRequests, _, _ := unstructured.NestedInt64(dr.Object, "spec", "trafficPolicy", "connectionPool", "http", "http2MaxRequests")
+	assert.Equal(t, int64(100), maxRequests)
+	errs, _, _ := unstructured.NestedInt64(dr.Object, "spec", "trafficPolicy", "outlierDetection", "consecutive5xxErrors")
+	assert.Equal(t, int64(5), errs)
+	ejection, _, _ := unstructured.NestedString(dr.Object, "spec", "trafficPolicy", "outlierDetection", "baseEjectionTime")
+	assert.Equal(t, "30s", ejection)
+}
+
+func TestRender_MatchGroups(t *testing.T) {
+	match := func(name, prefix string) *gov.Policy {
+		return renderPolicy("matchGroup", name, &gov.MatchSpec{MatchPolicies: []*gov.MatchPolicy{
+			{APIPaths: map[string]string{"prefix": prefix}},
+		}})
+	}
+
+	t.Run("match groups of an app should be merged per host", func(t *testing.T) {
+		objs := istio.Render(project, []*gov.Policy{
+			match("scene-refund", "/refund"),
+			match("scene-pay", "/pay"),
+			renderPolicy("bulkhead", "scene-pay", &gov.BulkheadSpec{MarkerName: "scene-pay", MaxConcurrentCalls: 100}),
+			renderPolicy("bulkhead", "scene-refund", &gov.BulkheadSpec{MarkerName: "scene-refund", MaxConcurrentCalls: 10}),
+			renderPolicy("instanceIsolation", "scene-refund", &gov.InstanceIsolationSpec{MarkerName: "scene-refund",
+				ConsecutiveErrors: 5, IsolationDuration: "30s"}),
+		})
+		assert.Equal(t, 2, len(objs))
+
+		vs := objs[0]
+		assert.Equal(t, "servicecomb-gov-payment", vs.GetName())
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+		assert.Equal(t, 3, len(routes))
+		prefix, _, _ := unstructured.NestedSlice(routes[0].(map[string]interface{}), "match")
+		assert.Equal(t, "/pay", prefix[0].(map[string]interface{})["uri"].(map[string]interface{})["prefix"])
+
+		dr := objs[1]
+		assert.Equal(t, "servicecomb-gov-payment", dr.GetName())
+		maxRequests, _, _ := unstructured.NestedInt64(dr.Object, "spec", "trafficPolicy", "connectionPool", "http", "http2MaxRequests")
+		assert.Equal(t, int64(100), maxRequests)
+		errs, _, _ := unstructured.NestedInt64(dr.Object, "spec", "trafficPolicy", "outlierDetection", "consecutive5xxErrors")
+		assert.Equal(t, int64(5), errs)
+	})
+
+	t.Run("disabled policies should not be rendered", func(t *testing.T) {
+		disabled := match("scene-pay", "/pay")
+		disabled.Status = "disabled"
+		assert.Empty(t, istio.Render(project, []*gov.Policy{disabled}))
+
+		fault := renderPolicy("faultInjection", "scene-pay", &gov.FaultInjectionSpec{MarkerName: "scene-pay",
+			Abort: &gov.AbortFault{Percentage: 5, HTTPStatus: 503}})
+		fault.Status = "disabled"
+		objs := istio.Render(project, []*gov.Policy{match("scene-pay", "/pay"), fault})
+		assert.Equal(t, 1, len(objs))
+		routes, _, _ := unstructured.NestedSlice(objs[0].Object, "spec", "http")
+		_, found, _ := unstructured.NestedMap(routes[0].(map[string]interface{}), "fault")
+		assert.False(t, found)
+	})
+}
@@ -20,7 +20,6 @@
 package kie
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -45,16 +44,16 @@ type Distributor struct {
 
 const (
 	PREFIX         = "servicecomb."
-	MatchGroup     = "match-group"
-	EnableStatus   = "enabled"
+	MatchGroup     = svc.KindMatchGroup
+	EnableStatus   = svc.EnableStatus
 	ValueType      = "text"
 	AppKey         = "app"
 	EnvironmentKey = "environment"
-	EnvAll         = "all"
+	EnvAll         = svc.EnvAll
 	BusinessPrefix = "scene-"
 )
 
-var PolicyNames = []string{"retry", "rateLimiting", "circuitBreaker", "bulkhead"}
+var PolicyNames = svc.PolicyNames
 
 var rule = Validator{}
 
@@ -80,7 +79,7 @@ func (d *Distributor) Create(kind, project string, spec []byte) ([]byte, error)
 		return nil, err
 	}
 	kv := kie.KVRequest{
-		Key:       PREFIX + toSnake(kind) + "." + p.Name,
+		Key:       PREFIX + ToSnake(kind) + "." + p.Name,
 		Value:     string(yamlByte),
 		Status:    EnableStatus,
 		ValueType: ValueType,
@@ -227,31 +226,14 @@ func new(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
 	return &Distributor{name: opts.Name, lbPolicies: map[string]*gov.Policy{}, client: initClient(opts.Endpoint)}, nil
 }
 
-func toSnake(name string) string {
-	if name == "" {
-		return ""
-	}
-	temp := strings.Split(name, "-")
-	var buffer bytes.Buffer
-	for num, v := range temp {
-		vv := []rune(v)
-		if num == 0 {
-			buffer.WriteString(string(vv))
-			continue
-		}
-		if len(vv) > 0 {
-			if vv[0] >= 'a' && vv[0] <= 'z' { //首字母大写
-				vv[0] -= 32
-			}
-			buffer.WriteString(string(vv))
-		}
-	}
-	return buffer.String()
+//ToSnake converts the kebab case kind to the camel case one, like rate-limiting to rateLimiting
+func ToSnake(name string) string {
+	return svc.ToSnake(name)
 }
 
 func (d *Distributor) listDataByKind(kind, project, app, env string) (*kie.KVResponse, int, error) {
 	ops := []kie.GetOption{
-		kie.WithKey("beginWith(" + PREFIX + toSnake(kind) + ")"),
+		kie.WithKey("beginWith(" + PREFIX + ToSnake(kind) + ")"),
 		kie.WithRevision(0),
 		kie.WithGetProject(project),
 	}
@@ -20,7 +20,13 @@
 package kie
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"reflect"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 )
 
 type Validator struct {
@@ -35,6 +41,11 @@ var (
 	methodSet map[string]bool
 )
 
+//NewErrIllegalItem returns the validation error of val
+func NewErrIllegalItem(err string, val interface{}) *ErrIllegalItem {
+	return &ErrIllegalItem{err, val}
+}
+
 func (e *ErrIllegalItem) Error() string {
 	return fmt.Sprintf("illegal item : %v , msg: %s", e.val, e.err)
 }
@@ -48,13 +59,18 @@ func (d *Validator) Validate(kind string, spec interface{}) error {
 	case "rate-limiting":
 		return rateLimitingValidate(spec)
 	case "circuit-breaker":
+		return typedValidate(spec, &gov.CircuitBreakerSpec{})
 	case "bulkhead":
+		return typedValidate(spec, &gov.BulkheadSpec{})
+	case "fault-injection":
+		return typedValidate(spec, &gov.FaultInjectionSpec{})
+	case "instance-isolation":
+		return typedValidate(spec, &gov.InstanceIsolationSpec{})
 	case "loadbalancer":
 		return nil
 	default:
 		return &ErrIllegalItem{"not support kind yet", kind}
 	}
-	return nil
 }
 
 func matchValidate(val interface{}) error {
@@ -116,6 +132,29 @@ func rateLimitingValidate(val interface{}) error {
 	return nil
 }
 
+//typedValidate decodes the spec to the typed one and validates the known fields,
+//the unknown fields are only warned, so the specs stored by the former versions are still valid
+func typedValidate(val interface{}, spec gov.Validator) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return &ErrIllegalItem{"spec can not be encoded", val}
+	}
+	err = json.Unmarshal(b, spec)
+	if err != nil {
+		return &ErrIllegalItem{err.Error(), val}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(reflect.New(reflect.TypeOf(spec).Elem()).Interface()); err != nil {
+		log.Warn(fmt.Sprintf("spec %s is accepted with the unknown fields: %s", b, err))
+	}
+	err = spec.Validate()
+	if err != nil {
+		return &ErrIllegalItem{err.Error(), val}
+	}
+	return nil
+}
+
 func policyValidate(val interface{}) error {
 	spec, ok := val.(map[string]interface{})
 	if !ok {
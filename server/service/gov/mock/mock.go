@@ -36,7 +36,7 @@ type Distributor struct {
 
 const MatchGroup = "match-group"
 
-var PolicyNames = []string{"retry", "rateLimiting", "circuitBreaker", "bulkhead"}
+var PolicyNames = []string{"retry", "rateLimiting", "circuitBreaker", "bulkhead", "faultInjection", "instanceIsolation"}
 
 func (d *Distributor) Create(kind, project string, spec []byte) ([]byte, error) {
 	p := &gov.Policy{}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+const (
+	KindMatchGroup = "match-group"
+	//EnableStatus is the status of the enabled policies, the policies without status are enabled too
+	EnableStatus = "enabled"
+	//EnvAll matches the policies of all environments
+	EnvAll = "all"
+)
+
+var (
+	ErrPolicyExist    = errors.New("governance policy already exists")
+	ErrPolicyNotExist = errors.New("governance policy does not exist")
+)
+
+//PolicyNames are the camel case kinds of the policies referring to match groups, in the display order
+var PolicyNames = []string{"retry", "rateLimiting", "circuitBreaker", "bulkhead", "faultInjection", "instanceIsolation"}
+
+//ToSnake converts the kebab case kind to the camel case one, like rate-limiting to rateLimiting
+func ToSnake(name string) string {
+	if name == "" {
+		return ""
+	}
+	temp := strings.Split(name, "-")
+	var buffer bytes.Buffer
+	for num, v := range temp {
+		vv := []rune(v)
+		if num == 0 {
+			buffer.WriteString(string(vv))
+			continue
+		}
+		if len(vv) > 0 {
+			if vv[0] >= 'a' && vv[0] <= 'z' {
+				vv[0] -= 32
+			}
+			buffer.WriteString(string(vv))
+		}
+	}
+	return buffer.String()
+}
+
+//ParsePolicy decodes the policy in the request body, the GovernancePolicy is never nil
+func ParsePolicy(spec []byte) (*gov.Policy, error) {
+	p := &gov.Policy{}
+	err := json.Unmarshal(spec, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.GovernancePolicy == nil {
+		p.GovernancePolicy = &gov.GovernancePolicy{}
+	}
+	return p, nil
+}
+
+//MatchPolicy has the same semantic as the kie labels query,
+//empty app matches all apps and env 'all' matches all environments
+func MatchPolicy(p *gov.Policy, app, env string) bool {
+	if app != "" && p.Selector.App != app {
+		return false
+	}
+	return env == EnvAll || p.Selector.Environment == env
+}
+
+//GroupPolicies groups the policies of app and env by the match groups,
+//the policies are stored with the camel case kinds
+func GroupPolicies(policies []*gov.Policy, app, env string) []*gov.DisplayData {
+	var groups []*gov.Policy
+	policyMap := make(map[string]*gov.Policy)
+	for _, p := range policies {
+		if !MatchPolicy(p, app, env) {
+			continue
+		}
+		if p.Kind == ToSnake(KindMatchGroup) {
+			p.Kind = KindMatchGroup
+			groups = append(groups, p)
+			continue
+		}
+		policyMap[p.Name+p.Kind] = p
+	}
+	r := make([]*gov.DisplayData, 0, len(groups))
+	for _, group := range groups {
+		var items []*gov.Policy
+		for _, kind := range PolicyNames {
+			if policyMap[group.Name+kind] != nil {
+				items = append(items, policyMap[group.Name+kind])
+			}
+		}
+		r = append(r, &gov.DisplayData{
+			Policies:   items,
+			MatchGroup: group,
+		})
+	}
+	return r
+}
+
+//Enabled returns true if the policy applies
+func Enabled(p *gov.Policy) bool {
+	return p.GovernancePolicy != nil && (len(p.Status) == 0 || p.Status == EnableStatus)
+}
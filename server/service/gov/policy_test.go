@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	p := &gov.Policy{GovernancePolicy: &gov.GovernancePolicy{
+		Selector: gov.Selector{App: "app", Environment: "production"},
+	}}
+	assert.True(t, MatchPolicy(p, "app", "production"))
+	assert.True(t, MatchPolicy(p, "", "production"))
+	assert.True(t, MatchPolicy(p, "app", "all"))
+	assert.False(t, MatchPolicy(p, "app", ""))
+	assert.False(t, MatchPolicy(p, "other", "production"))
+}
+
+func TestToSnake(t *testing.T) {
+	assert.Equal(t, "", ToSnake(""))
+	assert.Equal(t, "retry", ToSnake("retry"))
+	assert.Equal(t, "rateLimiting", ToSnake("rate-limiting"))
+	assert.Equal(t, "instanceIsolation", ToSnake("instance-isolation"))
+}
+
+func TestGroupPolicies(t *testing.T) {
+	newPolicy := func(name, kind, env string) *gov.Policy {
+		return &gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{Name: name, Selector: gov.Selector{Environment: env}},
+			Kind:             kind,
+		}
+	}
+	r := GroupPolicies([]*gov.Policy{
+		newPolicy("scene-a", "matchGroup", ""),
+		newPolicy("scene-a", "retry", ""),
+		newPolicy("scene-a", "rateLimiting", ""),
+		newPolicy("scene-b", "matchGroup", "production"),
+		newPolicy("scene-b", "retry", "production"),
+	}, "", "")
+	assert.Equal(t, 1, len(r))
+	assert.Equal(t, "scene-a", r[0].MatchGroup.Name)
+	assert.Equal(t, "match-group", r[0].MatchGroup.Kind)
+	assert.Equal(t, 2, len(r[0].Policies))
+	assert.Equal(t, "retry", r[0].Policies[0].Kind)
+	assert.Equal(t, "rateLimiting", r[0].Policies[1].Kind)
+}
@@ -0,0 +1,483 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	nf "github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const (
+	RolloutGroup = "__GOV_ROLLOUT_GROUP__"
+	// DefaultAbortAlarms are the alarms abort the rollouts by default, they are raised by the unhealthy instances
+	DefaultAbortAlarms = string(alarm.IDInstanceProbeFailed)
+	// abortRetries is the times of retrying to abort a rollout changed concurrently
+	abortRetries = 3
+	// registryCacheTTL is how long the services and instances loaded for the rollouts are reused
+	registryCacheTTL = 5 * time.Second
+)
+
+var (
+	ErrRolloutNotSupported = errors.New("the policy rollout needs the datasource")
+	ErrRolloutAborted      = errors.New("the policy rollout is aborted, start it again")
+	ErrRolloutNotHonored   = errors.New("the policy rollout is not honored by the config distributors, like kie and istio")
+)
+
+var (
+	subscribeAlarmsOnce sync.Once
+	registries          = &registryCache{entries: make(map[string]*registryEntry)}
+)
+
+//StartRollout applies the policy to the instances selected by the first stage of the rollout,
+//it restarts the rollout if the policy is in rollout already
+func StartRollout(ctx context.Context, kind, project, id string, r *gov.Rollout) error {
+	if datasource.Instance() == nil {
+		return ErrRolloutNotSupported
+	}
+	if primary == nil {
+		return nil
+	}
+	if !rolloutHonored() {
+		return ErrRolloutNotHonored
+	}
+	_, err := getPolicy(primary, kind, id, project)
+	if err != nil {
+		return err
+	}
+	old, err := datasource.Instance().GetGovRollout(ctx, project, id)
+	switch {
+	case err == nil:
+		r.Revision = old.Revision
+	case err == datasource.ErrGovRolloutNotExist:
+		r.Revision = 0
+	default:
+		return err
+	}
+	r.ID, r.Project, r.Kind = id, project, kind
+	r.Stage, r.Status, r.Reason, r.Instances = 0, gov.RolloutProgressing, "", nil
+	r.UpdateTime = time.Now().Unix()
+	return datasource.Instance().PutGovRollout(ctx, project, r)
+}
+
+//rolloutHonored returns false if any distributor applies the policies to all the instances regardless of the rollouts,
+//the rollouts are attached to the policies read from the distributors, which kie and istio do not pass to the instances
+func rolloutHonored() bool {
+	if !honorRollout(primary) {
+		return false
+	}
+	for _, t := range targets {
+		if !honorRollout(t.cd) {
+			return false
+		}
+	}
+	return true
+}
+
+func honorRollout(cd ConfigDistributor) bool {
+	if cd == nil {
+		return true
+	}
+	switch cd.Type() {
+	case ConfigDistributorKie, ConfigDistributorIstio:
+		log.Warn(fmt.Sprintf("config distributor %s::%s does not honor the policy rollouts", cd.Name(), cd.Type()))
+		return false
+	default:
+		return true
+	}
+}
+
+//Promote moves the rollout of the policy to the next stage,
+//the rollout is done once the last stage is promoted, then the policy applies to all the instances and nil is returned,
+//it returns datasource.ErrGovRolloutConflict if the rollout is changed concurrently, like aborted by alarms
+func Promote(ctx context.Context, project, id string) (*gov.Rollout, error) {
+	if datasource.Instance() == nil {
+		return nil, ErrRolloutNotSupported
+	}
+	r, err := datasource.Instance().GetGovRollout(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status == gov.RolloutAborted {
+		return nil, ErrRolloutAborted
+	}
+	if r.Stage+1 >= len(r.Stages) {
+		err = datasource.Instance().DeleteGovRollout(ctx, project, id)
+		if err != nil {
+			return nil, err
+		}
+		log.Info(fmt.Sprintf("the rollout of policy %s/%s is done", project, id))
+		return nil, nil
+	}
+	r.Stage++
+	r.UpdateTime = time.Now().Unix()
+	err = datasource.Instance().PutGovRollout(ctx, project, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+//Abort stops applying the policy to any instance
+func Abort(ctx context.Context, project, id, reason string) error {
+	if datasource.Instance() == nil {
+		return ErrRolloutNotSupported
+	}
+	return abortRetry(ctx, project, id, reason)
+}
+
+//abortRetry reads the rollout again and retries if it is changed concurrently,
+//the aborted rollout is not changed
+func abortRetry(ctx context.Context, project, id, reason string) error {
+	for i := 0; ; i++ {
+		r, err := datasource.Instance().GetGovRollout(ctx, project, id)
+		if err != nil {
+			return err
+		}
+		if r.Status == gov.RolloutAborted {
+			return nil
+		}
+		err = abort(ctx, r, reason)
+		if err != datasource.ErrGovRolloutConflict || i+1 >= abortRetries {
+			return err
+		}
+	}
+}
+
+func abort(ctx context.Context, r *gov.Rollout, reason string) error {
+	r.Status, r.Reason = gov.RolloutAborted, reason
+	r.UpdateTime = time.Now().Unix()
+	err := datasource.Instance().PutGovRollout(ctx, r.Project, r)
+	if err != nil {
+		log.Error(fmt.Sprintf("abort the rollout of policy %s/%s failed", r.Project, r.ID), err)
+		return err
+	}
+	log.Warn(fmt.Sprintf("the rollout of policy %s/%s is aborted: %s", r.Project, r.ID, reason))
+	return nil
+}
+
+//abortAll aborts the progressing rollouts of the project, or all the projects if project is empty
+func abortAll(ctx context.Context, project, reason string) {
+	rollouts, err := datasource.Instance().ListGovRollout(ctx, project)
+	if err != nil {
+		log.Error("list the policy rollouts failed", err)
+		return
+	}
+	for _, r := range rollouts {
+		if r.Status != gov.RolloutProgressing {
+			continue
+		}
+		if len(project) > 0 && r.Project != project {
+			continue
+		}
+		_ = abortRetry(ctx, r.Project, r.ID, reason)
+	}
+}
+
+//deleteRollout deletes the rollout of the deleted policy
+func deleteRollout(ctx context.Context, project, id string) {
+	if datasource.Instance() == nil {
+		return
+	}
+	err := datasource.Instance().DeleteGovRollout(ctx, project, id)
+	if err != nil && err != datasource.ErrGovRolloutNotExist {
+		log.Error(fmt.Sprintf("delete the rollout of policy %s/%s failed", project, id), err)
+	}
+}
+
+//AlarmSubscriber aborts the progressing rollouts once an alarm is activated,
+//the rollouts of the project in the alarm are aborted, or all the rollouts if the alarm has no project
+type AlarmSubscriber struct {
+	nf.Subscriber
+	// alarms are the ids of the alarms abort the rollouts, all the alarms if empty
+	alarms map[model.ID]bool
+}
+
+func (s *AlarmSubscriber) OnMessage(evt nf.Event) {
+	ae, ok := evt.(*model.AlarmEvent)
+	if !ok || ae.Status != alarm.Activated {
+		return
+	}
+	if len(s.alarms) > 0 && !s.alarms[ae.ID] {
+		return
+	}
+	var project string
+	if dp := ae.FieldString(alarm.FieldDomainProject); len(dp) > 0 {
+		_, project = util.FromDomainProject(dp)
+	}
+	reason := fmt.Sprintf("alarm %s is activated", ae.ID)
+	gopool.Go(func(ctx context.Context) {
+		abortAll(ctx, project, reason)
+	})
+}
+
+//NewAlarmSubscriber returns the subscriber of alarms, the ids are separated by comma
+func NewAlarmSubscriber(ids string) *AlarmSubscriber {
+	alarms := make(map[model.ID]bool)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); len(id) > 0 {
+			alarms[model.ID(id)] = true
+		}
+	}
+	return &AlarmSubscriber{
+		Subscriber: nf.NewSubscriber(alarm.ALARM, alarm.Subject, RolloutGroup),
+		alarms:     alarms,
+	}
+}
+
+func subscribeAlarms() {
+	if datasource.Instance() == nil {
+		return
+	}
+	subscribeAlarmsOnce.Do(func() {
+		s := NewAlarmSubscriber(config.GetString("gov.rollout.abortAlarms", DefaultAbortAlarms))
+		if err := notify.Center().AddSubscriber(s); err != nil {
+			log.Error("subscribe alarm events failed, the rollouts are not aborted by alarms", err)
+		}
+	})
+}
+
+//withRollouts attaches the rollouts to the policies in body, body is decoded to v,
+//and filter removes the policies in v the applied returns false for, it is called only if any policy is in rollout.
+//The policies of the aborted rollouts are not applied, and the ones not selected for the instance
+//are not applied if instanceID is not empty. body is returned as is if no policy is in rollout
+func withRollouts(ctx context.Context, project, instanceID string, body []byte, v interface{},
+	filter func(applied func(p *gov.Policy) bool)) []byte {
+	if datasource.Instance() == nil || len(body) == 0 {
+		return body
+	}
+	rollouts, err := datasource.Instance().ListGovRollout(ctx, project)
+	if err != nil {
+		log.Error(fmt.Sprintf("list the policy rollouts of project %s failed", project), err)
+		return body
+	}
+	if len(rollouts) == 0 {
+		return body
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		log.Error("decode the policies failed", err)
+		return body
+	}
+	byID := make(map[string]*gov.Rollout, len(rollouts))
+	for _, r := range rollouts {
+		byID[r.ID] = r
+	}
+	var reg *registry
+	filter(func(p *gov.Policy) bool {
+		if p == nil || p.GovernancePolicy == nil {
+			return true
+		}
+		r, ok := byID[p.ID]
+		if !ok {
+			return true
+		}
+		r.Instances = nil
+		p.Rollout = r
+		if r.Status == gov.RolloutAborted {
+			return false
+		}
+		if r.Status == gov.RolloutProgressing {
+			if reg == nil {
+				reg = registries.get(ctx, project)
+			}
+			r.Instances = reg.selectInstances(p.Selector, r.Current())
+		}
+		return len(instanceID) == 0 || r.Status != gov.RolloutProgressing || containsString(r.Instances, instanceID)
+	})
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Error("encode the policies failed", err)
+		return body
+	}
+	return b
+}
+
+//appliedPolicies returns the policies applied is true for, the policies are filtered in place
+func appliedPolicies(policies []*gov.Policy, applied func(p *gov.Policy) bool) []*gov.Policy {
+	r := policies[:0]
+	for _, p := range policies {
+		if applied(p) {
+			r = append(r, p)
+		}
+	}
+	return r
+}
+
+func containsString(ids []string, id string) bool {
+	i := sort.SearchStrings(ids, id)
+	return i < len(ids) && ids[i] == id
+}
+
+//registry is the services and instances of a project, the tags of services are loaded when used
+type registry struct {
+	services  []*pb.MicroService
+	instances map[string][]*pb.MicroServiceInstance
+	tagsLock  sync.Mutex
+	tags      map[string]map[string]string
+	loadTags  func(serviceID string) map[string]string
+}
+
+type registryEntry struct {
+	reg      *registry
+	loadTime time.Time
+}
+
+//registryCache reuses the registry of a project in registryCacheTTL,
+//so the reads of policies in rollout do not load all the services and instances every time
+type registryCache struct {
+	lock    sync.Mutex
+	entries map[string]*registryEntry
+}
+
+func (c *registryCache) get(ctx context.Context, project string) *registry {
+	key := util.StringJoin([]string{domainOf(ctx), project}, "/")
+	now := time.Now()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok && now.Sub(e.loadTime) < registryCacheTTL {
+		return e.reg
+	}
+	for k, e := range c.entries {
+		if now.Sub(e.loadTime) >= registryCacheTTL {
+			delete(c.entries, k)
+		}
+	}
+	reg := loadRegistry(ctx, project)
+	c.entries[key] = &registryEntry{reg: reg, loadTime: now}
+	return reg
+}
+
+func domainOf(ctx context.Context) string {
+	domain := util.ParseDomain(ctx)
+	if len(domain) == 0 {
+		domain = "default"
+	}
+	return domain
+}
+
+//loadRegistry loads the services and instances of the project in the domain of ctx,
+//it selects no instance if loading failed
+func loadRegistry(ctx context.Context, project string) *registry {
+	domain := domainOf(ctx)
+	ctx = util.SetDomainProject(util.CloneContext(ctx), domain, project)
+	// the registry is cached, the tags are loaded after the request is done
+	tagsCtx := util.SetDomainProject(context.Background(), domain, project)
+	reg := &registry{
+		instances: make(map[string][]*pb.MicroServiceInstance),
+		tags:      make(map[string]map[string]string),
+		loadTags: func(serviceID string) map[string]string {
+			resp, err := datasource.Instance().GetTags(tagsCtx, &pb.GetServiceTagsRequest{ServiceId: serviceID})
+			if err != nil || resp.Response.GetCode() != pb.ResponseSuccess {
+				log.Error(fmt.Sprintf("get the tags of service %s failed", serviceID), err)
+				return nil
+			}
+			return resp.Tags
+		},
+	}
+	services, err := datasource.Instance().GetServices(ctx, &pb.GetServicesRequest{})
+	if err != nil || services.Response.GetCode() != pb.ResponseSuccess {
+		log.Error(fmt.Sprintf("get the services of %s/%s failed", domain, project), err)
+		return reg
+	}
+	instances, err := datasource.Instance().GetAllInstances(ctx, &pb.GetAllInstancesRequest{})
+	if err != nil || instances.Response.GetCode() != pb.ResponseSuccess {
+		log.Error(fmt.Sprintf("get the instances of %s/%s failed", domain, project), err)
+		return reg
+	}
+	reg.services = services.Services
+	for _, inst := range instances.Instances {
+		reg.instances[inst.ServiceId] = append(reg.instances[inst.ServiceId], inst)
+	}
+	return reg
+}
+
+//selectInstances returns the sorted ids of the instances of selector selected by stage
+func (r *registry) selectInstances(selector gov.Selector, stage *gov.RolloutStage) []string {
+	if stage == nil {
+		return nil
+	}
+	var ids []string
+	for _, svc := range r.services {
+		if len(selector.App) > 0 && svc.AppId != selector.App {
+			continue
+		}
+		if svc.Environment != selector.Environment {
+			continue
+		}
+		if len(stage.Tags) > 0 && !contains(r.serviceTags(svc.ServiceId), stage.Tags) {
+			continue
+		}
+		for _, inst := range r.instances[svc.ServiceId] {
+			if !contains(inst.Properties, stage.Properties) {
+				continue
+			}
+			if stage.Percentage > 0 && !inPercentage(inst.InstanceId, stage.Percentage) {
+				continue
+			}
+			ids = append(ids, inst.InstanceId)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *registry) serviceTags(serviceID string) map[string]string {
+	r.tagsLock.Lock()
+	defer r.tagsLock.Unlock()
+	tags, ok := r.tags[serviceID]
+	if !ok {
+		tags = r.loadTags(serviceID)
+		r.tags[serviceID] = tags
+	}
+	return tags
+}
+
+//inPercentage hashes the instance into 100 buckets, the instance is selected if the bucket is less than percentage,
+//so the instances selected by a stage are still selected by the later stages of larger percentage
+func inPercentage(instanceID string, percentage int) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(instanceID))
+	return int(h.Sum32()%100) < percentage
+}
+
+func contains(m, sub map[string]string) bool {
+	for k, v := range sub {
+		if m[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRegistry() *registry {
+	reg := &registry{
+		services: []*pb.MicroService{
+			{ServiceId: "s1", AppId: "app", Environment: "production"},
+			{ServiceId: "s2", AppId: "app", Environment: "production"},
+			{ServiceId: "s3", AppId: "other", Environment: "production"},
+		},
+		instances: make(map[string][]*pb.MicroServiceInstance),
+		tags:      make(map[string]map[string]string),
+		loadTags: func(serviceID string) map[string]string {
+			if serviceID == "s2" {
+				return map[string]string{"canary": "true"}
+			}
+			return nil
+		},
+	}
+	for _, svc := range reg.services {
+		for i := 0; i < 50; i++ {
+			reg.instances[svc.ServiceId] = append(reg.instances[svc.ServiceId], &pb.MicroServiceInstance{
+				ServiceId:  svc.ServiceId,
+				InstanceId: fmt.Sprintf("%s-%d", svc.ServiceId, i),
+				Properties: map[string]string{"zone": fmt.Sprintf("zone-%d", i%2)},
+			})
+		}
+	}
+	return reg
+}
+
+func TestSelectInstances(t *testing.T) {
+	reg := newRegistry()
+	selector := gov.Selector{App: "app", Environment: "production"}
+
+	t.Run("select by percentage should be stable and growing", func(t *testing.T) {
+		all := reg.selectInstances(selector, &gov.RolloutStage{Percentage: 100})
+		assert.Equal(t, 100, len(all))
+		ten := reg.selectInstances(selector, &gov.RolloutStage{Percentage: 10})
+		half := reg.selectInstances(selector, &gov.RolloutStage{Percentage: 50})
+		assert.True(t, len(ten) > 0 && len(ten) < len(half) && len(half) < len(all))
+		assert.Subset(t, half, ten)
+		assert.Equal(t, ten, reg.selectInstances(selector, &gov.RolloutStage{Percentage: 10}))
+	})
+
+	t.Run("select by tags and properties", func(t *testing.T) {
+		ids := reg.selectInstances(selector, &gov.RolloutStage{Tags: map[string]string{"canary": "true"}})
+		assert.Equal(t, 50, len(ids))
+		for _, id := range ids {
+			assert.Contains(t, id, "s2-")
+		}
+		ids = reg.selectInstances(selector, &gov.RolloutStage{
+			Tags:       map[string]string{"canary": "true"},
+			Properties: map[string]string{"zone": "zone-0"},
+		})
+		assert.Equal(t, 25, len(ids))
+	})
+
+	t.Run("select no instance without stage", func(t *testing.T) {
+		assert.Empty(t, reg.selectInstances(selector, nil))
+		assert.Empty(t, reg.selectInstances(gov.Selector{App: "app"}, &gov.RolloutStage{Percentage: 100}))
+	})
+}
+
+func TestNewAlarmSubscriber(t *testing.T) {
+	s := NewAlarmSubscriber(" InternalError, BackendConnectionRefuse ,")
+	assert.Equal(t, map[model.ID]bool{"InternalError": true, "BackendConnectionRefuse": true}, s.alarms)
+	assert.Empty(t, NewAlarmSubscriber("").alarms)
+}
+
+//rolloutStore is a datasource keeps the rollouts in memory, the other methods are not implemented
+type rolloutStore struct {
+	datasource.DataSource
+	mux      sync.Mutex
+	rollouts map[string]gov.Rollout
+}
+
+func (s *rolloutStore) GetGovRollout(ctx context.Context, project, id string) (*gov.Rollout, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	r, ok := s.rollouts[project+"/"+id]
+	if !ok {
+		return nil, datasource.ErrGovRolloutNotExist
+	}
+	return &r, nil
+}
+
+func (s *rolloutStore) ListGovRollout(ctx context.Context, project string) ([]*gov.Rollout, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var rollouts []*gov.Rollout
+	for _, r := range s.rollouts {
+		if len(project) == 0 || r.Project == project {
+			r := r
+			rollouts = append(rollouts, &r)
+		}
+	}
+	return rollouts, nil
+}
+
+func (s *rolloutStore) PutGovRollout(ctx context.Context, project string, r *gov.Rollout) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.rollouts == nil {
+		s.rollouts = make(map[string]gov.Rollout)
+	}
+	if s.rollouts[project+"/"+r.ID].Revision != r.Revision {
+		return datasource.ErrGovRolloutConflict
+	}
+	r.Revision++
+	s.rollouts[project+"/"+r.ID] = *r
+	return nil
+}
+
+func (s *rolloutStore) DeleteGovRollout(ctx context.Context, project, id string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.rollouts, project+"/"+id)
+	return nil
+}
+
+//kieMemory is a distributor does not honor the rollouts
+type kieMemory struct {
+	*memory
+}
+
+func (m *kieMemory) Type() string {
+	return ConfigDistributorKie
+}
+
+func TestRollout(t *testing.T) {
+	m := newMemory("primary")
+	ctx := context.Background()
+	b, err := m.Create("retry", "default", newSpec("a", 1))
+	assert.NoError(t, err)
+	id := DecodeID(b)
+	newRollout := func() *gov.Rollout {
+		return &gov.Rollout{Stages: []*gov.RolloutStage{{Percentage: 10}, {Percentage: 50}}}
+	}
+
+	t.Run("start rollout should be rejected if the distributor does not honor it", func(t *testing.T) {
+		withDatasource(t, &rolloutStore{}, &kieMemory{m}, func() {
+			assert.Equal(t, ErrRolloutNotHonored, StartRollout(ctx, "retry", "default", id, newRollout()))
+		})
+	})
+
+	t.Run("promote a rollout changed concurrently should be rejected", func(t *testing.T) {
+		withDatasource(t, &rolloutStore{}, m, func() {
+			assert.NoError(t, StartRollout(ctx, "retry", "default", id, newRollout()))
+			stale, err := datasource.Instance().GetGovRollout(ctx, "default", id)
+			assert.NoError(t, err)
+			assert.NoError(t, Abort(ctx, "default", id, "test"))
+			stale.Stage++
+			assert.Equal(t, datasource.ErrGovRolloutConflict, datasource.Instance().PutGovRollout(ctx, "default", stale))
+			_, err = Promote(ctx, "default", id)
+			assert.Equal(t, ErrRolloutAborted, err)
+			// restart the aborted rollout
+			assert.NoError(t, StartRollout(ctx, "retry", "default", id, newRollout()))
+			r, err := Promote(ctx, "default", id)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, r.Stage)
+		})
+	})
+
+	t.Run("alarms should abort the rollouts of the project only", func(t *testing.T) {
+		withDatasource(t, &rolloutStore{}, m, func() {
+			for _, project := range []string{"p1", "p2"} {
+				assert.NoError(t, datasource.Instance().PutGovRollout(ctx, project,
+					&gov.Rollout{ID: id, Project: project, Status: gov.RolloutProgressing}))
+			}
+			abortAll(ctx, "p1", "alarm")
+			r, err := datasource.Instance().GetGovRollout(ctx, "p1", id)
+			assert.NoError(t, err)
+			assert.Equal(t, gov.RolloutAborted, r.Status)
+			r, err = datasource.Instance().GetGovRollout(ctx, "p2", id)
+			assert.NoError(t, err)
+			assert.Equal(t, gov.RolloutProgressing, r.Status)
+			abortAll(ctx, "", "alarm")
+			r, err = datasource.Instance().GetGovRollout(ctx, "p2", id)
+			assert.NoError(t, err)
+			assert.Equal(t, gov.RolloutAborted, r.Status)
+		})
+	})
+}
+
+func TestListApplied(t *testing.T) {
+	m := newMemory("primary")
+	ctx := context.Background()
+	spec, _ := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{Name: "a", Selector: gov.Selector{App: "app", Environment: "production"}},
+		Spec:             map[string]interface{}{"retryNext": 1},
+	})
+	b, err := m.Create("retry", "default", spec)
+	assert.NoError(t, err)
+	id := DecodeID(b)
+	registries.lock.Lock()
+	registries.entries["default/default"] = &registryEntry{reg: newRegistry(), loadTime: time.Now().Add(time.Hour)}
+	registries.lock.Unlock()
+	defer func() {
+		registries.lock.Lock()
+		delete(registries.entries, "default/default")
+		registries.lock.Unlock()
+	}()
+	list := func(instanceID string) []*gov.Policy {
+		b, err := ListApplied(ctx, "retry", "default", "", EnvAll, instanceID)
+		assert.NoError(t, err)
+		var policies []*gov.Policy
+		assert.NoError(t, json.Unmarshal(b, &policies))
+		return policies
+	}
+
+	withDatasource(t, &rolloutStore{}, m, func() {
+		assert.NoError(t, datasource.Instance().PutGovRollout(ctx, "default", &gov.Rollout{ID: id, Project: "default",
+			Status: gov.RolloutProgressing, Stages: []*gov.RolloutStage{{Tags: map[string]string{"canary": "true"}}}}))
+
+		t.Run("the policy in rollout should be applied to the selected instances only", func(t *testing.T) {
+			policies := list("")
+			assert.Equal(t, 1, len(policies))
+			assert.Equal(t, 50, len(policies[0].Rollout.Instances))
+			assert.Equal(t, 1, len(list("s2-1")))
+			assert.Empty(t, list("s1-1"))
+		})
+
+		t.Run("the policy of an aborted rollout should not be applied", func(t *testing.T) {
+			assert.NoError(t, Abort(ctx, "default", id, "test"))
+			assert.Empty(t, list(""))
+			assert.Empty(t, list("s2-1"))
+
+			b, err := Get(ctx, "retry", id, "default")
+			assert.NoError(t, err)
+			p := &gov.Policy{}
+			assert.NoError(t, json.Unmarshal(b, p))
+			assert.Equal(t, gov.RolloutAborted, p.Rollout.Status)
+		})
+	})
+}
@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+//Simulate evaluates the match groups and policies of the app for the sample request as the data plane does,
+//a policy applies if it is enabled and the match group it refers to by match is matched,
+//and the policy in rollout applies only if the instance of req is selected, the rollout is reported in the result
+func Simulate(ctx context.Context, project string, req *gov.SimulateRequest) (*gov.SimulateResult, error) {
+	policies := make(map[string][]*gov.Policy, len(Kinds))
+	for _, kind := range Kinds {
+		b, err := List(ctx, kind, project, req.App, req.Environment)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) == 0 {
+			continue
+		}
+		var list []*gov.Policy
+		err = json.Unmarshal(b, &list)
+		if err != nil {
+			return nil, err
+		}
+		policies[kind] = list
+	}
+	return simulate(req, policies), nil
+}
+
+//simulate evaluates the policies keyed by kind, the drafts in req replace the policies of the same kind and name
+func simulate(req *gov.SimulateRequest, policies map[string][]*gov.Policy) *gov.SimulateResult {
+	policies = withDrafts(policies, req.Policies)
+	result := &gov.SimulateResult{Markers: []*gov.MarkerResult{}, Policies: []*gov.Policy{}}
+	matched := make(map[string]bool)
+	for _, p := range policies[KindMatchGroup] {
+		if !Enabled(p) || !rolledOut(req, p) {
+			continue
+		}
+		r := evaluate(req, p)
+		matched[p.Name] = matched[p.Name] || r.Matched
+		result.Markers = append(result.Markers, r)
+	}
+	sort.SliceStable(result.Markers, func(i, j int) bool {
+		return result.Markers[i].Name < result.Markers[j].Name
+	})
+	for _, kind := range Kinds {
+		if kind == KindMatchGroup {
+			continue
+		}
+		var applied []*gov.Policy
+		for _, p := range policies[kind] {
+			spec := &struct {
+				MarkerName string `json:"match"`
+			}{}
+			if !Enabled(p) || !rolledOut(req, p) || decodeSpec(p.Spec, spec) != nil || !matched[spec.MarkerName] {
+				continue
+			}
+			p.Kind = kind
+			applied = append(applied, p)
+		}
+		sort.SliceStable(applied, func(i, j int) bool {
+			return applied[i].Name < applied[j].Name
+		})
+		result.Policies = append(result.Policies, applied...)
+	}
+	return result
+}
+
+//rolledOut returns true if the policy is not in rollout, or the instance of req is selected by the rollout,
+//the progressing ones are taken as applied if req has no instance, the aborted ones apply to no instance
+func rolledOut(req *gov.SimulateRequest, p *gov.Policy) bool {
+	if p.Rollout == nil {
+		return true
+	}
+	if p.Rollout.Status == gov.RolloutAborted {
+		return false
+	}
+	if len(req.InstanceID) == 0 {
+		return true
+	}
+	for _, id := range p.Rollout.Instances {
+		if id == req.InstanceID {
+			return true
+		}
+	}
+	return false
+}
+
+//evaluate matches the request with all the matches of the match group to report all the invalid operators
+func evaluate(req *gov.SimulateRequest, p *gov.Policy) *gov.MarkerResult {
+	r := &gov.MarkerResult{ID: p.ID, Name: p.Name}
+	spec := &gov.MatchSpec{}
+	if err := decodeSpec(p.Spec, spec); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+		return r
+	}
+	for i, mp := range spec.MatchPolicies {
+		if mp == nil {
+			continue
+		}
+		name := mp.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("matches[%d]", i)
+		}
+		ok, err := mp.Match(req.Method, req.Path, req.Headers)
+		if err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		if ok && !r.Matched {
+			r.Matched, r.Match = true, name
+		}
+	}
+	return r
+}
+
+func withDrafts(policies map[string][]*gov.Policy, drafts []*gov.Policy) map[string][]*gov.Policy {
+	if len(drafts) == 0 {
+		return policies
+	}
+	r := make(map[string][]*gov.Policy, len(policies))
+	replaced := make(map[string]bool)
+	for _, d := range drafts {
+		if d == nil || d.GovernancePolicy == nil || len(d.Kind) == 0 {
+			continue
+		}
+		r[d.Kind] = append(r[d.Kind], d)
+		replaced[d.Kind+"/"+d.Name] = true
+	}
+	for kind, list := range policies {
+		for _, p := range list {
+			if !replaced[kind+"/"+p.Name] {
+				r[kind] = append(r[kind], p)
+			}
+		}
+	}
+	return r
+}
+
+func decodeSpec(spec, v interface{}) error {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
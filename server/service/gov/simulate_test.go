@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSimulatePolicy(name, status string, spec interface{}) *gov.Policy {
+	return &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{ID: name, Name: name, Status: status},
+		Spec:             spec,
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	policies := map[string][]*gov.Policy{
+		"match-group": {
+			newSimulatePolicy("scene-pay", "", &gov.MatchSpec{MatchPolicies: []*gov.MatchPolicy{
+				{Name: "query", APIPaths: map[string]string{"prefix": "/pay"}, Methods: []string{"GET"}},
+				{Name: "vip", Headers: map[string]map[string]string{"X-User": {"regex": "vip-.*"}}},
+			}}),
+			newSimulatePolicy("scene-order", "", &gov.MatchSpec{MatchPolicies: []*gov.MatchPolicy{
+				{APIPaths: map[string]string{"exact": "/order"}},
+				{APIPaths: map[string]string{"like": "/order"}},
+			}}),
+			newSimulatePolicy("scene-disabled", "disabled", &gov.MatchSpec{}),
+		},
+		"rate-limiting": {
+			newSimulatePolicy("limit-pay", "enabled", &gov.LimiterSpec{MarkerName: "scene-pay", Rate: 10}),
+			newSimulatePolicy("limit-order", "enabled", &gov.LimiterSpec{MarkerName: "scene-order", Rate: 10}),
+		},
+		"retry": {
+			newSimulatePolicy("retry-pay", "disabled", &gov.LBSpec{MarkerName: "scene-pay", RetryNext: 1}),
+		},
+		"circuit-breaker": {
+			newSimulatePolicy("breaker-pay", "", &gov.CircuitBreakerSpec{MarkerName: "scene-pay", FailureRateThreshold: 50}),
+		},
+	}
+
+	t.Run("the matched match groups should apply the enabled policies", func(t *testing.T) {
+		r := simulate(&gov.SimulateRequest{Method: "POST", Path: "/pay/order", Headers: map[string]string{"x-user": "vip-jack"}}, policies)
+		assert.Equal(t, 2, len(r.Markers))
+		assert.Equal(t, "scene-order", r.Markers[0].Name)
+		assert.False(t, r.Markers[0].Matched)
+		assert.Equal(t, []string{"matches[1]: unknown operator like"}, r.Markers[0].Errors)
+		assert.Equal(t, "scene-pay", r.Markers[1].Name)
+		assert.True(t, r.Markers[1].Matched)
+		assert.Equal(t, "vip", r.Markers[1].Match)
+
+		assert.Equal(t, 2, len(r.Policies))
+		assert.Equal(t, "rate-limiting", r.Policies[0].Kind)
+		assert.Equal(t, "limit-pay", r.Policies[0].Name)
+		assert.Equal(t, "circuit-breaker", r.Policies[1].Kind)
+	})
+
+	t.Run("the drafts should replace the stored policies", func(t *testing.T) {
+		draft := newSimulatePolicy("scene-pay", "", &gov.MatchSpec{MatchPolicies: []*gov.MatchPolicy{
+			{APIPaths: map[string]string{"exact": "/pay"}},
+		}})
+		draft.Kind = "match-group"
+		r := simulate(&gov.SimulateRequest{Method: "GET", Path: "/pay/order", Policies: []*gov.Policy{draft}}, policies)
+		assert.Equal(t, 2, len(r.Markers))
+		for _, m := range r.Markers {
+			assert.False(t, m.Matched)
+		}
+		assert.Empty(t, r.Policies)
+	})
+
+	t.Run("the policies in rollout should apply to the selected instances", func(t *testing.T) {
+		limiter := policies["rate-limiting"][0]
+		limiter.Rollout = &gov.Rollout{Status: gov.RolloutProgressing, Instances: []string{"i1"}}
+		defer func() {
+			limiter.Rollout = nil
+		}()
+		req := &gov.SimulateRequest{Method: "GET", Path: "/pay", InstanceID: "i2"}
+		r := simulate(req, policies)
+		assert.Equal(t, 1, len(r.Policies))
+		assert.Equal(t, "breaker-pay", r.Policies[0].Name)
+
+		req.InstanceID = "i1"
+		r = simulate(req, policies)
+		assert.Equal(t, 2, len(r.Policies))
+		assert.Equal(t, "limit-pay", r.Policies[0].Name)
+		assert.Equal(t, gov.RolloutProgressing, r.Policies[0].Rollout.Status)
+
+		req.InstanceID = ""
+		r = simulate(req, policies)
+		assert.Equal(t, 2, len(r.Policies))
+	})
+
+	t.Run("the policies of the aborted rollouts should apply to no instance", func(t *testing.T) {
+		limiter := policies["rate-limiting"][0]
+		limiter.Rollout = &gov.Rollout{Status: gov.RolloutAborted}
+		defer func() {
+			limiter.Rollout = nil
+		}()
+		for _, id := range []string{"", "i1"} {
+			r := simulate(&gov.SimulateRequest{Method: "GET", Path: "/pay", InstanceID: id}, policies)
+			assert.Equal(t, 1, len(r.Policies))
+			assert.Equal(t, "breaker-pay", r.Policies[0].Name)
+		}
+	})
+}
@@ -0,0 +1,507 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/backoff"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+
+	// syncLeaderPrefix prefixes the election id of reconciling a distributor
+	syncLeaderPrefix = "gov-sync-"
+)
+
+//Kinds are the policy kinds reconciled between the distributors
+var Kinds = []string{"match-group", "retry", "rate-limiting", "circuit-breaker", "bulkhead",
+	"fault-injection", "instance-isolation", "loadbalancer"}
+
+//KindSupporter is implemented by the distributors can not store some kinds of policies,
+//the policies of the kinds not supported are not replicated to them
+type KindSupporter interface {
+	Supports(kind string) bool
+}
+
+//Operation is a write succeeded in the primary, ID is the id in the primary,
+//Key identifies the policy in every distributor, since every distributor generates its own ids
+type Operation struct {
+	Action  string
+	Kind    string
+	Project string
+	ID      string
+	Key     string
+	Spec    []byte
+	// retries is the count of the failed attempts
+	retries int
+}
+
+type SyncOptions struct {
+	// Retries is the max retries of an operation
+	Retries int
+	Backoff backoff.Backoff
+	// ReconcileInterval is the interval of repairing the drift, 0 disables the reconciliation
+	ReconcileInterval time.Duration
+	// Projects are always reconciled, besides the projects written since started
+	Projects  []string
+	QueueSize int
+}
+
+//TargetStatus is the replication status of a distributor
+type TargetStatus struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Primary bool   `json:"primary,omitempty"`
+	// Synced is false if the last operation failed or the drift is not repaired
+	Synced   bool  `json:"synced"`
+	Pending  int   `json:"pending"`
+	Failures int64 `json:"failures"`
+	// Repaired is the count of the policies repaired by reconciliation
+	Repaired     int64  `json:"repaired"`
+	LastError    string `json:"lastError,omitempty"`
+	LastSyncTime int64  `json:"lastSyncTime,omitempty"`
+}
+
+//Target replicates the writes of the primary to a distributor,
+//the operations are applied in order, the failed ones are retried after backoff,
+//and the drift is repaired by reconciliation
+type Target struct {
+	cd      ConfigDistributor
+	primary ConfigDistributor
+	opts    SyncOptions
+	ops     chan *Operation
+
+	mux    sync.Mutex
+	status TargetStatus
+	// projects are the projects need to be reconciled, true if it is drifted
+	projects map[string]bool
+}
+
+func NewSyncOptions() SyncOptions {
+	return SyncOptions{
+		Retries:           config.GetInt("gov.sync.retries", 3),
+		Backoff:           backoff.GetBackoff(),
+		ReconcileInterval: config.GetDuration("gov.sync.reconcileInterval", time.Minute),
+		Projects:          strings.Split(config.GetString("gov.sync.projects", "default"), ","),
+		QueueSize:         1000,
+	}
+}
+
+func NewTarget(cd, primary ConfigDistributor, opts SyncOptions) *Target {
+	t := &Target{
+		cd:       cd,
+		primary:  primary,
+		opts:     opts,
+		ops:      make(chan *Operation, opts.QueueSize),
+		status:   TargetStatus{Name: cd.Name(), Type: cd.Type(), Synced: true},
+		projects: make(map[string]bool),
+	}
+	for _, project := range opts.Projects {
+		if project = strings.TrimSpace(project); len(project) > 0 {
+			t.projects[project] = false
+		}
+	}
+	return t
+}
+
+//Publish queues the operation, the project is drifted if the queue is full
+func (t *Target) Publish(op *Operation) {
+	select {
+	case t.ops <- op:
+		t.mux.Lock()
+		if _, ok := t.projects[op.Project]; !ok {
+			t.projects[op.Project] = false
+		}
+		t.mux.Unlock()
+	default:
+		log.Warn(fmt.Sprintf("replication queue of distributor %s is full, drop the %s operation", t.cd.Name(), op.Action))
+		t.drift(op.Project, errors.New("replication queue is full"))
+	}
+}
+
+//Run applies the operations and reconciles periodically until ctx is done,
+//all the projects are reconciled by the elected replica, the others only repair their own drifted projects
+func (t *Target) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if t.opts.ReconcileInterval > 0 {
+		ticker := time.NewTicker(t.opts.ReconcileInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	leader := false
+	defer func() {
+		if leader {
+			t.resign()
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case op := <-t.ops:
+			t.Apply(op)
+		case <-tick:
+			leader = t.elect(ctx)
+			if leader {
+				t.ReconcileAll()
+				continue
+			}
+			t.ReconcileDrifted()
+		}
+	}
+}
+
+//Apply applies the operation once, the failed one is queued again after backoff,
+//so it does not block the following operations
+func (t *Target) Apply(op *Operation) {
+	err := t.apply(op)
+	if err == nil {
+		t.mux.Lock()
+		t.status.LastSyncTime = time.Now().Unix()
+		t.mux.Unlock()
+		return
+	}
+	log.Error(fmt.Sprintf("%s policy %s/%s in distributor %s failed, retries: %d",
+		op.Action, op.Project, op.ID, t.cd.Name(), op.retries), err)
+	if op.retries >= t.opts.Retries {
+		t.drift(op.Project, err)
+		return
+	}
+	op.retries++
+	time.AfterFunc(t.opts.Backoff.Delay(op.retries-1), func() {
+		t.Publish(op)
+	})
+}
+
+func (t *Target) apply(op *Operation) error {
+	if !supports(t.cd, op.Kind) {
+		return nil
+	}
+	if op.retries > 0 {
+		// the following operations of the policy may be applied during the backoff,
+		// so the retry copies the latest policy in the primary instead of replaying the operation
+		_, err := t.reconcile(op.Kind, op.Project, op.Key)
+		return err
+	}
+	a, err := t.find(op.Kind, op.Project, op.Key)
+	if err != nil {
+		return err
+	}
+	switch {
+	case op.Action == ActionDelete:
+		if a == nil {
+			return nil
+		}
+		return t.cd.Delete(a.ID, op.Project)
+	case a == nil:
+		// the policy is created before started or its creation failed
+		_, err = t.cd.Create(op.Kind, op.Project, op.Spec)
+		return err
+	default:
+		return t.cd.Update(a.ID, op.Kind, op.Project, op.Spec)
+	}
+}
+
+//find returns the policy of key in the distributor, or nil if it does not exist
+func (t *Target) find(kind, project, key string) (*gov.Policy, error) {
+	policies, err := listPolicies(t.cd, kind, project)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		if policyKey(p) == key {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+//elect returns true if this replica reconciles all the projects of the distributor,
+//the lease lasts several rounds, so the leader keeps it even if one renewal failed
+func (t *Target) elect(ctx context.Context) bool {
+	err := datasource.Instance().Campaign(ctx, &datasource.CampaignRequest{
+		ID:  syncLeaderPrefix + t.cd.Name(),
+		TTL: int64(3*t.opts.ReconcileInterval/time.Second) + 1,
+	})
+	if err != nil && err != datasource.ErrNotLeader {
+		log.Error(fmt.Sprintf("campaign reconciling distributor %s failed", t.cd.Name()), err)
+	}
+	return err == nil
+}
+
+func (t *Target) resign() {
+	err := datasource.Instance().Resign(context.Background(), &datasource.ResignRequest{ID: syncLeaderPrefix + t.cd.Name()})
+	if err != nil {
+		log.Error(fmt.Sprintf("resign reconciling distributor %s failed", t.cd.Name()), err)
+	}
+}
+
+//ReconcileAll reconciles the projects written since started and the configured ones
+func (t *Target) ReconcileAll() {
+	t.reconcileProjects(false)
+}
+
+//ReconcileDrifted reconciles the projects failed to replicate the writes of this replica
+func (t *Target) ReconcileDrifted() {
+	t.reconcileProjects(true)
+}
+
+func (t *Target) reconcileProjects(driftedOnly bool) {
+	t.mux.Lock()
+	projects := make([]string, 0, len(t.projects))
+	for project, drifted := range t.projects {
+		if drifted || !driftedOnly {
+			projects = append(projects, project)
+		}
+	}
+	t.mux.Unlock()
+	for _, project := range projects {
+		err := t.Reconcile(project)
+		if err != nil {
+			log.Error(fmt.Sprintf("reconcile project %s of distributor %s failed", project, t.cd.Name()), err)
+			t.drift(project, err)
+		}
+	}
+}
+
+//Reconcile makes the policies of project in the distributor the same as the primary,
+//the policies are identified by the kind, name and selector.
+//A policy failed to repair does not stop repairing the others, the errors are returned together
+func (t *Target) Reconcile(project string) error {
+	var (
+		repaired int64
+		errs     []string
+	)
+	for _, kind := range Kinds {
+		if !supports(t.cd, kind) {
+			continue
+		}
+		n, err := t.reconcile(kind, project, "")
+		repaired += n
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if repaired > 0 {
+		log.Warn(fmt.Sprintf("repaired %d drifted policies of project %s in distributor %s", repaired, project, t.cd.Name()))
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.status.Repaired += repaired
+	t.status.LastSyncTime = time.Now().Unix()
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	t.projects[project] = false
+	for _, drifted := range t.projects {
+		if drifted {
+			return nil
+		}
+	}
+	t.status.Synced = true
+	t.status.LastError = ""
+	return nil
+}
+
+//reconcile repairs the policies of kind, or only the policy of key if it is not empty,
+//it returns the count of the repaired policies and the errors of the ones failed to repair
+func (t *Target) reconcile(kind, project, key string) (int64, error) {
+	expected, err := listPolicies(t.primary, kind, project)
+	if err != nil {
+		return 0, err
+	}
+	actual, err := listPolicies(t.cd, kind, project)
+	if err != nil {
+		return 0, err
+	}
+	index := make(map[string]*gov.Policy, len(actual))
+	for _, p := range actual {
+		if len(key) == 0 || policyKey(p) == key {
+			index[policyKey(p)] = p
+		}
+	}
+	var (
+		repaired int64
+		errs     []string
+	)
+	repair := func(action string, p *gov.Policy, err error) {
+		switch {
+		case err == nil:
+			repaired++
+		case len(key) > 0:
+			// the operation of the policy logs the error with its id
+			errs = append(errs, err.Error())
+		default:
+			errs = append(errs, fmt.Sprintf("%s %s policy %s: %s", action, kind, policyKey(p), err.Error()))
+		}
+	}
+	for _, p := range expected {
+		if len(key) > 0 && policyKey(p) != key {
+			continue
+		}
+		a, ok := index[policyKey(p)]
+		delete(index, policyKey(p))
+		if !ok {
+			_, err = t.cd.Create(kind, project, toSpec(p))
+			repair(ActionCreate, p, err)
+			continue
+		}
+		if p.Status != a.Status || !reflect.DeepEqual(p.Spec, a.Spec) {
+			repair(ActionUpdate, p, t.cd.Update(a.ID, kind, project, toSpec(p)))
+		}
+	}
+	for _, a := range index {
+		repair(ActionDelete, a, t.cd.Delete(a.ID, project))
+	}
+	if len(errs) > 0 {
+		return repaired, errors.New(strings.Join(errs, "; "))
+	}
+	return repaired, nil
+}
+
+//Status returns the replication status
+func (t *Target) Status() *TargetStatus {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	status := t.status
+	status.Pending = len(t.ops)
+	return &status
+}
+
+func (t *Target) drift(project string, err error) {
+	t.mux.Lock()
+	t.projects[project] = true
+	t.status.Synced = false
+	t.status.Failures++
+	t.status.LastError = err.Error()
+	t.mux.Unlock()
+}
+
+//Status returns the status of the primary and the replication status of the others
+func Status() []*TargetStatus {
+	if primary == nil {
+		return nil
+	}
+	status := []*TargetStatus{{Name: primary.Name(), Type: primary.Type(), Primary: true, Synced: true}}
+	for _, t := range targets {
+		status = append(status, t.Status())
+	}
+	return status
+}
+
+//DecodeID returns the id responded by the Create of distributor, some distributors encode it in json
+func DecodeID(b []byte) string {
+	var id string
+	if err := json.Unmarshal(b, &id); err == nil {
+		return id
+	}
+	return string(b)
+}
+
+func startSync(targets []*Target) {
+	for _, t := range targets {
+		gopool.Go(t.Run)
+	}
+}
+
+func publish(op *Operation) {
+	for _, t := range targets {
+		t.Publish(op)
+	}
+}
+
+func markDrift(project string) {
+	for _, t := range targets {
+		t.drift(project, errors.New("the policy is not replicated"))
+	}
+}
+
+func getPolicy(cd ConfigDistributor, kind, id, project string) (*gov.Policy, error) {
+	b, err := cd.Get(kind, id, project)
+	if err != nil {
+		return nil, err
+	}
+	p := &gov.Policy{}
+	err = json.Unmarshal(b, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.GovernancePolicy == nil {
+		p.GovernancePolicy = &gov.GovernancePolicy{}
+	}
+	return p, nil
+}
+
+func listPolicies(cd ConfigDistributor, kind, project string) ([]*gov.Policy, error) {
+	b, err := cd.List(kind, project, "", EnvAll)
+	if err != nil {
+		return nil, err
+	}
+	var policies []*gov.Policy
+	err = json.Unmarshal(b, &policies)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]*gov.Policy, 0, len(policies))
+	for _, p := range policies {
+		if p != nil && p.GovernancePolicy != nil {
+			r = append(r, p)
+		}
+	}
+	return r, nil
+}
+
+//supports returns false if the distributor can not store the policies of kind
+func supports(cd ConfigDistributor, kind string) bool {
+	s, ok := cd.(KindSupporter)
+	return !ok || s.Supports(kind)
+}
+
+func policyKey(p *gov.Policy) string {
+	return p.Name + "/" + p.Selector.App + "/" + p.Selector.Environment
+}
+
+//toSpec returns the request body of creating the policy in another distributor
+func toSpec(p *gov.Policy) []byte {
+	b, _ := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     p.Name,
+			Status:   p.Status,
+			Selector: p.Selector,
+		},
+		Spec: p.Spec,
+	})
+	return b
+}
@@ -0,0 +1,313 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/backoff"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+//memory is a distributor keeps the policies in memory, the ids are json encoded like kie
+type memory struct {
+	name     string
+	mux      sync.Mutex
+	seq      int
+	policies map[string]*gov.Policy
+	// failures is the count of the next writes fail
+	failures int
+}
+
+func newMemory(name string) *memory {
+	return &memory{name: name, policies: make(map[string]*gov.Policy)}
+}
+
+func (m *memory) fail() error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func (m *memory) Create(kind, project string, spec []byte) ([]byte, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if err := m.fail(); err != nil {
+		return nil, err
+	}
+	p := &gov.Policy{}
+	if err := json.Unmarshal(spec, p); err != nil {
+		return nil, err
+	}
+	m.seq++
+	p.ID = fmt.Sprintf("%s-%d", m.name, m.seq)
+	p.Kind = kind
+	m.policies[p.ID] = p
+	return json.Marshal(p.ID)
+}
+
+func (m *memory) Update(id, kind, project string, spec []byte) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if err := m.fail(); err != nil {
+		return err
+	}
+	old, ok := m.policies[id]
+	if !ok {
+		return errors.New("not exist")
+	}
+	p := &gov.Policy{}
+	if err := json.Unmarshal(spec, p); err != nil {
+		return err
+	}
+	old.Spec = p.Spec
+	return nil
+}
+
+func (m *memory) Delete(id, project string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if err := m.fail(); err != nil {
+		return err
+	}
+	delete(m.policies, id)
+	return nil
+}
+
+func (m *memory) Display(project, app, env string) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *memory) List(kind, project, app, env string) ([]byte, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	r := make([]*gov.Policy, 0)
+	for _, p := range m.policies {
+		if p.Kind == kind {
+			r = append(r, p)
+		}
+	}
+	return json.Marshal(r)
+}
+
+func (m *memory) Get(kind, id, project string) ([]byte, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	p, ok := m.policies[id]
+	if !ok {
+		return nil, errors.New("not exist")
+	}
+	return json.Marshal(p)
+}
+
+func (m *memory) Type() string {
+	return "memory"
+}
+
+func (m *memory) Name() string {
+	return m.name
+}
+
+func (m *memory) specs() map[string]interface{} {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	r := make(map[string]interface{})
+	for _, p := range m.policies {
+		r[p.Kind+"/"+policyKey(p)] = p.Spec
+	}
+	return r
+}
+
+//partial is a distributor can not store the circuit breakers
+type partial struct {
+	*memory
+}
+
+func (p *partial) Supports(kind string) bool {
+	return kind != "circuit-breaker"
+}
+
+func newSpec(name string, retries int) []byte {
+	b, _ := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{Name: name, Selector: gov.Selector{App: "app"}},
+		Spec:             map[string]interface{}{"retryNext": retries},
+	})
+	return b
+}
+
+func newTestTarget(from, to *memory) *Target {
+	return NewTarget(to, from, SyncOptions{
+		Retries:   2,
+		Backoff:   &backoff.PowerBackoff{InitDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1},
+		Projects:  []string{"default"},
+		QueueSize: 1,
+	})
+}
+
+//retried returns the failed operation queued again after backoff
+func retried(t *testing.T, target *Target) *Operation {
+	select {
+	case op := <-target.ops:
+		return op
+	case <-time.After(10 * time.Second):
+		t.Fatal("the failed operation is not retried")
+		return nil
+	}
+}
+
+func TestTarget_Apply(t *testing.T) {
+	from, to := newMemory("from"), newMemory("to")
+	target := newTestTarget(from, to)
+
+	id, err := from.Create("retry", "default", newSpec("a", 1))
+	assert.NoError(t, err)
+	target.Apply(&Operation{Action: ActionCreate, Kind: "retry", Project: "default", ID: DecodeID(id), Key: "a/app/", Spec: newSpec("a", 1)})
+	assert.Equal(t, from.specs(), to.specs())
+
+	t.Run("retry the failed writes with the latest policy", func(t *testing.T) {
+		assert.NoError(t, from.Update(DecodeID(id), "retry", "default", newSpec("a", 2)))
+		to.failures = 1
+		target.Apply(&Operation{Action: ActionUpdate, Kind: "retry", Project: "default", ID: DecodeID(id), Key: "a/app/", Spec: newSpec("a", 2)})
+		// the following operations are not blocked by the retry
+		assert.NoError(t, from.Update(DecodeID(id), "retry", "default", newSpec("a", 3)))
+		target.Apply(&Operation{Action: ActionUpdate, Kind: "retry", Project: "default", ID: DecodeID(id), Key: "a/app/", Spec: newSpec("a", 3)})
+		assert.Equal(t, from.specs(), to.specs())
+		target.Apply(retried(t, target))
+		assert.Equal(t, from.specs(), to.specs())
+		assert.True(t, target.Status().Synced)
+	})
+
+	t.Run("drift if retries exhausted", func(t *testing.T) {
+		assert.NoError(t, from.Delete(DecodeID(id), "default"))
+		to.failures = 3
+		target.Apply(&Operation{Action: ActionDelete, Kind: "retry", Project: "default", ID: DecodeID(id), Key: "a/app/"})
+		target.Apply(retried(t, target))
+		target.Apply(retried(t, target))
+		assert.Equal(t, 1, len(to.specs()))
+		status := target.Status()
+		assert.False(t, status.Synced)
+		assert.Equal(t, int64(1), status.Failures)
+		assert.Equal(t, "unavailable", status.LastError)
+
+		target.ReconcileAll()
+		assert.Empty(t, to.specs())
+		status = target.Status()
+		assert.True(t, status.Synced)
+		assert.Equal(t, int64(1), status.Repaired)
+	})
+
+	t.Run("drift if queue is full", func(t *testing.T) {
+		target.Publish(&Operation{Action: ActionCreate, Kind: "retry", Project: "default", ID: "x", Key: "x/app/", Spec: newSpec("x", 1)})
+		target.Publish(&Operation{Action: ActionCreate, Kind: "retry", Project: "default", ID: "y", Key: "y/app/", Spec: newSpec("y", 1)})
+		status := target.Status()
+		assert.False(t, status.Synced)
+		assert.Equal(t, 1, status.Pending)
+	})
+}
+
+func TestTarget_Reconcile(t *testing.T) {
+	from, to := newMemory("from"), newMemory("to")
+	target := newTestTarget(from, to)
+
+	_, err := from.Create("retry", "default", newSpec("missing", 1))
+	assert.NoError(t, err)
+	changed, err := from.Create("retry", "default", newSpec("changed", 1))
+	assert.NoError(t, err)
+	_, err = from.Create("match-group", "default", newSpec("changed", 1))
+	assert.NoError(t, err)
+	_, err = to.Create("retry", "default", newSpec("changed", 2))
+	assert.NoError(t, err)
+	_, err = to.Create("match-group", "default", newSpec("changed", 1))
+	assert.NoError(t, err)
+	_, err = to.Create("retry", "default", newSpec("extra", 1))
+	assert.NoError(t, err)
+
+	assert.NoError(t, target.Reconcile("default"))
+	assert.Equal(t, from.specs(), to.specs())
+	assert.Equal(t, int64(3), target.Status().Repaired)
+
+	t.Run("the writes are applied to the policies of the same key", func(t *testing.T) {
+		id := DecodeID(changed)
+		assert.NoError(t, from.Update(id, "retry", "default", newSpec("changed", 3)))
+		target.Apply(&Operation{Action: ActionUpdate, Kind: "retry", Project: "default", ID: id, Key: "changed/app/", Spec: newSpec("changed", 3)})
+		assert.Equal(t, from.specs(), to.specs())
+		assert.Equal(t, int64(3), target.Status().Repaired)
+	})
+
+	t.Run("the writes of the missing policies are repaired", func(t *testing.T) {
+		other := newTestTarget(from, newMemory("other"))
+		other.Apply(&Operation{Action: ActionUpdate, Kind: "retry", Project: "default", ID: DecodeID(changed), Key: "changed/app/", Spec: newSpec("changed", 3)})
+		other.Apply(&Operation{Action: ActionDelete, Kind: "retry", Project: "default", ID: "unknown", Key: "unknown/app/"})
+		assert.Equal(t, 1, len(other.cd.(*memory).specs()))
+		assert.True(t, other.Status().Synced)
+	})
+
+	t.Run("reconcile the drifted projects only", func(t *testing.T) {
+		other := newTestTarget(from, newMemory("other"))
+		other.ReconcileDrifted()
+		assert.Empty(t, other.cd.(*memory).specs())
+		other.drift("default", errors.New("unavailable"))
+		other.ReconcileDrifted()
+		assert.Equal(t, from.specs(), other.cd.(*memory).specs())
+		assert.True(t, other.Status().Synced)
+	})
+}
+
+func TestTarget_ReconcilePartially(t *testing.T) {
+	from, to := newMemory("from"), newMemory("to")
+	target := NewTarget(&partial{to}, from, SyncOptions{Projects: []string{"default"}, QueueSize: 1})
+
+	_, err := from.Create("circuit-breaker", "default", newSpec("a", 1))
+	assert.NoError(t, err)
+	_, err = from.Create("bulkhead", "default", newSpec("a", 1))
+	assert.NoError(t, err)
+	_, err = from.Create("retry", "default", newSpec("a", 1))
+	assert.NoError(t, err)
+
+	t.Run("the kinds not supported are skipped", func(t *testing.T) {
+		target.Apply(&Operation{Action: ActionCreate, Kind: "circuit-breaker", Project: "default", Key: "a/app/", Spec: newSpec("a", 1)})
+		assert.NoError(t, target.Reconcile("default"))
+		assert.Equal(t, 2, len(to.specs()))
+		assert.True(t, target.Status().Synced)
+	})
+
+	t.Run("a failed policy does not block repairing the others", func(t *testing.T) {
+		_, err = from.Create("fault-injection", "default", newSpec("b", 1))
+		assert.NoError(t, err)
+		_, err = from.Create("instance-isolation", "default", newSpec("b", 1))
+		assert.NoError(t, err)
+		to.failures = 1
+		target.ReconcileAll()
+		assert.Equal(t, 3, len(to.specs()))
+		status := target.Status()
+		assert.False(t, status.Synced)
+		assert.Contains(t, status.LastError, "create")
+
+		target.ReconcileAll()
+		assert.Equal(t, 4, len(to.specs()))
+		assert.True(t, target.Status().Synced)
+	})
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	apt "github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/health"
+	"github.com/apache/servicecomb-service-center/server/heartbeat"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -45,8 +46,12 @@ func (s *InstanceService) Register(ctx context.Context, in *pb.RegisterInstanceR
 	remoteIP := util.GetIPFromContext(ctx)
 	instanceFlag := fmt.Sprintf("endpoints %v, host '%s', serviceID %s",
 		in.Instance.Endpoints, in.Instance.HostName, in.Instance.ServiceId)
+	if resp := reuseInstance(ctx, in.Instance); resp != nil {
+		return resp, nil
+	}
 	domainProject := util.ParseDomainProject(ctx)
-	quotaErr := checkInstanceQuota(ctx, domainProject, in.Instance.ServiceId)
+	release, quotaErr := checkInstanceQuota(ctx, domainProject, in.Instance.ServiceId)
+	defer release()
 	if quotaErr != nil {
 		log.Error(fmt.Sprintf("register instance failed, %s, operator %s",
 			instanceFlag, remoteIP), quotaErr)
@@ -72,6 +77,9 @@ func (s *InstanceService) Unregister(ctx context.Context,
 		}, nil
 	}
 
+	if a := heartbeat.Instance(); a != nil {
+		a.Forget(util.ParseDomainProject(ctx), in.ServiceId, in.InstanceId)
+	}
 	return datasource.Instance().UnregisterInstance(ctx, in)
 }
 
@@ -84,6 +92,9 @@ func (s *InstanceService) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest
 		}, nil
 	}
 
+	if a := heartbeat.Instance(); a != nil {
+		return a.Heartbeat(ctx, in)
+	}
 	return datasource.Instance().Heartbeat(ctx, in)
 }
 
@@ -95,6 +106,9 @@ func (s *InstanceService) HeartbeatSet(ctx context.Context,
 			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request format invalid."),
 		}, nil
 	}
+	if a := heartbeat.Instance(); a != nil {
+		return a.HeartbeatSet(ctx, in)
+	}
 	return datasource.Instance().HeartbeatSet(ctx, in)
 }
 
@@ -225,12 +239,48 @@ func (s *InstanceService) ClusterHealth(ctx context.Context) (*pb.GetInstancesRe
 	}, nil
 }
 
-func checkInstanceQuota(ctx context.Context, domainProject string, serviceID string) *pb.Error {
-	if !apt.IsSCInstance(ctx) {
-		res := quota.NewApplyQuotaResource(quota.TypeInstance,
-			domainProject, serviceID, 1)
-		err := quota.Apply(ctx, res)
-		return err
+// reuseInstance renews the lease of the instance registered again with its id,
+// it does not need the quota, nil means the instance should be registered
+func reuseInstance(ctx context.Context, instance *pb.MicroServiceInstance) *pb.RegisterInstanceResponse {
+	if len(instance.InstanceId) == 0 {
+		return nil
 	}
-	return nil
+	resp, err := datasource.Instance().Heartbeat(ctx, &pb.HeartbeatRequest{
+		ServiceId:  instance.ServiceId,
+		InstanceId: instance.InstanceId,
+	})
+	if err != nil || resp.Response.GetCode() != pb.ResponseSuccess {
+		return nil
+	}
+	log.Info(fmt.Sprintf("register instance successful, reuse instance[%s/%s], operator %s",
+		instance.ServiceId, instance.InstanceId, util.GetIPFromContext(ctx)))
+	return &pb.RegisterInstanceResponse{
+		Response:   resp.Response,
+		InstanceId: instance.InstanceId,
+	}
+}
+
+// checkInstanceQuota keeps the instance quotas of domain project and of the service reserved
+// until release is called, the release frees both reservations
+func checkInstanceQuota(ctx context.Context, domainProject string, serviceID string) (release func(), err *pb.Error) {
+	if apt.IsSCInstance(ctx) {
+		return func() {}, nil
+	}
+	res := quota.NewApplyQuotaResource(quota.TypeInstance,
+		domainProject, serviceID, 1)
+	releaseDomain, err := quota.Acquire(ctx, res)
+	if err != nil {
+		return releaseDomain, err
+	}
+	res = quota.NewApplyQuotaResource(quota.TypeInstancePerService,
+		domainProject, serviceID, 1)
+	releaseService, err := quota.Acquire(ctx, res)
+	if err != nil {
+		releaseDomain()
+		return func() {}, err
+	}
+	return func() {
+		releaseService()
+		releaseDomain()
+	}, nil
 }
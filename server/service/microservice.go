@@ -27,6 +27,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/proto"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
@@ -51,6 +52,13 @@ func (s *MicroServiceService) Create(ctx context.Context, in *pb.CreateServiceRe
 			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request body is empty"),
 		}, nil
 	}
+	if !rbacframe.ScopeFromContext(ctx).MatchService(in.Service) {
+		log.Errorf(nil, "create micro-service[%s/%s] failed: labels are out of the permission scope",
+			in.Service.AppId, in.Service.ServiceName)
+		return &pb.CreateServiceResponse{
+			Response: pb.CreateResponse(pb.ErrPermissionDeny, "The service labels are out of the permission scope"),
+		}, nil
+	}
 
 	//create service
 	rsp, err := s.CreateServicePri(ctx, in)
@@ -82,7 +90,8 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
 		}, nil
 	}
-	quotaErr := checkServiceQuota(ctx, domainProject)
+	release, quotaErr := checkServiceQuota(ctx, domainProject)
+	defer release()
 	if quotaErr != nil {
 		log.Error(fmt.Sprintf("create micro-service[%s] failed, operator: %s",
 			serviceFlag, remoteIP), err)
@@ -218,7 +227,22 @@ func (s *MicroServiceService) GetOne(ctx context.Context, in *pb.GetServiceReque
 }
 
 func (s *MicroServiceService) GetServices(ctx context.Context, in *pb.GetServicesRequest) (*pb.GetServicesResponse, error) {
-	return datasource.Instance().GetServices(ctx, in)
+	resp, err := datasource.Instance().GetServices(ctx, in)
+	if err != nil {
+		return resp, err
+	}
+	scope := rbacframe.ScopeFromContext(ctx)
+	if scope == nil {
+		return resp, nil
+	}
+	services := make([]*pb.MicroService, 0, len(resp.Services))
+	for _, service := range resp.Services {
+		if scope.MatchService(service) {
+			services = append(services, service)
+		}
+	}
+	resp.Services = services
+	return resp, nil
 }
 
 func (s *MicroServiceService) UpdateProperties(ctx context.Context, in *pb.UpdateServicePropsRequest) (*pb.UpdateServicePropsResponse, error) {
@@ -366,12 +390,12 @@ func (s *MicroServiceService) isCreateServiceEx(in *pb.CreateServiceRequest) boo
 	return true
 }
 
-func checkServiceQuota(ctx context.Context, domainProject string) *pb.Error {
+// checkServiceQuota keeps the service quota reserved until release is called
+func checkServiceQuota(ctx context.Context, domainProject string) (release func(), err *pb.Error) {
 	if core.IsSCInstance(ctx) {
 		log.Debugf("skip quota check")
-		return nil
+		return func() {}, nil
 	}
 	res := quota.NewApplyQuotaResource(quota.TypeService, domainProject, "", 1)
-	rst := quota.Apply(ctx, res)
-	return rst
+	return quota.Acquire(ctx, res)
 }
@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	pkgquota "github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+)
+
+func ValidateQuotaLimits(l *pkgquota.Limits) error {
+	if len(l.Limits) == 0 {
+		return errors.New("limits is required")
+	}
+	for name, limit := range l.Limits {
+		t, ok := quota.ParseResourceType(name)
+		if !ok {
+			return fmt.Errorf("unknown resource type %s", name)
+		}
+		if t.Global() {
+			return fmt.Errorf("limit of %s can not be overridden by domain project", name)
+		}
+		if limit < 0 {
+			return fmt.Errorf("limit of %s should not be negative", name)
+		}
+	}
+	return nil
+}
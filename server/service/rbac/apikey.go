@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
+)
+
+//the last used time of a key is persisted at most once in this interval
+const lastUsedUpdateInterval = time.Minute
+
+var (
+	ErrEmptyAPIKeyName   = errors.New("api key name should not be empty")
+	ErrInvalidExpiration = errors.New("invalid api key expiration, should be a positive duration like 720h")
+	ErrAPIKeyRoleExceed  = errors.New("api key roles should be a subset of the account roles")
+	ErrInvalidAPIKey     = errors.New("invalid api key")
+	ErrAPIKeyExpired     = errors.New("api key is expired")
+	ErrAccountNotExist   = errors.New("account does not exist")
+)
+
+var lastUsed sync.Map // id -> time.Time
+
+//CreateAPIKey generates a new api key of account,
+//the returned key carries the plain key which can not be retrieved again
+func CreateAPIKey(ctx context.Context, account string, key *rbacframe.APIKey) (*rbacframe.APIKey, error) {
+	if len(key.Name) == 0 {
+		return nil, ErrEmptyAPIKeyName
+	}
+	exist, err := dao.AccountExist(ctx, account)
+	if err != nil {
+		log.Errorf(err, "check account [%s] failed", account)
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrAccountNotExist
+	}
+	a, err := dao.GetAccount(ctx, account)
+	if err != nil {
+		log.Errorf(err, "get account [%s] failed", account)
+		return nil, err
+	}
+	if len(key.Roles) == 0 {
+		key.Roles = a.Roles
+	}
+	for _, r := range key.Roles {
+		if !ableToAccessResource(a.Roles, r) {
+			return nil, ErrAPIKeyRoleExceed
+		}
+	}
+	now := time.Now()
+	key.ExpireTime = ""
+	if len(key.ExpireAfter) > 0 {
+		d, err := time.ParseDuration(key.ExpireAfter)
+		if err != nil || d <= 0 {
+			return nil, ErrInvalidExpiration
+		}
+		key.ExpireTime = strconv.FormatInt(now.Add(d).Unix(), 10)
+	}
+	id, plain, err := rbacframe.NewAPIKeySecret()
+	if err != nil {
+		log.Error("generate api key failed", err)
+		return nil, err
+	}
+	key.ID = id
+	key.Account = account
+	key.Hash = rbacframe.HashAPIKey(plain)
+	key.Key = ""
+	key.ExpireAfter = ""
+	key.LastUsedTime = ""
+	key.CreateTime = strconv.FormatInt(now.Unix(), 10)
+	err = dao.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	created := *key
+	created.Hash = ""
+	created.Key = plain
+	return &created, nil
+}
+
+//ListAPIKey returns the keys of account without the hashes
+func ListAPIKey(ctx context.Context, account string) ([]*rbacframe.APIKey, error) {
+	keys, err := dao.ListAPIKey(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		k.Hash = ""
+	}
+	return keys, nil
+}
+
+//RevokeAPIKey deletes the key of account
+func RevokeAPIKey(ctx context.Context, account, id string) error {
+	err := dao.DeleteAPIKey(ctx, account, id)
+	if err != nil {
+		return err
+	}
+	lastUsed.Delete(id)
+	log.Infof("api key [%s] of account [%s] is revoked", id, account)
+	return nil
+}
+
+//RevokeRoleAPIKeys revokes the api keys having role, it is called when the role is changed
+func RevokeRoleAPIKeys(ctx context.Context, role string) error {
+	accounts, _, err := dao.ListAccount(ctx)
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		keys, err := dao.ListAPIKey(ctx, a.Name)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !ableToAccessResource(key.Roles, role) {
+				continue
+			}
+			if err := RevokeAPIKey(ctx, a.Name, key.ID); err != nil && err != datasource.ErrAPIKeyNotExist {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//AuthenticateAPIKey verifies the plain key and records the last used time,
+//the roles of the returned key are limited to the current roles of the account
+func AuthenticateAPIKey(ctx context.Context, plain string) (*rbacframe.APIKey, error) {
+	id, ok := rbacframe.ParseAPIKeyID(plain)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := dao.GetAPIKey(ctx, id)
+	if err != nil {
+		if err == datasource.ErrAPIKeyNotExist {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if !key.Verify(plain) {
+		return nil, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if key.Expired(now) {
+		return nil, ErrAPIKeyExpired
+	}
+	exist, err := dao.AccountExist(ctx, key.Account)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrInvalidAPIKey
+	}
+	a, err := dao.GetAccount(ctx, key.Account)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]string, 0, len(key.Roles))
+	for _, r := range key.Roles {
+		if ableToAccessResource(a.Roles, r) {
+			roles = append(roles, r)
+		}
+	}
+	if len(roles) == 0 {
+		log.Warnf("api key [%s] of account [%s] has none of the account roles", id, key.Account)
+		return nil, ErrInvalidAPIKey
+	}
+	key.Roles = roles
+	touchAPIKey(id, now)
+	return key, nil
+}
+
+func touchAPIKey(id string, now time.Time) {
+	if t, ok := lastUsed.Load(id); ok && now.Sub(t.(time.Time)) < lastUsedUpdateInterval {
+		return
+	}
+	lastUsed.Store(id, now)
+	gopool.Go(func(ctx context.Context) {
+		err := dao.UpdateAPIKeyLastUsed(ctx, id, strconv.FormatInt(now.Unix(), 10))
+		if err != nil {
+			log.Errorf(err, "update last used time of api key [%s] failed", id)
+		}
+	})
+}
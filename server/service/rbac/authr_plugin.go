@@ -20,14 +20,14 @@ package rbac
 import (
 	"context"
 	"errors"
-	"github.com/apache/servicecomb-service-center/pkg/privacy"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/privacy"
 	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
-	"github.com/dgrijalva/jwt-go"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
-	"github.com/go-chassis/go-chassis/v2/security/token"
 )
 
 var ErrUnauthorized = errors.New("wrong user name or password")
@@ -53,32 +53,52 @@ func (a *EmbeddedAuthenticator) Login(ctx context.Context, user string, password
 	}
 	same := privacy.SamePassword(account.Password, password)
 	if user == account.Name && same {
-		secret, err := GetPrivateKey()
-		if err != nil {
-			return "", err
+		if PasswordExpired(ctx, user) {
+			// the token can only be used to change the password
+			return signToken(user, account.Roles, opt.ExpireAfter,
+				map[string]interface{}{rbacframe.ClaimsPasswordExpired: true})
 		}
-		tokenStr, err := token.Sign(map[string]interface{}{
-			rbacframe.ClaimsUser:  user,
-			rbacframe.ClaimsRoles: account.Roles,
-		},
-			secret,
-			token.WithExpTime(opt.ExpireAfter),
-			token.WithSigningMethod(token.RS512)) //TODO config for each user
-		if err != nil {
-			log.Errorf(err, "can not sign a token")
-			return "", err
-		}
-		return tokenStr, nil
+		return signToken(user, account.Roles, opt.ExpireAfter, nil)
 	}
 	return "", ErrUnauthorized
 }
+
+//signToken issues a service center token carrying the account name and roles
+func signToken(user string, roles []string, expireAfter string, extra map[string]interface{}) (string, error) {
+	if keyRing == nil {
+		return "", ErrNoSigningKey
+	}
+	key := keyRing.Active()
+	claims := map[string]interface{}{
+		rbacframe.ClaimsUser:  user,
+		rbacframe.ClaimsRoles: roles,
+		rbacframe.ClaimsJTI:   util.GenerateUUID(),
+		// the tokens issued before the account revoked are denied by iat
+		rbacframe.ClaimsIssuedAt: rbacframe.NumericDate(time.Now()),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	tokenStr, err := rbacframe.Sign(claims, key.Kid, key.Alg, key.Private, expireAfter)
+	if err != nil {
+		log.Errorf(err, "can not sign a token")
+		return "", err
+	}
+	return tokenStr, nil
+}
+
 func (a *EmbeddedAuthenticator) Authenticate(ctx context.Context, tokenStr string) (interface{}, error) {
-	p, err := jwt.ParseRSAPublicKeyFromPEM([]byte(PublicKey()))
+	if keyRing == nil {
+		return nil, ErrNoSigningKey
+	}
+	claims, err := rbacframe.AuthenticateWithKeys(tokenStr, keyRing.PublicKey)
 	if err != nil {
-		log.Error("can not parse public key", err)
 		return nil, err
 	}
-	return rbacframe.Authenticate(tokenStr, p)
+	if revoker != nil && revoker.Revoked(claims) {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
 }
 
 func init() {
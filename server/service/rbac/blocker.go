@@ -18,83 +18,205 @@
 package rbac
 
 import (
-	"sync"
+	"context"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/config"
 )
 
 const (
-	MaxAttempts = 2
-
-	BlockInterval = 1 * time.Hour
+	DefaultIPMaxAttempts      = 3
+	DefaultAccountMaxAttempts = 10
+	DefaultBlockWindow        = 1 * time.Hour
+	DefaultBanTime            = 1 * time.Hour
 )
 
-var BanTime = 1 * time.Hour
+var blocker *Blocker
+
+// BlockPolicy bans the client after MaxAttempts login failures within Window
+type BlockPolicy struct {
+	MaxAttempts int32
+	Window      time.Duration
+	BanTime     time.Duration
+}
+
+// LoginAttemptStore persists the login failures, the records are shared by all replicas
+type LoginAttemptStore interface {
+	PutLoginAttempt(ctx context.Context, a *rbacframe.LoginAttempt, ttl time.Duration) error
+	IncLoginAttempt(ctx context.Context, typ, value string, ttl time.Duration) (*rbacframe.LoginAttempt, error)
+	GetLoginAttempt(ctx context.Context, id string) (*rbacframe.LoginAttempt, error)
+	ListLoginAttempt(ctx context.Context) ([]*rbacframe.LoginAttempt, error)
+	DeleteLoginAttempt(ctx context.Context, id string) error
+}
+
+// Blocker counts the login failures by client ip and by account, only the client
+// ip is banned, the failures of an account from any client only raise the alarm,
+// otherwise anyone could lock out the root account by failed logins
+type Blocker struct {
+	store    LoginAttemptStore
+	policies map[string]*BlockPolicy
+}
+
+func NewBlocker(store LoginAttemptStore, ipPolicy, accountPolicy *BlockPolicy) *Blocker {
+	return &Blocker{
+		store: store,
+		policies: map[string]*BlockPolicy{
+			rbacframe.BlockIP:      ipPolicy,
+			rbacframe.BlockAccount: accountPolicy,
+		},
+	}
+}
 
-type Client struct {
-	limiter   *rate.Limiter
-	Key       string
-	Banned    bool
-	ReleaseAt time.Time //at this time client can be allow to attempt to do something
+func blockPolicy(typ string, maxAttempts int) *BlockPolicy {
+	prefix := "rbac.blocker." + typ + "."
+	return &BlockPolicy{
+		MaxAttempts: int32(config.GetInt(prefix+"maxAttempts", maxAttempts)),
+		Window:      config.GetDuration(prefix+"window", DefaultBlockWindow),
+		BanTime:     config.GetDuration(prefix+"banTime", DefaultBanTime),
+	}
 }
 
-var clients sync.Map
+func initBlocker() {
+	blocker = NewBlocker(datasource.Instance(),
+		blockPolicy(rbacframe.BlockIP, DefaultIPMaxAttempts),
+		blockPolicy(rbacframe.BlockAccount, DefaultAccountMaxAttempts))
+}
 
-func BannedList() []*Client {
-	cs := make([]*Client, 0)
-	clients.Range(func(key, value interface{}) bool {
-		client := value.(*Client)
-		if client.Banned && time.Now().After(client.ReleaseAt) {
-			client.Banned = false
-			client.ReleaseAt = time.Time{}
+//IsBanned check if the client ip or the account is banned,
+//it does not block the login if the datasource is unavailable
+func (b *Blocker) IsBanned(ctx context.Context, ip, account string) bool {
+	now := time.Now()
+	for typ, value := range map[string]string{rbacframe.BlockIP: ip, rbacframe.BlockAccount: account} {
+		if len(value) == 0 {
+			continue
+		}
+		a, err := b.store.GetLoginAttempt(ctx, rbacframe.LoginAttemptID(typ, value))
+		if err != nil {
+			if err != datasource.ErrLoginAttemptNotExist {
+				log.Error("get login attempt failed", err)
+			}
+			continue
+		}
+		if a.IsBanned(now) {
 			return true
 		}
-		cs = append(cs, client)
-		return true
-	})
-	return cs
-}
-
-//CountFailure can cause a client banned
-// it use time/rate to allow certainty failure,
-//but will ban client if rate limiter can not accept failures
-func CountFailure(key string) {
-	var c interface{}
-	var client *Client
-	var ok bool
+	}
+	return false
+}
+
+//CountFailure counts a login failure of both client ip and account,
+//it bans them if the failures exceed the policy in the window
+func (b *Blocker) CountFailure(ctx context.Context, ip, account string) {
+	if len(ip) > 0 {
+		b.count(ctx, rbacframe.BlockIP, ip)
+	}
+	if len(account) > 0 {
+		b.count(ctx, rbacframe.BlockAccount, account)
+	}
+}
+
+func (b *Blocker) count(ctx context.Context, typ, value string) {
+	p := b.policies[typ]
+	// the window starts from the first failure, the attempt is removed after it
+	a, err := b.store.IncLoginAttempt(ctx, typ, value, p.Window)
+	if err != nil {
+		log.Error("count login failure failed", err)
+		return
+	}
+	if a.Failures < p.MaxAttempts || a.Banned {
+		return
+	}
+	if typ == rbacframe.BlockAccount {
+		// raise once in the window, the increments are atomic
+		if a.Failures == p.MaxAttempts {
+			log.Warnf("%s [%s] has %d login failures", typ, value, a.Failures)
+			raiseUnderAttack(value, a.Failures)
+		}
+		return
+	}
+	now := time.Now()
+	a.Banned = true
+	a.ReleaseTime = now.Add(p.BanTime).Unix()
+	log.Warnf("%s [%s] is banned after %d login failures", typ, value, a.Failures)
+	if err := b.store.PutLoginAttempt(ctx, a, p.BanTime); err != nil {
+		log.Error("save login attempt failed", err)
+	}
+}
+
+// BannedList returns the clients banned now
+func (b *Blocker) BannedList(ctx context.Context) ([]*rbacframe.LoginAttempt, error) {
+	attempts, err := b.store.ListLoginAttempt(ctx)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
-	if c, ok = clients.Load(key); !ok {
-		client = &Client{
-			Key:       key,
-			limiter:   rate.NewLimiter(rate.Every(BlockInterval), MaxAttempts),
-			ReleaseAt: time.Time{},
+	banned := make([]*rbacframe.LoginAttempt, 0, len(attempts))
+	for _, a := range attempts {
+		if a.IsBanned(now) {
+			banned = append(banned, a)
 		}
-		clients.Store(key, client)
-	} else {
-		client = c.(*Client)
 	}
+	return banned, nil
+}
+
+// Release lifts the ban and clears the failures
+func (b *Blocker) Release(ctx context.Context, typ, value string) error {
+	err := b.store.DeleteLoginAttempt(ctx, rbacframe.LoginAttemptID(typ, value))
+	if err != nil {
+		return err
+	}
+	log.Infof("%s [%s] is released", typ, value)
+	if typ == rbacframe.BlockAccount {
+		b.clearUnderAttack()
+	}
+	return nil
+}
 
-	allow := client.limiter.AllowN(time.Now(), 1)
-	if !allow {
-		client.Banned = true
-		client.ReleaseAt = now.Add(BanTime)
+func raiseUnderAttack(account string, failures int32) {
+	err := alarm.Raise(alarm.IDAccountUnderAttack,
+		alarm.AdditionalContext("account [%s] has %d login failures", account, failures))
+	if err != nil {
+		log.Error("raise alarm failed", err)
 	}
 }
 
-//IsBanned check if a client is banned, and if client ban time expire,
-//it will release the client from banned status
-func IsBanned(key string) bool {
-	var c interface{}
-	var client *Client
-	var ok bool
-	if c, ok = clients.Load(key); !ok {
+// clearUnderAttack clears the alarm after the account failures are released
+func (b *Blocker) clearUnderAttack() {
+	if err := alarm.Clear(alarm.IDAccountUnderAttack); err != nil {
+		log.Error("clear alarm failed", err)
+	}
+}
+
+//IsBanned check if the client ip or the account is banned, it always return false if rbac is disabled
+func IsBanned(ctx context.Context, ip, account string) bool {
+	if blocker == nil {
 		return false
 	}
-	client = c.(*Client)
-	if client.Banned && time.Now().After(client.ReleaseAt) {
-		client.Banned = false
-		client.ReleaseAt = time.Time{}
+	return blocker.IsBanned(ctx, ip, account)
+}
+
+//CountFailure can cause the client ip and account banned
+func CountFailure(ctx context.Context, ip, account string) {
+	if blocker == nil {
+		return
+	}
+	blocker.CountFailure(ctx, ip, account)
+}
+
+func BannedList(ctx context.Context) ([]*rbacframe.LoginAttempt, error) {
+	if blocker == nil {
+		return []*rbacframe.LoginAttempt{}, nil
+	}
+	return blocker.BannedList(ctx)
+}
+
+func Release(ctx context.Context, typ, value string) error {
+	if blocker == nil {
+		return nil
 	}
-	return client.Banned
+	return blocker.Release(ctx, typ, value)
 }
@@ -18,40 +18,127 @@
 package rbac_test
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	"github.com/apache/servicecomb-service-center/server/service/rbac"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCountFailure(t *testing.T) {
-	rbac.BanTime = 3 * time.Second
-	rbac.CountFailure("1")
-	assert.False(t, rbac.IsBanned("1"))
+type memLoginAttemptStore struct {
+	lock sync.Mutex
+	m    map[string]rbacframe.LoginAttempt
+}
+
+func (s *memLoginAttemptStore) PutLoginAttempt(_ context.Context, a *rbacframe.LoginAttempt, _ time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.m[a.ID] = *a
+	return nil
+}
+
+func (s *memLoginAttemptStore) IncLoginAttempt(_ context.Context, typ, value string, ttl time.Duration) (*rbacframe.LoginAttempt, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	id := rbacframe.LoginAttemptID(typ, value)
+	a, ok := s.m[id]
+	if !ok || (a.Banned && !a.IsBanned(time.Now())) {
+		a = rbacframe.LoginAttempt{ID: id, Type: typ, Value: value, FirstFailTime: time.Now().Unix()}
+	}
+	a.Failures++
+	s.m[id] = a
+	return &a, nil
+}
+
+func (s *memLoginAttemptStore) GetLoginAttempt(_ context.Context, id string) (*rbacframe.LoginAttempt, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	a, ok := s.m[id]
+	if !ok {
+		return nil, datasource.ErrLoginAttemptNotExist
+	}
+	return &a, nil
+}
 
-	rbac.CountFailure("1")
-	assert.False(t, rbac.IsBanned("1"))
+func (s *memLoginAttemptStore) ListLoginAttempt(_ context.Context) ([]*rbacframe.LoginAttempt, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var as []*rbacframe.LoginAttempt
+	for _, a := range s.m {
+		a := a
+		as = append(as, &a)
+	}
+	return as, nil
+}
 
-	rbac.CountFailure("1")
-	assert.True(t, rbac.IsBanned("1"))
+func (s *memLoginAttemptStore) DeleteLoginAttempt(_ context.Context, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.m, id)
+	return nil
+}
 
-	t.Run("ban 1 more", func(t *testing.T) {
-		rbac.CountFailure("2")
-		assert.False(t, rbac.IsBanned("2"))
+func TestBlocker(t *testing.T) {
+	ctx := context.Background()
+	store := &memLoginAttemptStore{m: make(map[string]rbacframe.LoginAttempt)}
+	b := rbac.NewBlocker(store,
+		&rbac.BlockPolicy{MaxAttempts: 3, Window: time.Hour, BanTime: time.Hour},
+		&rbac.BlockPolicy{MaxAttempts: 4, Window: time.Hour, BanTime: time.Hour})
 
-		rbac.CountFailure("2")
-		assert.False(t, rbac.IsBanned("2"))
+	t.Run("ip failures exceed, should ban the ip", func(t *testing.T) {
+		b.CountFailure(ctx, "1", "")
+		b.CountFailure(ctx, "1", "")
+		assert.False(t, b.IsBanned(ctx, "1", ""))
+		b.CountFailure(ctx, "1", "")
+		assert.True(t, b.IsBanned(ctx, "1", ""))
+		assert.True(t, b.IsBanned(ctx, "1", "other"))
+		assert.False(t, b.IsBanned(ctx, "2", ""))
+	})
 
-		rbac.CountFailure("2")
-		assert.True(t, rbac.IsBanned("2"))
+	t.Run("account failures from different ips exceed, should not ban the account", func(t *testing.T) {
+		for _, ip := range []string{"10", "11", "12", "13", "14"} {
+			assert.False(t, b.IsBanned(ctx, ip, "root"))
+			b.CountFailure(ctx, ip, "root")
+		}
+		assert.False(t, b.IsBanned(ctx, "15", "root"))
+		a, err := store.GetLoginAttempt(ctx, rbacframe.LoginAttemptID(rbacframe.BlockAccount, "root"))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(5), a.Failures)
+		assert.False(t, a.Banned)
 	})
-	t.Log(rbac.BannedList()[0].ReleaseAt)
-	assert.Equal(t, 2, len(rbac.BannedList()))
 
-	time.Sleep(4 * time.Second)
-	assert.Equal(t, 0, len(rbac.BannedList()))
-	assert.False(t, rbac.IsBanned("1"))
-	assert.False(t, rbac.IsBanned("2"))
+	t.Run("list and release, should lift the ban", func(t *testing.T) {
+		assert.NoError(t, b.Release(ctx, rbacframe.BlockIP, "2"))
+		b.CountFailure(ctx, "2", "")
+		b.CountFailure(ctx, "2", "")
+		b.CountFailure(ctx, "2", "")
+		banned, err := b.BannedList(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(banned))
 
+		assert.NoError(t, b.Release(ctx, rbacframe.BlockIP, "2"))
+		assert.False(t, b.IsBanned(ctx, "2", ""))
+		banned, err = b.BannedList(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(banned))
+		assert.Equal(t, "1", banned[0].Value)
+	})
+
+	t.Run("ban time passed, should count again", func(t *testing.T) {
+		a, err := store.GetLoginAttempt(ctx, rbacframe.LoginAttemptID(rbacframe.BlockIP, "1"))
+		assert.NoError(t, err)
+		a.ReleaseTime = time.Now().Add(-time.Second).Unix()
+		assert.NoError(t, store.PutLoginAttempt(ctx, a, time.Hour))
+		assert.False(t, b.IsBanned(ctx, "1", ""))
+
+		b.CountFailure(ctx, "1", "")
+		assert.False(t, b.IsBanned(ctx, "1", ""))
+		a, err = store.GetLoginAttempt(ctx, rbacframe.LoginAttemptID(rbacframe.BlockIP, "1"))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), a.Failures)
+	})
 }
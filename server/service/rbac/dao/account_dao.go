@@ -42,7 +42,19 @@ func AccountExist(ctx context.Context, name string) (bool, error) {
 	return datasource.Instance().AccountExist(ctx, name)
 }
 func DeleteAccount(ctx context.Context, name string) (bool, error) {
-	return datasource.Instance().DeleteAccount(ctx, []string{name})
+	ok, err := datasource.Instance().DeleteAccount(ctx, []string{name})
+	if err != nil || !ok {
+		return ok, err
+	}
+	err = DeleteAccountAPIKeys(ctx, name)
+	if err != nil {
+		log.Errorf(err, "can not revoke api keys of account [%s]", name)
+	}
+	err = DeletePasswordState(ctx, name)
+	if err != nil {
+		log.Errorf(err, "can not delete password state of account [%s]", name)
+	}
+	return ok, nil
 }
 
 //CreateAccount save 2 kv
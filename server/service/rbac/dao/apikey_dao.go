@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dao
+
+import (
+	"context"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+)
+
+func CreateAPIKey(ctx context.Context, key *rbacframe.APIKey) error {
+	return datasource.Instance().CreateAPIKey(ctx, key)
+}
+
+func GetAPIKey(ctx context.Context, id string) (*rbacframe.APIKey, error) {
+	return datasource.Instance().GetAPIKey(ctx, id)
+}
+
+func ListAPIKey(ctx context.Context, account string) ([]*rbacframe.APIKey, error) {
+	return datasource.Instance().ListAPIKey(ctx, account)
+}
+
+func DeleteAPIKey(ctx context.Context, account, id string) error {
+	return datasource.Instance().DeleteAPIKey(ctx, account, id)
+}
+
+func UpdateAPIKeyLastUsed(ctx context.Context, id, lastUsedTime string) error {
+	return datasource.Instance().UpdateAPIKeyLastUsed(ctx, id, lastUsedTime)
+}
+
+//DeleteAccountAPIKeys revokes all the api keys of account
+func DeleteAccountAPIKeys(ctx context.Context, account string) error {
+	keys, err := ListAPIKey(ctx, account)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		err = DeleteAPIKey(ctx, account, key.ID)
+		if err != nil && err != datasource.ErrAPIKeyNotExist {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,24 +19,31 @@ package rbac
 
 import (
 	"context"
-	"github.com/go-chassis/cari/rbac"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 )
 
+// Allow returns true if any permission of roleList allows the verbs on resource in project
 func Allow(ctx context.Context, roleList []string, project, resource, verbs string) (bool, error) {
-	//TODO check project
+	_, allow, err := GetScope(ctx, roleList, project, resource, verbs)
+	return allow, err
+}
+
+// GetScope returns the label scope of the permissions which allow the verbs on resource in project,
+// the scope is nil if the role list can access any service
+func GetScope(ctx context.Context, roleList []string, project, resource, verbs string) (*rbacframe.Scope, bool, error) {
 	if ableToAccessResource(roleList, "admin") {
-		return true, nil
+		return nil, true, nil
 	}
 	// allPerms combines the roleList permission
-	var allPerms = make([]*rbac.Permission, 0)
+	var allPerms = make([]*rbacframe.Permission, 0)
 	for i := 0; i < len(roleList); i++ {
 		r, err := datasource.Instance().GetRole(ctx, roleList[i])
 		if err != nil {
 			log.Error("get role list errors", err)
-			return false, err
+			return nil, false, err
 		}
 		if r == nil {
 			log.Warnf("role [%s] has no any permissions", roleList[i])
@@ -47,16 +54,32 @@ func Allow(ctx context.Context, roleList []string, project, resource, verbs stri
 
 	if len(allPerms) == 0 {
 		log.Warn("role list has no any permissions")
-		return false, nil
+		return nil, false, nil
 	}
-	for i := 0; i < len(allPerms); i++ {
-		if ableToAccessResource(allPerms[i].Resources, resource) && ableToOperateResource(allPerms[i].Verbs, verbs) {
-			return true, nil
+	scope, allow := scopeOf(allPerms, project, resource, verbs)
+	return scope, allow, nil
+}
+
+func scopeOf(perms []*rbacframe.Permission, project, resource, verbs string) (*rbacframe.Scope, bool) {
+	scope := &rbacframe.Scope{}
+	allow := false
+	for _, perm := range perms {
+		if !perm.MatchProject(project) ||
+			!ableToAccessResource(perm.Resources, resource) || !ableToOperateResource(perm.Verbs, verbs) {
+			continue
+		}
+		if len(perm.Labels) == 0 {
+			// not restricted by labels
+			return nil, true
 		}
+		allow = true
+		scope.Selectors = append(scope.Selectors, perm.Labels)
 	}
-
-	log.Warn("role is not allowed to operate resource")
-	return false, nil
+	if !allow {
+		log.Warn("role is not allowed to operate resource")
+		return nil, false
+	}
+	return scope, true
 }
 
 func ableToOperateResource(haystack []string, needle string) bool {
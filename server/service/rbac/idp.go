@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/oidc"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	DefaultUsernameClaim  = "preferred_username"
+	DefaultGroupsClaim    = "groups"
+	DefaultUsernamePrefix = "oidc:"
+)
+
+var (
+	ErrIdPDisabled    = errors.New("identity provider login is disabled")
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrNoRoleMapped   = errors.New("no role is mapped to the identity provider groups")
+)
+
+var idp *IdentityProvider
+
+// IdentityProvider exchanges the OIDC ID tokens for service center tokens
+type IdentityProvider struct {
+	Config   *config.IdentityProvider
+	Verifier *oidc.Verifier
+}
+
+// NewIdentityProvider loads the JWKS file of cfg
+func NewIdentityProvider(cfg *config.IdentityProvider) (*IdentityProvider, error) {
+	data, err := ioutil.ReadFile(cfg.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := oidc.ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &IdentityProvider{
+		Config:   cfg,
+		Verifier: oidc.NewVerifier(cfg.Issuer, cfg.Audience, keys),
+	}, nil
+}
+
+func initIdentityProvider() {
+	cfg := config.GetIdP()
+	if cfg == nil || !cfg.Enabled {
+		return
+	}
+	p, err := NewIdentityProvider(cfg)
+	if err != nil {
+		log.Fatal("can not init identity provider", err)
+		return
+	}
+	idp = p
+	rbacframe.Add2WhiteAPIList(APIOIDCTokenGranter)
+	log.Infof("identity provider %s is enabled", cfg.Issuer)
+}
+
+// IdP return the identity provider, nil if it is disabled
+func IdP() *IdentityProvider {
+	return idp
+}
+
+// Identify verifies the ID token and returns the account name and mapped roles
+func (p *IdentityProvider) Identify(idToken string) (string, []string, error) {
+	claims, err := p.Verifier.Verify(idToken)
+	if err != nil {
+		log.Error("verify id token failed", err)
+		return "", nil, ErrInvalidIDToken
+	}
+	usernameClaim := p.Config.UsernameClaim
+	if len(usernameClaim) == 0 {
+		usernameClaim = DefaultUsernameClaim
+	}
+	name := oidc.StringClaim(claims, usernameClaim)
+	if len(name) == 0 {
+		name = oidc.StringClaim(claims, "sub")
+	}
+	if len(name) == 0 {
+		return "", nil, ErrInvalidIDToken
+	}
+	prefix := p.Config.UsernamePrefix
+	if len(prefix) == 0 {
+		prefix = DefaultUsernamePrefix
+	}
+	groupsClaim := p.Config.GroupsClaim
+	if len(groupsClaim) == 0 {
+		groupsClaim = DefaultGroupsClaim
+	}
+	roles := MapRoles(oidc.StringsClaim(claims, groupsClaim), p.Config.Rules, p.Config.DefaultRoles)
+	if len(roles) == 0 {
+		return "", nil, ErrNoRoleMapped
+	}
+	return prefix + name, roles, nil
+}
+
+// Login exchanges the ID token for a service center token
+func (p *IdentityProvider) Login(ctx context.Context, idToken string, expireAfter string) (string, error) {
+	name, roles, err := p.Identify(idToken)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("account [%s] login by identity provider, roles: %v", name, roles)
+	return signToken(name, roles, expireAfter, nil)
+}
+
+// MapRoles returns the roles of the rules matching groups,
+// a rule of group "*" matches any group
+func MapRoles(groups []string, rules []config.GroupRule, defaultRoles []string) []string {
+	var roles []string
+	seen := make(map[string]struct{})
+	add := func(rs []string) {
+		for _, r := range rs {
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+			roles = append(roles, r)
+		}
+	}
+	for _, rule := range rules {
+		for _, g := range groups {
+			if rule.Group == "*" || rule.Group == g {
+				add(rule.Roles)
+				break
+			}
+		}
+	}
+	if len(roles) == 0 {
+		add(defaultRoles)
+	}
+	return roles
+}
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/oidc"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/service/rbac"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapRoles(t *testing.T) {
+	rules := []config.GroupRule{
+		{Group: "sc-admins", Roles: []string{"admin"}},
+		{Group: "dev", Roles: []string{"developer", "tester"}},
+	}
+	assert.Equal(t, []string{"admin", "developer", "tester"}, rbac.MapRoles([]string{"dev", "sc-admins"}, rules, nil))
+	assert.Equal(t, []string{"guest"}, rbac.MapRoles([]string{"other"}, rules, []string{"guest"}))
+	assert.Empty(t, rbac.MapRoles(nil, rules, nil))
+}
+
+func TestIdentityProvider_Identify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	b, err := json.Marshal(&oidc.JWKS{Keys: []*oidc.JWK{oidc.NewJWK("k1", &key.PublicKey)}})
+	assert.NoError(t, err)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, ioutil.WriteFile(jwksFile, b, 0600))
+
+	p, err := rbac.NewIdentityProvider(&config.IdentityProvider{
+		Enabled:  true,
+		Issuer:   "https://idp.example.com",
+		Audience: "service-center",
+		JWKSFile: jwksFile,
+		Rules:    []config.GroupRule{{Group: "sc-admins", Roles: []string{"admin"}}},
+	})
+	assert.NoError(t, err)
+
+	sign := func(groups ...string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":                "https://idp.example.com",
+			"aud":                "service-center",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"sub":                "1",
+			"preferred_username": "alice",
+			"groups":             groups,
+		})
+		token.Header["kid"] = "k1"
+		s, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return s
+	}
+
+	name, roles, err := p.Identify(sign("sc-admins"))
+	assert.NoError(t, err)
+	assert.Equal(t, "oidc:alice", name)
+	assert.Equal(t, []string{"admin"}, roles)
+
+	_, _, err = p.Identify(sign("others"))
+	assert.Equal(t, rbac.ErrNoRoleMapped, err)
+
+	_, _, err = p.Identify("invalid")
+	assert.Equal(t, rbac.ErrInvalidIDToken, err)
+}
@@ -0,0 +1,260 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/oidc"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/security/cipher"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-chassis/go-chassis/v2/security/secret"
+)
+
+const (
+	// DefaultKid is the kid of the key pair configured by rbac.privateKeyFile and rbac.publicKeyFile
+	DefaultKid = "default"
+
+	DefaultKeyReloadInterval = time.Minute
+)
+
+var ErrNoSigningKey = errors.New("the active key can not sign tokens")
+
+var keyRing *KeyRing
+
+// SigningKey is a parsed key pair, Private is nil if the key only verifies tokens
+type SigningKey struct {
+	Kid     string
+	Alg     string
+	Private *rsa.PrivateKey
+	Public  *rsa.PublicKey
+}
+
+// KeyRing holds the keys verifying tokens and the active key signing new tokens,
+// the keys are reloaded when the key files change
+type KeyRing struct {
+	lock      sync.RWMutex
+	active    string
+	files     []config.SigningKey
+	keys      map[string]*SigningKey
+	modTimes  map[string]time.Time
+	interval  time.Duration
+	goroutine *gopool.Pool
+}
+
+func NewKeyRing(active string, files []config.SigningKey, interval time.Duration) *KeyRing {
+	if interval <= 0 {
+		interval = DefaultKeyReloadInterval
+	}
+	return &KeyRing{
+		active:    active,
+		files:     files,
+		keys:      make(map[string]*SigningKey),
+		modTimes:  make(map[string]time.Time),
+		interval:  interval,
+		goroutine: gopool.New(context.Background()),
+	}
+}
+
+// signingKeysConfig returns the rbac.signingKeys config,
+// or the single key pair of rbac.privateKeyFile and rbac.publicKeyFile if it is absent
+func signingKeysConfig() (string, []config.SigningKey, time.Duration) {
+	if c := config.GetSigningKeys(); c != nil && len(c.Keys) > 0 {
+		interval, err := time.ParseDuration(c.ReloadInterval)
+		if err != nil && len(c.ReloadInterval) > 0 {
+			log.Warnf("invalid rbac.signingKeys.reloadInterval %s, use %s", c.ReloadInterval, DefaultKeyReloadInterval)
+		}
+		return c.Active, c.Keys, interval
+	}
+	return DefaultKid, []config.SigningKey{{
+		Kid:            DefaultKid,
+		PrivateKeyFile: config.GetString("rbac.privateKeyFile", "", config.WithStandby("rbac_rsa_private_key_file")),
+		PublicKeyFile:  config.GetString("rbac.publicKeyFile", "", config.WithStandby("rbac_rsa_public_key_file")),
+	}}, DefaultKeyReloadInterval
+}
+
+func initKeyRing() {
+	r := NewKeyRing(signingKeysConfig())
+	if err := r.Load(); err != nil {
+		log.Fatal("can not load signing keys", err)
+		return
+	}
+	r.Start()
+	keyRing = r
+	log.Infof("signing keys loaded, active key is [%s]", r.active)
+}
+
+// Keys returns the key ring, it is nil if rbac is disabled
+func Keys() *KeyRing {
+	return keyRing
+}
+
+// Load reads all the key files, the current keys are kept if any file is invalid
+func (r *KeyRing) Load() error {
+	keys := make(map[string]*SigningKey, len(r.files))
+	modTimes := make(map[string]time.Time, len(r.files)*2)
+	for _, f := range r.files {
+		if _, err := rbacframe.SigningMethod(f.Alg); err != nil {
+			return fmt.Errorf("key [%s]: %v", f.Kid, err)
+		}
+		k := &SigningKey{Kid: f.Kid, Alg: f.Alg}
+		if len(f.PrivateKeyFile) > 0 {
+			data, err := readKeyFile(f.PrivateKeyFile, modTimes)
+			if err != nil {
+				return fmt.Errorf("key [%s]: %v", f.Kid, err)
+			}
+			k.Private, err = parsePrivateKey(string(data))
+			if err != nil {
+				return fmt.Errorf("key [%s]: %v", f.Kid, err)
+			}
+			k.Public = &k.Private.PublicKey
+		}
+		if len(f.PublicKeyFile) > 0 {
+			data, err := readKeyFile(f.PublicKeyFile, modTimes)
+			if err != nil {
+				return fmt.Errorf("key [%s]: %v", f.Kid, err)
+			}
+			k.Public, err = jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return fmt.Errorf("key [%s]: %v", f.Kid, err)
+			}
+		}
+		if k.Public == nil {
+			return fmt.Errorf("key [%s] has no key file", f.Kid)
+		}
+		keys[f.Kid] = k
+	}
+	if k, ok := keys[r.active]; !ok || k.Private == nil {
+		return fmt.Errorf("active key [%s]: %v", r.active, ErrNoSigningKey)
+	}
+	r.lock.Lock()
+	r.keys = keys
+	r.modTimes = modTimes
+	r.lock.Unlock()
+	return nil
+}
+
+func readKeyFile(name string, modTimes map[string]time.Time) ([]byte, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	modTimes[name] = info.ModTime()
+	return ioutil.ReadFile(name)
+}
+
+//parsePrivateKey decrypts the private key if it is encrypted
+func parsePrivateKey(ep string) (*rsa.PrivateKey, error) {
+	p, err := cipher.Decrypt(ep)
+	if err != nil {
+		log.Warn("cipher fallback: " + err.Error())
+		p = ep
+	}
+	return secret.ParseRSAPrivateKey(p)
+}
+
+// Active returns the key signing new tokens
+func (r *KeyRing) Active() *SigningKey {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.keys[r.active]
+}
+
+// PublicKey returns the key verifying the tokens signed by kid,
+// the tokens without kid are signed before the rotation supported and verified by the active key
+func (r *KeyRing) PublicKey(kid string) (*rsa.PublicKey, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if len(kid) == 0 {
+		kid = r.active
+	}
+	k, ok := r.keys[kid]
+	if !ok {
+		return nil, rbacframe.ErrUnknownKid
+	}
+	return k.Public, nil
+}
+
+// JWKS returns the public keys in json web key set format
+func (r *KeyRing) JWKS() *oidc.JWKS {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	jwks := &oidc.JWKS{Keys: make([]*oidc.JWK, 0, len(r.keys))}
+	for _, f := range r.files {
+		k, ok := r.keys[f.Kid]
+		if !ok {
+			continue
+		}
+		jwk := oidc.NewJWK(k.Kid, k.Public)
+		jwk.Alg = k.Alg
+		if len(jwk.Alg) == 0 {
+			jwk.Alg = rbacframe.DefaultAlg
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+func (r *KeyRing) changed() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for name, t := range r.modTimes {
+		info, err := os.Stat(name)
+		if err != nil || !info.ModTime().Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *KeyRing) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+			if !r.changed() {
+				continue
+			}
+			if err := r.Load(); err != nil {
+				log.Error("reload signing keys failed, keep the current keys", err)
+				continue
+			}
+			log.Info("signing keys reloaded")
+		}
+	}
+}
+
+func (r *KeyRing) Start() {
+	r.goroutine.Do(r.loop)
+}
+
+func (r *KeyRing) Stop() {
+	r.goroutine.Close(true)
+}
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/service/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeyPair(t *testing.T, dir, kid string) (string, string) {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	private := filepath.Join(dir, kid+".key")
+	public := filepath.Join(dir, kid+".pub")
+	assert.NoError(t, ioutil.WriteFile(private, pem.EncodeToMemory(&pem.Block{
+		Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), 0600))
+	pub, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(public, pem.EncodeToMemory(&pem.Block{
+		Type: "PUBLIC KEY", Bytes: pub}), 0600))
+	return private, public
+}
+
+func TestKeyRing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	assert.NoError(t, err)
+	k1, p1 := writeKeyPair(t, dir, "k1")
+	_, p2 := writeKeyPair(t, dir, "k2")
+
+	t.Run("active key can not sign, should fail", func(t *testing.T) {
+		r := rbac.NewKeyRing("k2", []config.SigningKey{{Kid: "k2", PublicKeyFile: p2}}, time.Minute)
+		assert.Error(t, r.Load())
+	})
+
+	t.Run("load keys, should verify by kid", func(t *testing.T) {
+		r := rbac.NewKeyRing("k1", []config.SigningKey{
+			{Kid: "k1", PrivateKeyFile: k1, PublicKeyFile: p1},
+			{Kid: "k2", Alg: "RS256", PublicKeyFile: p2},
+		}, time.Minute)
+		assert.NoError(t, r.Load())
+		assert.Equal(t, "k1", r.Active().Kid)
+		assert.NotNil(t, r.Active().Private)
+
+		_, err := r.PublicKey("k2")
+		assert.NoError(t, err)
+		active, err := r.PublicKey("")
+		assert.NoError(t, err)
+		assert.Equal(t, r.Active().Public, active)
+		_, err = r.PublicKey("k3")
+		assert.Error(t, err)
+
+		jwks := r.JWKS()
+		assert.Equal(t, 2, len(jwks.Keys))
+		assert.Equal(t, "k1", jwks.Keys[0].Kid)
+		assert.Equal(t, "RS512", jwks.Keys[0].Alg)
+		assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+	})
+
+	t.Run("invalid alg, should fail", func(t *testing.T) {
+		r := rbac.NewKeyRing("k1", []config.SigningKey{{Kid: "k1", Alg: "HS256", PrivateKeyFile: k1}}, time.Minute)
+		assert.Error(t, r.Load())
+	})
+}
@@ -23,9 +23,6 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 	rbacmodel "github.com/go-chassis/cari/rbac"
 
-	"github.com/go-chassis/foundation/stringutil"
-	"golang.org/x/crypto/bcrypt"
-
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
 )
@@ -54,7 +51,7 @@ func changePasswordForcibly(ctx context.Context, name, pwd string) error {
 		log.Error("can not change pwd", err)
 		return err
 	}
-	err = doChangePassword(ctx, old, pwd)
+	err = doChangePassword(ctx, old, pwd, passwordPolicy.ForceChangeOnFirstLogin)
 	if err != nil {
 		return err
 	}
@@ -74,24 +71,35 @@ func changePassword(ctx context.Context, name, currentPassword, pwd string) erro
 		log.Error("current password is wrong", nil)
 		return ErrWrongPassword
 	}
-	err = doChangePassword(ctx, old, pwd)
+	err = doChangePassword(ctx, old, pwd, false)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func doChangePassword(ctx context.Context, old *rbacmodel.Account, pwd string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(pwd), 14)
+//doChangePassword checks the password history and records the new password,
+//mustChange is true if the password is set by others
+func doChangePassword(ctx context.Context, old *rbacmodel.Account, pwd string, mustChange bool) error {
+	state, err := getPasswordState(ctx, old.Name)
+	if err != nil {
+		log.Error("can not get password state", err)
+		return err
+	}
+	err = checkPasswordReused(state, old.Password, pwd)
+	if err != nil {
+		return err
+	}
+	hash, err := privacy.ScryptPassword(pwd)
 	if err != nil {
 		log.Error("pwd hash failed", err)
 		return err
 	}
-	old.Password = stringutil.Bytes2str(hash)
+	old.Password = hash
 	err = dao.EditAccount(ctx, old)
 	if err != nil {
 		log.Error("can not change pwd", err)
 		return err
 	}
-	return nil
+	return recordPassword(ctx, state, hash, mustChange)
 }
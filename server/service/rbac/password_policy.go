@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/privacy"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/pkg/validate"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	"github.com/apache/servicecomb-service-center/server/service"
+	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
+	rbacmodel "github.com/go-chassis/cari/rbac"
+)
+
+var (
+	ErrPasswordReused  = errors.New("the password is used recently")
+	ErrPasswordExpired = errors.New("password is expired, change it before accessing other apis")
+)
+
+// PasswordPolicy is the rotation rule of passwords, the complexity rule is validate.PasswordPolicy
+type PasswordPolicy struct {
+	// HistorySize is the count of recent passwords can not be reused
+	HistorySize int
+	// MaxAge is the max duration a password can be used, never expires if it is 0
+	MaxAge time.Duration
+	// ForceChangeOnFirstLogin forces the accounts created by admin to change the password
+	ForceChangeOnFirstLogin bool
+}
+
+var passwordPolicy = &PasswordPolicy{}
+
+func initPasswordPolicy() {
+	d := validate.DefaultPasswordPolicy()
+	service.SetPasswordPolicy(&validate.PasswordPolicy{
+		MinLength:      config.GetInt("rbac.passwordPolicy.minLength", d.MinLength),
+		MaxLength:      config.GetInt("rbac.passwordPolicy.maxLength", d.MaxLength),
+		RequireUpper:   config.GetBool("rbac.passwordPolicy.requireUpper", d.RequireUpper),
+		RequireLower:   config.GetBool("rbac.passwordPolicy.requireLower", d.RequireLower),
+		RequireNumber:  config.GetBool("rbac.passwordPolicy.requireNumber", d.RequireNumber),
+		RequireSpecial: config.GetBool("rbac.passwordPolicy.requireSpecial", d.RequireSpecial),
+	})
+	passwordPolicy = &PasswordPolicy{
+		HistorySize:             config.GetInt("rbac.passwordPolicy.historySize", 0),
+		MaxAge:                  config.GetDuration("rbac.passwordPolicy.maxAge", 0),
+		ForceChangeOnFirstLogin: config.GetBool("rbac.passwordPolicy.forceChangeOnFirstLogin", false),
+	}
+}
+
+//CreateAccount creates the account and records its password,
+//the account created by admin must change the password on first login if the policy forces it,
+//it returns *discovery.Error if the account quota is used up
+func CreateAccount(ctx context.Context, a *rbacmodel.Account) error {
+	release, errQuota := quota.Acquire(ctx, quota.NewApplyQuotaResource(quota.TypeAccount, "", "", 1))
+	defer release()
+	if errQuota != nil {
+		return errQuota
+	}
+	return createAccount(ctx, a, passwordPolicy.ForceChangeOnFirstLogin)
+}
+
+//createAccount removes the account if its password state can not be recorded,
+//so that the account can be created again
+func createAccount(ctx context.Context, a *rbacmodel.Account, mustChange bool) error {
+	err := dao.CreateAccount(ctx, a)
+	if err != nil {
+		return err
+	}
+	// the password is hashed by datasource
+	err = recordPassword(ctx, &rbacframe.PasswordState{Account: a.Name}, a.Password, mustChange)
+	if err == nil {
+		return nil
+	}
+	if _, errDel := dao.DeleteAccount(ctx, a.Name); errDel != nil {
+		log.Errorf(errDel, "can not roll back account [%s]", a.Name)
+	}
+	return err
+}
+
+func recordPassword(ctx context.Context, s *rbacframe.PasswordState, hash string, mustChange bool) error {
+	s.Push(hash, passwordPolicy.HistorySize, time.Now())
+	s.MustChange = mustChange
+	err := dao.PutPasswordState(ctx, s)
+	if err != nil {
+		log.Errorf(err, "can not save password state of account [%s]", s.Account)
+	}
+	return err
+}
+
+func getPasswordState(ctx context.Context, name string) (*rbacframe.PasswordState, error) {
+	s, err := dao.GetPasswordState(ctx, name)
+	if err != datasource.ErrPasswordStateNotExist {
+		return s, err
+	}
+	// the accounts created before the policy enabled, the password is
+	// regarded as changed when the policy finds it first time
+	s = &rbacframe.PasswordState{Account: name, ChangeTime: time.Now().Unix()}
+	if err := dao.PutPasswordState(ctx, s); err != nil {
+		log.Errorf(err, "can not save password state of account [%s]", name)
+	}
+	return s, nil
+}
+
+//checkPasswordReused return ErrPasswordReused if pwd is the current password or one of the recent passwords
+func checkPasswordReused(s *rbacframe.PasswordState, current, pwd string) error {
+	if privacy.MatchPassword(current, pwd) {
+		return ErrSamePassword
+	}
+	for _, hash := range s.History {
+		if privacy.MatchPassword(hash, pwd) {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+//PasswordExpired return true if the account must change the password,
+//it does not block the login if the state is unavailable
+func PasswordExpired(ctx context.Context, name string) bool {
+	s, err := getPasswordState(ctx, name)
+	if err != nil {
+		log.Errorf(err, "can not get password state of account [%s]", name)
+		return false
+	}
+	return s.Expired(time.Now(), passwordPolicy.MaxAge)
+}
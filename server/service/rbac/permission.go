@@ -19,7 +19,6 @@ package rbac
 
 import (
 	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
-	"github.com/go-chassis/cari/rbac"
 )
 
 // method to verbs
@@ -33,10 +32,10 @@ var (
 )
 
 // AdminPerms allocate all resource permissions
-func AdminPerms() []*rbac.Permission {
+func AdminPerms() []*rbacframe.Permission {
 	resources := rbacframe.BuildResourceList(ResourceAccount, ResourceRole, ResourceService, ResourceInstance,
 		ResourceDep, ResourceTag, ResourceRule, ResourceGovern, ResourceAdminister, ResourceSchema)
-	perm := []*rbac.Permission{
+	perm := []*rbacframe.Permission{
 		{
 			Resources: resources,
 			Verbs:     []string{"*"},
@@ -46,10 +45,10 @@ func AdminPerms() []*rbac.Permission {
 }
 
 // DevPerms allocate all resource permissions except account and role resources
-func DevPerms() []*rbac.Permission {
+func DevPerms() []*rbacframe.Permission {
 	resources := rbacframe.BuildResourceList(ResourceService, ResourceInstance,
 		ResourceDep, ResourceTag, ResourceRule, ResourceGovern, ResourceAdminister, ResourceSchema)
-	perm := []*rbac.Permission{
+	perm := []*rbacframe.Permission{
 		{
 			Resources: resources,
 			Verbs:     []string{"*"},
@@ -19,10 +19,8 @@ package rbac
 
 import (
 	"context"
-	"crypto/rsa"
 	"errors"
 	"github.com/go-chassis/cari/rbac"
-	"io/ioutil"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -33,7 +31,6 @@ import (
 	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
 	"github.com/go-chassis/go-archaius"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
-	"github.com/go-chassis/go-chassis/v2/security/secret"
 )
 
 const (
@@ -61,6 +58,7 @@ func Init() {
 	if err != nil {
 		log.Fatal("can not enable auth module", err)
 	}
+	initPasswordPolicy()
 	accountExist, err := dao.AccountExist(context.Background(), RootName)
 	if err != nil {
 		log.Fatal("can not enable auth module", err)
@@ -68,47 +66,17 @@ func Init() {
 	if !accountExist {
 		initFirstTime(RootName)
 	}
-	readPrivateKey()
-	readPublicKey()
+	initKeyRing()
 	initAdminRole()
 	initDevRole()
-	rbacframe.Add2WhiteAPIList(APITokenGranter)
+	rbacframe.Add2WhiteAPIList(APITokenGranter, APIJWKS)
+	initIdentityProvider()
+	initRevoker()
+	initBlocker()
 	config.ServerInfo.Config.EnableRBAC = true
 	log.Info("rbac is enabled")
 }
 
-//readPublicKey read key to memory
-func readPrivateKey() {
-	pf := config.GetString("rbac.privateKeyFile", "", config.WithStandby("rbac_rsa_private_key_file"))
-	// 打开文件
-	data, err := ioutil.ReadFile(pf)
-	if err != nil {
-		log.Fatal("can not read private key", err)
-		return
-	}
-	err = archaius.Set("rbac_private_key", string(data))
-	if err != nil {
-		log.Fatal("can not init rbac", err)
-		return
-	}
-	log.Info("read private key success")
-}
-
-//readPublicKey read key to memory
-func readPublicKey() {
-	pf := config.GetString("rbac.publicKeyFile", "", config.WithStandby("rbac_rsa_public_key_file"))
-	// 打开文件
-	content, err := ioutil.ReadFile(pf)
-	if err != nil {
-		log.Fatal("can not find public key", err)
-		return
-	}
-	err = archaius.Set("rbac_public_key", string(content))
-	if err != nil {
-		log.Fatal("", err)
-	}
-	log.Info("read public key success")
-}
 func initFirstTime(admin string) {
 	//handle root account
 	pwd, err := getPassword()
@@ -125,7 +93,7 @@ func initFirstTime(admin string) {
 		log.Fatal("invalid pwd", err)
 		return
 	}
-	if err := dao.CreateAccount(context.Background(), a); err != nil {
+	if err := createAccount(context.Background(), a, false); err != nil {
 		if err == datasource.ErrAccountDuplicated {
 			log.Info("root account already exists")
 			return
@@ -152,30 +120,3 @@ func getPassword() (string, error) {
 func Enabled() bool {
 	return config.GetRBAC().EnableRBAC
 }
-
-//PublicKey get public key to verify a token
-func PublicKey() string {
-	return archaius.GetString("rbac_public_key", "")
-}
-
-//privateKey get decrypted private key to verify a token
-func privateKey() string {
-	ep := archaius.GetString("rbac_private_key", "")
-	p, err := cipher.Decrypt(ep)
-	if err != nil {
-		log.Warn("cipher fallback: " + err.Error())
-		return ep
-	}
-	return p
-}
-
-//GetPrivateKey return rsa key instance
-func GetPrivateKey() (*rsa.PrivateKey, error) {
-	sk := privateKey()
-	p, err := secret.ParseRSAPrivateKey(sk)
-	if err != nil {
-		log.Error("can not get key:", err)
-		return nil, err
-	}
-	return p, nil
-}
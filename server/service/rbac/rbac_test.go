@@ -147,9 +147,9 @@ func TestInitRBAC(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	tester := &rbacmodel.Role{
+	tester := &rbacframe.Role{
 		Name: "tester",
-		Perms: []*rbacmodel.Permission{
+		Perms: []*rbacframe.Permission{
 			{
 				Resources: []string{"service", "instance"},
 				Verbs:     []string{"get", "create", "update"},
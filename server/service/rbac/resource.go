@@ -33,13 +33,24 @@ const (
 )
 
 var (
-	APIHealth       = "/v4/:project/registry/health"
-	APIVersion      = "/v4/:project/registry/version"
-	APITokenGranter = "/v4/token"
+	APIHealth           = "/v4/:project/registry/health"
+	APIVersion          = "/v4/:project/registry/version"
+	APITokenGranter     = "/v4/token"
+	APIOIDCTokenGranter = "/v4/token/oidc"
+	APIJWKS             = "/v4/.well-known/jwks.json"
 
 	APIAccountList  = "/v4/account"
 	APIUserAccount  = "/v4/account/:name"
 	APIUserPassword = "/v4/account/:name/password"
+	APIUserKeyList  = "/v4/account/:name/keys"
+	APIUserKey      = "/v4/account/:name/keys/:id"
+	APIUserSessions = "/v4/account/:name/sessions"
+
+	APIBanned = "/v4/admin/banned"
+	APIAudit  = "/v4/admin/audit"
+
+	APIQuotaLimits = "/v4/admin/quotas/:domain/:project"
+	APIQuotaUsage  = "/v4/admin/quotas/:domain/:project/usage"
 
 	APIRoleList = "/v4/role"
 	APIRoleInfo = "/v4/role/:roleName"
@@ -80,12 +91,20 @@ var (
 	APIDump     = "/v4/:project/admin/dump"
 	APIClusters = "/v4/:project/admin/clusters"
 	APIAlarms   = "/v4/:project/admin/alarms"
+
+	APIWebhookList       = "/v4/:project/webhooks"
+	APIWebhookInfo       = "/v4/:project/webhooks/:id"
+	APIWebhookLetters    = "/v4/:project/webhooks/:id/deadletters"
+	APIWebhookLetterInfo = "/v4/:project/webhooks/:id/deadletters/:letterId"
 )
 
 func initResourceMap() {
 	rbacframe.MapResource(APIAccountList, ResourceAccount)
 	rbacframe.MapResource(APIUserAccount, ResourceAccount)
 	rbacframe.MapResource(APIUserPassword, ResourceAccount)
+	rbacframe.MapResource(APIUserKeyList, ResourceAccount)
+	rbacframe.MapResource(APIUserKey, ResourceAccount)
+	rbacframe.MapResource(APIUserSessions, ResourceAccount)
 
 	rbacframe.MapResource(APIRoleList, ResourceRole)
 	rbacframe.MapResource(APIRoleInfo, ResourceRole)
@@ -126,4 +145,13 @@ func initResourceMap() {
 	rbacframe.MapResource(APIDump, ResourceAdminister)
 	rbacframe.MapResource(APIClusters, ResourceAdminister)
 	rbacframe.MapResource(APIAlarms, ResourceAdminister)
+	rbacframe.MapResource(APIBanned, ResourceAdminister)
+	rbacframe.MapResource(APIAudit, ResourceAdminister)
+	rbacframe.MapResource(APIQuotaLimits, ResourceAdminister)
+	rbacframe.MapResource(APIQuotaUsage, ResourceAdminister)
+
+	rbacframe.MapResource(APIWebhookList, ResourceAdminister)
+	rbacframe.MapResource(APIWebhookInfo, ResourceAdminister)
+	rbacframe.MapResource(APIWebhookLetters, ResourceAdminister)
+	rbacframe.MapResource(APIWebhookLetterInfo, ResourceAdminister)
 }
@@ -0,0 +1,295 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	nf "github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const (
+	RevocationGroup = "__REVOCATION_GROUP__"
+
+	DefaultRevocationSyncInterval = 30 * time.Second
+)
+
+var (
+	ErrTokenRevoked = errors.New("token is revoked")
+	ErrNoJTI        = errors.New("token has no jti, can not be revoked")
+)
+
+var revoker *Revoker
+
+// RevocationStore persists the token denylist
+type RevocationStore interface {
+	PutRevocation(ctx context.Context, r *rbacframe.Revocation) error
+	ListRevocation(ctx context.Context) ([]*rbacframe.Revocation, error)
+	DeleteRevocation(ctx context.Context, id string) error
+}
+
+// Revoker caches the token denylist in memory,
+// the revocations of other replicas are received by the REVOCATION events
+// and the periodic synchronization from the store
+type Revoker struct {
+	nf.Subscriber
+	store     RevocationStore
+	interval  time.Duration
+	lock      sync.RWMutex
+	tokens    map[string]int64 // jti -> token expire time
+	accounts  map[string]int64 // account -> revoke before, in unix milliseconds
+	roles     map[string]int64 // role -> revoke before, in unix milliseconds
+	goroutine *gopool.Pool
+}
+
+func NewRevoker(store RevocationStore, interval time.Duration) *Revoker {
+	if interval <= 0 {
+		interval = DefaultRevocationSyncInterval
+	}
+	return &Revoker{
+		Subscriber: nf.NewSubscriber(notify.REVOCATION, notify.RevocationSubject, RevocationGroup),
+		store:      store,
+		interval:   interval,
+		tokens:     make(map[string]int64),
+		accounts:   make(map[string]int64),
+		roles:      make(map[string]int64),
+		goroutine:  gopool.New(context.Background()),
+	}
+}
+
+func initRevoker() {
+	r := NewRevoker(datasource.Instance(),
+		config.GetDuration("rbac.revocation.syncInterval", DefaultRevocationSyncInterval))
+	if err := r.Sync(context.Background()); err != nil {
+		log.Error("load token revocations failed", err)
+	}
+	if err := notify.Center().AddSubscriber(r); err != nil {
+		log.Error("add token revoker failed", err)
+	}
+	r.Start()
+	revoker = r
+}
+
+// Revocations returns the revoker, it is nil if rbac is disabled
+func Revocations() *Revoker {
+	return revoker
+}
+
+// OnMessage applies the revocations made by other replicas
+func (r *Revoker) OnMessage(evt nf.Event) {
+	re, ok := evt.(*notify.RevocationEvent)
+	if !ok || re.Revocation == nil {
+		return
+	}
+	if re.Action == string(pb.EVT_DELETE) {
+		r.forget(re.Revocation)
+		return
+	}
+	r.apply(re.Revocation)
+}
+
+func (r *Revoker) apply(rev *rbacframe.Revocation) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	switch rev.Type {
+	case rbacframe.RevokeToken:
+		r.tokens[rev.JTI] = rev.ExpireTime
+	case rbacframe.RevokeAccount:
+		if rev.RevokeBefore > r.accounts[rev.Account] {
+			r.accounts[rev.Account] = rev.RevokeBefore
+		}
+	case rbacframe.RevokeRole:
+		if rev.RevokeBefore > r.roles[rev.Role] {
+			r.roles[rev.Role] = rev.RevokeBefore
+		}
+	}
+}
+
+// forget only removes the purged token entries,
+// the account and role cutoffs never go back
+func (r *Revoker) forget(rev *rbacframe.Revocation) {
+	if rev.Type != rbacframe.RevokeToken {
+		return
+	}
+	r.lock.Lock()
+	delete(r.tokens, rev.JTI)
+	r.lock.Unlock()
+}
+
+// Sync reloads the denylist from the store and purges the expired entries
+func (r *Revoker) Sync(ctx context.Context) error {
+	revocations, err := r.store.ListRevocation(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tokens := make(map[string]int64, len(revocations))
+	for _, rev := range revocations {
+		if rev.Expired(now) {
+			if err := r.store.DeleteRevocation(ctx, rev.ID); err != nil {
+				log.Errorf(err, "purge revocation[%s] failed", rev.ID)
+			}
+			continue
+		}
+		if rev.Type == rbacframe.RevokeToken {
+			tokens[rev.JTI] = rev.ExpireTime
+			continue
+		}
+		r.apply(rev)
+	}
+	r.lock.Lock()
+	// keep the local entries not persisted yet
+	for jti, exp := range r.tokens {
+		if _, ok := tokens[jti]; !ok && (exp == 0 || exp >= now.Unix()) {
+			tokens[jti] = exp
+		}
+	}
+	r.tokens = tokens
+	r.lock.Unlock()
+	return nil
+}
+
+// RevokeToken adds the jti to the denylist until the token expires
+func (r *Revoker) RevokeToken(ctx context.Context, jti, account string, expireTime int64) error {
+	if len(jti) == 0 {
+		return ErrNoJTI
+	}
+	rev := rbacframe.NewTokenRevocation(jti, account, expireTime)
+	if err := r.store.PutRevocation(ctx, rev); err != nil {
+		return err
+	}
+	r.apply(rev)
+	log.Infof("token[%s] of account [%s] is revoked", jti, account)
+	return nil
+}
+
+// RevokeAccount revokes all the tokens of account issued before now,
+// the cutoff does not depend on the local state, so it is consistent
+// whichever replica revokes, as long as the clocks of replicas are synchronized
+func (r *Revoker) RevokeAccount(ctx context.Context, account string) error {
+	rev := rbacframe.NewAccountRevocation(account, time.Now())
+	if err := r.store.PutRevocation(ctx, rev); err != nil {
+		return err
+	}
+	r.apply(rev)
+	log.Infof("all sessions of account [%s] are revoked", account)
+	return nil
+}
+
+// RevokeRole revokes all the tokens having role issued before now,
+// including the tokens of the identities logged in by the identity provider
+func (r *Revoker) RevokeRole(ctx context.Context, role string) error {
+	rev := rbacframe.NewRoleRevocation(role, time.Now())
+	if err := r.store.PutRevocation(ctx, rev); err != nil {
+		return err
+	}
+	r.apply(rev)
+	log.Infof("all sessions having role [%s] are revoked", role)
+	return nil
+}
+
+// RevokeBefore returns the cutoff of account in unix milliseconds, 0 if never revoked
+func (r *Revoker) RevokeBefore(account string) int64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.accounts[account]
+}
+
+// Revoked return true if the token claims are revoked
+func (r *Revoker) Revoked(claims map[string]interface{}) bool {
+	account, _ := claims[rbacframe.ClaimsUser].(string)
+	jti, _ := claims[rbacframe.ClaimsJTI].(string)
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if len(jti) > 0 {
+		if _, ok := r.tokens[jti]; ok {
+			return true
+		}
+	}
+	iat := rbacframe.IssuedAt(claims)
+	if iat < r.accounts[account] {
+		return true
+	}
+	if len(r.roles) == 0 {
+		return false
+	}
+	roles, _ := rbacframe.GetRolesList(claims[rbacframe.ClaimsRoles])
+	for _, role := range roles {
+		if iat < r.roles[role] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Revoker) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+			if err := r.Sync(ctx); err != nil {
+				log.Error("sync token revocations failed", err)
+			}
+		}
+	}
+}
+
+func (r *Revoker) Start() {
+	r.goroutine.Do(r.loop)
+}
+
+func (r *Revoker) Stop() {
+	r.goroutine.Close(true)
+}
+
+// RevokeAccountSessions revokes the tokens of account, it does nothing if rbac is disabled
+func RevokeAccountSessions(ctx context.Context, account string) error {
+	if revoker == nil {
+		return nil
+	}
+	return revoker.RevokeAccount(ctx, account)
+}
+
+// RevokeRoleSessions revokes the tokens having role, of both the accounts and the identities of the identity provider
+func RevokeRoleSessions(ctx context.Context, role string) error {
+	if revoker == nil {
+		return nil
+	}
+	return revoker.RevokeRole(ctx, role)
+}
+
+// Logout revokes the token of claims
+func Logout(ctx context.Context, claims map[string]interface{}) error {
+	if revoker == nil {
+		return nil
+	}
+	account, _ := claims[rbacframe.ClaimsUser].(string)
+	jti, _ := claims[rbacframe.ClaimsJTI].(string)
+	return revoker.RevokeToken(ctx, jti, account, rbacframe.Int64Claim(claims, rbacframe.ClaimsExpire))
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
+	"github.com/apache/servicecomb-service-center/server/service/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+type memRevocationStore struct {
+	lock sync.Mutex
+	m    map[string]*rbacframe.Revocation
+}
+
+func (s *memRevocationStore) PutRevocation(_ context.Context, r *rbacframe.Revocation) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.m[r.ID] = r
+	return nil
+}
+
+func (s *memRevocationStore) ListRevocation(_ context.Context) ([]*rbacframe.Revocation, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var rs []*rbacframe.Revocation
+	for _, r := range s.m {
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func (s *memRevocationStore) DeleteRevocation(_ context.Context, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.m, id)
+	return nil
+}
+
+func TestRevoker(t *testing.T) {
+	ctx := context.Background()
+	store := &memRevocationStore{m: make(map[string]*rbacframe.Revocation)}
+	r := rbac.NewRevoker(store, time.Minute)
+	exp := float64(time.Now().Add(time.Hour).Unix())
+
+	t.Run("revoke a token, should only deny the jti", func(t *testing.T) {
+		assert.NoError(t, r.RevokeToken(ctx, "t1", "a", int64(exp)))
+		assert.True(t, r.Revoked(map[string]interface{}{"account": "a", "jti": "t1", "exp": exp}))
+		assert.False(t, r.Revoked(map[string]interface{}{"account": "a", "jti": "t2", "exp": exp}))
+		assert.Equal(t, rbac.ErrNoJTI, r.RevokeToken(ctx, "", "a", int64(exp)))
+	})
+
+	t.Run("revoke an account, should deny the tokens issued before", func(t *testing.T) {
+		before := rbacframe.NumericDate(time.Now().Add(-time.Second))
+		assert.NoError(t, r.RevokeAccount(ctx, "b"))
+		assert.NotEqual(t, int64(0), r.RevokeBefore("b"))
+		assert.True(t, r.Revoked(map[string]interface{}{"account": "b", "jti": "t3"}))
+		assert.True(t, r.Revoked(map[string]interface{}{"account": "b", "jti": "t3", "iat": before}))
+		after := rbacframe.NumericDate(time.Now().Add(time.Second))
+		assert.False(t, r.Revoked(map[string]interface{}{"account": "b", "jti": "t4", "iat": after}))
+		assert.False(t, r.Revoked(map[string]interface{}{"account": "c", "jti": "t5"}))
+	})
+
+	t.Run("revoke a role, should deny the tokens having it issued before", func(t *testing.T) {
+		before := rbacframe.NumericDate(time.Now().Add(-time.Second))
+		assert.NoError(t, r.RevokeRole(ctx, "developer"))
+		// the identities of the identity provider have no account
+		assert.True(t, r.Revoked(map[string]interface{}{"account": "oidc:d", "iat": before,
+			"roles": []interface{}{"viewer", "developer"}}))
+		assert.False(t, r.Revoked(map[string]interface{}{"account": "oidc:d", "iat": before,
+			"roles": []interface{}{"viewer"}}))
+		after := rbacframe.NumericDate(time.Now().Add(time.Second))
+		assert.False(t, r.Revoked(map[string]interface{}{"account": "oidc:d", "iat": after,
+			"roles": []interface{}{"developer"}}))
+	})
+
+	t.Run("another replica, should load the revocations and purge the expired", func(t *testing.T) {
+		assert.NoError(t, store.PutRevocation(ctx, rbacframe.NewTokenRevocation("old", "a", 1)))
+		replica := rbac.NewRevoker(store, time.Minute)
+		assert.NoError(t, replica.Sync(ctx))
+		assert.True(t, replica.Revoked(map[string]interface{}{"account": "a", "jti": "t1"}))
+		assert.Equal(t, r.RevokeBefore("b"), replica.RevokeBefore("b"))
+		assert.True(t, replica.Revoked(map[string]interface{}{"account": "oidc:d", "roles": []interface{}{"developer"}}))
+		rs, _ := store.ListRevocation(ctx)
+		assert.Equal(t, 3, len(rs))
+	})
+}
@@ -19,17 +19,29 @@ package rbac
 
 import (
 	"context"
-	"github.com/go-chassis/cari/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/rbacframe"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
 	"github.com/apache/servicecomb-service-center/server/service/rbac/dao"
 )
 
-var roleMap = map[string]*rbac.Role{}
+var roleMap = map[string]*rbacframe.Role{}
+
+//CreateRole creates the role if the role quota is not used up,
+//it returns *discovery.Error otherwise
+func CreateRole(ctx context.Context, r *rbacframe.Role) error {
+	release, errQuota := quota.Acquire(ctx, quota.NewApplyQuotaResource(quota.TypeRole, "", "", 1))
+	defer release()
+	if errQuota != nil {
+		return errQuota
+	}
+	return dao.CreateRole(ctx, r)
+}
 
 // Assign resources to admin role, admin role own all permissions
 func initAdminRole() {
-	roleMap["admin"] = &rbac.Role{
+	roleMap["admin"] = &rbacframe.Role{
 		Name:  "admin",
 		Perms: AdminPerms(),
 	}
@@ -41,7 +53,7 @@ func initAdminRole() {
 
 // Assign resources to developer role
 func initDevRole() {
-	roleMap["developer"] = &rbac.Role{
+	roleMap["developer"] = &rbacframe.Role{
 		Name:  "developer",
 		Perms: DevPerms(),
 	}
@@ -101,6 +101,12 @@ func (s *MicroServiceService) ModifySchemas(ctx context.Context, in *pb.ModifySc
 func (s *MicroServiceService) ModifySchema(ctx context.Context, request *pb.ModifySchemaRequest) (*pb.ModifySchemaResponse, error) {
 	domainProject := util.ParseDomainProject(ctx)
 	respErr := s.canModifySchema(ctx, domainProject, request)
+	if respErr == nil {
+		var release func()
+		res := quota.NewApplyQuotaResource(quota.TypeSchema, domainProject, request.ServiceId, 1)
+		release, respErr = quota.Acquire(ctx, res)
+		defer release()
+	}
 	if respErr != nil {
 		resp := &pb.ModifySchemaResponse{
 			Response: pb.CreateResponseWithSCErr(respErr),
@@ -129,12 +135,6 @@ func (s *MicroServiceService) canModifySchema(ctx context.Context, domainProject
 		return pb.NewError(pb.ErrInvalidParams, err.Error())
 	}
 
-	res := quota.NewApplyQuotaResource(quota.TypeSchema, domainProject, serviceID, 1)
-	errQuota := quota.Apply(ctx, res)
-	if errQuota != nil {
-		log.Errorf(errQuota, "update schema[%s/%s] failed, operator: %s", serviceID, schemaID, remoteIP)
-		return errQuota
-	}
 	if len(in.Summary) == 0 {
 		log.Warnf("schema[%s/%s]'s summary is empty, operator: %s", serviceID, schemaID, remoteIP)
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -36,6 +37,21 @@ func (s *MicroServiceService) AddTags(ctx context.Context, in *pb.AddServiceTags
 		}, nil
 	}
 
+	// the tags are overwritten as a whole
+	res := quota.NewApplyQuotaResource(quota.TypeTag, util.ParseDomainProject(ctx), in.ServiceId, int64(len(in.Tags)))
+	res.Overwrite = true
+	if errQuota := quota.Apply(ctx, res); errQuota != nil {
+		remoteIP := util.GetIPFromContext(ctx)
+		log.Errorf(errQuota, "add service[%s]'s tags %v failed, operator: %s", in.ServiceId, in.Tags, remoteIP)
+		resp := &pb.AddServiceTagsResponse{
+			Response: pb.CreateResponseWithSCErr(errQuota),
+		}
+		if errQuota.InternalError() {
+			return resp, errQuota
+		}
+		return resp, nil
+	}
+
 	return datasource.Instance().AddTags(ctx, in)
 }
 
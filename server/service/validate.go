@@ -31,15 +31,16 @@ import (
 var createAccountValidator = &validate.Validator{}
 var changePWDValidator = &validate.Validator{}
 var accountLoginValidator = &validate.Validator{}
+var passwordChecker = &validate.PasswordChecker{}
 
 func init() {
 	var roleRegex, _ = regexp.Compile(`^$|^(admin|developer|[a-zA-Z]\w{2,15})$`)
 	var accountRegex, _ = regexp.Compile(`^[a-zA-Z]\w{3,15}$`)
 	createAccountValidator.AddRule("Name", &validate.Rule{Regexp: accountRegex})
 	createAccountValidator.AddRule("Roles", &validate.Rule{Regexp: roleRegex})
-	createAccountValidator.AddRule("Password", &validate.Rule{Regexp: &validate.PasswordChecker{}})
+	createAccountValidator.AddRule("Password", &validate.Rule{Regexp: passwordChecker})
 
-	changePWDValidator.AddRule("Password", &validate.Rule{Regexp: &validate.PasswordChecker{}})
+	changePWDValidator.AddRule("Password", &validate.Rule{Regexp: passwordChecker})
 	changePWDValidator.AddRule("Name", &validate.Rule{Regexp: accountRegex})
 
 	accountLoginValidator.AddRule("TokenExpirationTime", &validate.Rule{Regexp: &validate.TokenExpirationTimeChecker{}})
@@ -121,6 +122,12 @@ func baseCheck(v interface{}) error {
 	}
 	return nil
 }
+
+// SetPasswordPolicy changes the complexity rule of the created and changed passwords
+func SetPasswordPolicy(p *validate.PasswordPolicy) {
+	passwordChecker.Policy = p
+}
+
 func ValidateCreateAccount(a *rbac.Account) error {
 	err := baseCheck(a)
 	if err != nil {
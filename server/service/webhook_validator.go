@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"regexp"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/pkg/validate"
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	"github.com/go-chassis/cari/discovery"
+)
+
+var createWebhookValidator validate.Validator
+
+var (
+	webhookURLRegex, _      = regexp.Compile(`^https?://\S+$`)
+	webhookResourceRegex, _ = regexp.Compile("^(" + util.StringJoin([]string{
+		notify.ResourceService, notify.ResourceInstance, notify.ResourceSchema, notify.ResourceRule}, "|") + ")$")
+	webhookActionRegex, _ = regexp.Compile("^(" + util.StringJoin([]string{
+		string(discovery.EVT_CREATE), string(discovery.EVT_UPDATE), string(discovery.EVT_DELETE)}, "|") + ")$")
+)
+
+func CreateWebhookValidator() *validate.Validator {
+	return createWebhookValidator.Init(func(v *validate.Validator) {
+		v.AddRule("Name", &validate.Rule{Max: 64, Regexp: nameRegex})
+		v.AddRule("URL", &validate.Rule{Min: 1, Max: 1024, Regexp: webhookURLRegex})
+		v.AddRule("Secret", &validate.Rule{Max: 256, Hide: true})
+
+		var filterValidator validate.Validator
+		filterValidator.AddRule("Resources", &validate.Rule{Regexp: webhookResourceRegex})
+		filterValidator.AddRule("Actions", &validate.Rule{Regexp: webhookActionRegex})
+		filterValidator.AddRule("AppID", &validate.Rule{Max: 160, Regexp: nameRegex})
+		filterValidator.AddRule("ServiceName", &validate.Rule{Max: 128, Regexp: nameRegex})
+		v.AddSub("Filter", &filterValidator)
+	})
+}
+
+func ValidateCreateWebhook(hook *webhook.Webhook) error {
+	err := baseCheck(hook)
+	if err != nil {
+		return err
+	}
+	return CreateWebhookValidator().Validate(hook)
+}
@@ -0,0 +1,319 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/backoff"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	nf "github.com/apache/servicecomb-service-center/pkg/notify"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+const (
+	Group = "__WEBHOOK_GROUP__"
+	// LeaderID is the datasource election of the dispatcher leader,
+	// only the leader delivers webhooks in a cluster
+	LeaderID = "webhook"
+
+	DefaultRetries         = 5
+	DefaultTimeout         = 10 * time.Second
+	DefaultQueueSize       = 1000
+	DefaultRefreshInterval = 30 * time.Second
+)
+
+type Options struct {
+	// Retries is the max retry times after the first delivery failed
+	Retries int
+	// Timeout is the timeout of one delivery
+	Timeout time.Duration
+	// QueueSize is the max number of events waiting for dispatching
+	QueueSize int
+	// RefreshInterval is the interval of reloading webhooks and electing leader
+	RefreshInterval time.Duration
+	Backoff         backoff.Backoff
+	Store           datasource.WebhookManager
+}
+
+// Dispatcher subscribes the RESOURCE events and posts them to the matched webhooks
+type Dispatcher struct {
+	nf.Subscriber
+	opts      Options
+	client    *rest.URLClient
+	queue     chan *notify.ResourceEvent
+	hooks     atomic.Value
+	leader    int32
+	goroutine *gopool.Pool
+	once      sync.Once
+}
+
+func (d *Dispatcher) OnMessage(evt nf.Event) {
+	re, ok := evt.(*notify.ResourceEvent)
+	if !ok || !d.IsLeader() {
+		return
+	}
+	select {
+	case d.queue <- re:
+	default:
+		log.Warnf("webhook queue is full, drop %s[%s] %s event", re.Resource, re.ResourceID, re.Action)
+	}
+}
+
+// IsLeader returns true if the dispatcher is the one delivering webhooks
+func (d *Dispatcher) IsLeader() bool {
+	return atomic.LoadInt32(&d.leader) == 1
+}
+
+func (d *Dispatcher) setLeader(b bool) {
+	var v int32
+	if b {
+		v = 1
+	}
+	if atomic.SwapInt32(&d.leader, v) != v {
+		log.Infof("webhook dispatcher leader changed to %v", b)
+	}
+}
+
+// Webhooks returns the cached webhooks
+func (d *Dispatcher) Webhooks() []*webhook.Webhook {
+	hooks, _ := d.hooks.Load().([]*webhook.Webhook)
+	return hooks
+}
+
+// SetWebhooks replaces the cached webhooks
+func (d *Dispatcher) SetWebhooks(hooks []*webhook.Webhook) {
+	d.hooks.Store(hooks)
+}
+
+// Refresh reloads webhooks from datasource
+func (d *Dispatcher) Refresh(ctx context.Context) error {
+	hooks, err := d.opts.Store.ListWebhook(ctx, "")
+	if err != nil {
+		return err
+	}
+	d.SetWebhooks(hooks)
+	return nil
+}
+
+func (d *Dispatcher) elect(ctx context.Context) {
+	// the lease lasts several refresh intervals, so the leader
+	// keeps the leadership even if one renewal failed
+	err := datasource.Instance().Campaign(ctx, &datasource.CampaignRequest{
+		ID:  LeaderID,
+		TTL: int64(3 * d.opts.RefreshInterval / time.Second),
+	})
+	if err != nil && err != datasource.ErrNotLeader {
+		log.Error("webhook dispatcher campaign failed", err)
+	}
+	d.setLeader(err == nil)
+}
+
+func (d *Dispatcher) refresh(ctx context.Context) {
+	d.elect(ctx)
+	if err := d.Refresh(ctx); err != nil {
+		log.Error("refresh webhooks failed", err)
+	}
+}
+
+func (d *Dispatcher) keepalive(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.opts.RefreshInterval):
+			d.refresh(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-d.queue:
+			d.Dispatch(evt)
+		}
+	}
+}
+
+// Dispatch delivers the event to all the matched webhooks asynchronously
+func (d *Dispatcher) Dispatch(evt *notify.ResourceEvent) {
+	payload := NewPayload(evt)
+	for _, hook := range d.Webhooks() {
+		if hook.Domain != payload.Domain || hook.Project != payload.Project ||
+			!hook.Filter.Match(payload) {
+			continue
+		}
+		h := hook
+		d.goroutine.Do(func(ctx context.Context) {
+			_ = d.Deliver(ctx, h, payload)
+		})
+	}
+}
+
+// Deliver posts the payload to webhook and retries with backoff,
+// the payload will be saved as a dead letter if all the retries failed
+func (d *Dispatcher) Deliver(ctx context.Context, hook *webhook.Webhook, payload *webhook.Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("marshal webhook payload failed", err)
+		return err
+	}
+	attempts := 0
+	for ; attempts <= d.opts.Retries; attempts++ {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d.opts.Backoff.Delay(attempts - 1)):
+			}
+		}
+		start := time.Now()
+		err = d.post(ctx, hook, payload, body)
+		metrics.ReportWebhookDelivered(payload.Resource, err, start)
+		if err == nil {
+			return nil
+		}
+		log.Errorf(err, "deliver event[%s] to webhook[%s] failed, attempts: %d", payload.ID, hook.ID, attempts+1)
+	}
+
+	metrics.ReportWebhookDeadLetter(payload.Resource)
+	letter := &webhook.DeadLetter{
+		ID:         util.GenerateUUID(),
+		WebhookID:  hook.ID,
+		Domain:     hook.Domain,
+		Project:    hook.Project,
+		Payload:    payload,
+		Attempts:   attempts,
+		Error:      err.Error(),
+		CreateTime: strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	if perr := d.opts.Store.PutDeadLetter(ctx, letter); perr != nil {
+		log.Errorf(perr, "save dead letter of event[%s] to webhook[%s] failed", payload.ID, hook.ID)
+	}
+	return err
+}
+
+func (d *Dispatcher) post(ctx context.Context, hook *webhook.Webhook, payload *webhook.Payload, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	headers := http.Header{}
+	headers.Set(rest.HeaderContentType, rest.ContentTypeJSON)
+	headers.Set(webhook.HeaderEvent, payload.Resource+"."+payload.Action)
+	headers.Set(webhook.HeaderDelivery, payload.ID)
+	if len(hook.Secret) > 0 {
+		headers.Set(webhook.HeaderSignature, webhook.Sign(hook.Secret, body))
+	}
+	resp, err := d.client.HTTPDoWithContext(ctx, http.MethodPost, hook.URL, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Start loads webhooks and starts dispatching events
+func (d *Dispatcher) Start() {
+	d.once.Do(func() {
+		d.refresh(context.Background())
+		d.goroutine.Do(d.loop).Do(d.keepalive)
+	})
+}
+
+func (d *Dispatcher) Stop() {
+	d.goroutine.Close(true)
+	if d.IsLeader() {
+		d.setLeader(false)
+		if err := datasource.Instance().Resign(context.Background(), &datasource.ResignRequest{ID: LeaderID}); err != nil {
+			log.Error("webhook dispatcher resign failed", err)
+		}
+	}
+}
+
+// NewPayload converts the resource event to webhook payload
+func NewPayload(evt *notify.ResourceEvent) *webhook.Payload {
+	domain, project := util.FromDomainProject(evt.DomainProject)
+	p := &webhook.Payload{
+		ID:         util.GenerateUUID(),
+		Resource:   evt.Resource,
+		Action:     evt.Action,
+		Domain:     domain,
+		Project:    project,
+		ResourceID: evt.ResourceID,
+		Timestamp:  evt.CreateAt().Unix(),
+		Data:       evt.Value,
+	}
+	if evt.Service != nil {
+		p.AppID = evt.Service.AppId
+		p.ServiceName = evt.Service.ServiceName
+		p.ServiceID = evt.Service.ServiceId
+	}
+	return p
+}
+
+func NewDispatcher(opts Options) *Dispatcher {
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = backoff.GetBackoff()
+	}
+	client, _ := rest.GetURLClient(rest.URLClientOption{
+		Compressed:     false,
+		VerifyPeer:     true,
+		RequestTimeout: opts.Timeout,
+	})
+	return &Dispatcher{
+		Subscriber: nf.NewSubscriber(notify.RESOURCE, notify.ResourceSubject, Group),
+		opts:       opts,
+		client:     client,
+		queue:      make(chan *notify.ResourceEvent, opts.QueueSize),
+		goroutine:  gopool.New(context.Background()),
+	}
+}
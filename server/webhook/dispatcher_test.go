@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/webhook"
+	"github.com/apache/servicecomb-service-center/server/notify"
+	. "github.com/apache/servicecomb-service-center/server/webhook"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+type noDelay struct{}
+
+func (noDelay) Delay(int) time.Duration { return time.Millisecond }
+
+type mockStore struct {
+	datasource.WebhookManager
+	mux     sync.Mutex
+	letters []*webhook.DeadLetter
+}
+
+func (s *mockStore) PutDeadLetter(_ context.Context, letter *webhook.DeadLetter) error {
+	s.mux.Lock()
+	s.letters = append(s.letters, letter)
+	s.mux.Unlock()
+	return nil
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	var (
+		calls  int
+		header http.Header
+		body   []byte
+	)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		header = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	store := &mockStore{}
+	d := NewDispatcher(Options{Retries: 2, Backoff: noDelay{}, Store: store})
+	hook := &webhook.Webhook{ID: "1", URL: server.URL, Secret: "secret", Domain: "default", Project: "default"}
+	evt := notify.NewResourceEvent(notify.ResourceInstance, string(pb.EVT_CREATE), "default/default", "ins1",
+		&pb.MicroService{ServiceId: "svc1", AppId: "app", ServiceName: "a"}, nil)
+	payload := NewPayload(evt)
+	assert.Equal(t, "default", payload.Project)
+	assert.Equal(t, "svc1", payload.ServiceID)
+	assert.Equal(t, "a", payload.ServiceName)
+
+	t.Run("deliver successfully, should be signed", func(t *testing.T) {
+		err := d.Deliver(context.Background(), hook, payload)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "instance.CREATE", header.Get(webhook.HeaderEvent))
+		assert.Equal(t, payload.ID, header.Get(webhook.HeaderDelivery))
+		assert.True(t, webhook.Verify("secret", body, header.Get(webhook.HeaderSignature)))
+	})
+
+	t.Run("deliver failed, should retry and save dead letter", func(t *testing.T) {
+		calls = 0
+		status = http.StatusInternalServerError
+		err := d.Deliver(context.Background(), hook, payload)
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 1, len(store.letters))
+		assert.Equal(t, "1", store.letters[0].WebhookID)
+		assert.Equal(t, 3, store.letters[0].Attempts)
+		assert.Equal(t, payload, store.letters[0].Payload)
+	})
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	ch := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ch <- r.URL.Path
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(Options{Backoff: noDelay{}, Store: &mockStore{}})
+	d.SetWebhooks([]*webhook.Webhook{
+		{ID: "1", URL: server.URL + "/all", Domain: "default", Project: "default"},
+		{ID: "2", URL: server.URL + "/service", Domain: "default", Project: "default",
+			Filter: &webhook.Filter{Resources: []string{notify.ResourceService}}},
+		{ID: "3", URL: server.URL + "/other", Domain: "other", Project: "default"},
+	})
+	d.Dispatch(notify.NewResourceEvent(notify.ResourceInstance, string(pb.EVT_DELETE), "default/default", "ins1", nil, nil))
+	select {
+	case path := <-ch:
+		assert.Equal(t, "/all", path)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+	select {
+	case path := <-ch:
+		t.Fatalf("unexpected delivery to %s", path)
+	case <-time.After(100 * time.Millisecond):
+	}
+	d.Stop()
+}
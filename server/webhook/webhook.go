@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"sync"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/notify"
+)
+
+var (
+	dispatcher *Dispatcher
+	once       sync.Once
+)
+
+// Enabled returns true if the webhook feature is enabled
+func Enabled() bool {
+	return config.GetBool("webhook.enable", false)
+}
+
+// Init starts the dispatcher if webhook is enabled
+func Init() {
+	if !Enabled() {
+		return
+	}
+	once.Do(func() {
+		dispatcher = NewDispatcher(Options{
+			Retries:         config.GetInt("webhook.retries", DefaultRetries),
+			Timeout:         config.GetDuration("webhook.timeout", DefaultTimeout),
+			QueueSize:       config.GetInt("webhook.queueSize", DefaultQueueSize),
+			RefreshInterval: config.GetDuration("webhook.refreshInterval", DefaultRefreshInterval),
+			Store:           datasource.Instance(),
+		})
+		if err := notify.Center().AddSubscriber(dispatcher); err != nil {
+			log.Error("add webhook dispatcher failed", err)
+			return
+		}
+		dispatcher.Start()
+		log.Info("webhook dispatcher started")
+	})
+}
+
+// Instance returns the dispatcher, it is nil if webhook is disabled
+func Instance() *Dispatcher {
+	return dispatcher
+}